@@ -0,0 +1,50 @@
+package captcha
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"loop_server/pkg/settings"
+)
+
+const (
+	DriverNone = "none" // 不启用人机验证，只靠退避和锁定限制暴力破解
+	DriverStub = "stub" // 固定答案，用于本地调试和测试
+)
+
+// Verifier 人机验证校验器，接入第三方验证码服务时实现该接口即可
+type Verifier interface {
+	Verify(ctx context.Context, token, ip string) (bool, error)
+}
+
+// InitVerifier 按 driver 创建校验器，未配置或配置不可用时返回 nil，登录时跳过人机验证，
+// 避免校验器拒绝所有 token 导致任何人都能通过几次错误密码锁死他人的密码登录
+func InitVerifier(c *settings.CaptchaConfig) Verifier {
+	if c == nil || c.Driver == "" || c.Driver == DriverNone {
+		slog.Warn("captcha verifier disabled")
+		return nil
+	}
+	switch c.Driver {
+	case DriverStub:
+		if c.StubAnswer == "" {
+			slog.Error("captcha stub_answer is empty, captcha verifier disabled")
+			return nil
+		}
+		return &StubVerifier{Answer: c.StubAnswer}
+	default:
+		slog.Error("unknown captcha driver, captcha verifier disabled", "driver", c.Driver)
+		return nil
+	}
+}
+
+// StubVerifier token 与配置的答案一致即通过，答案为空时全部拒绝
+type StubVerifier struct {
+	Answer string
+}
+
+func (s *StubVerifier) Verify(ctx context.Context, token, ip string) (bool, error) {
+	if s.Answer == "" {
+		return false, nil
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.Answer)) == 1, nil
+}
@@ -10,6 +10,14 @@ const (
 	FriendRequestStatusUntreated = 0 // 待处理
 	FriendRequestStatusAgree     = 1
 	FriendRequestStatusRefuse    = 2
+	FriendRequestStatusWithdraw  = 3 // 已撤回
+	FriendRequestStatusExpired   = 4 // 已过期
+)
+
+const (
+	FriendRequestExpireDays    = 7  // 好友请求默认过期天数
+	FriendRequestCooldownHours = 24 // 被拒绝后默认冷却小时数
+	FriendRequestDailyLimit    = 20 // 默认每日好友请求上限
 )
 
 const (
@@ -25,7 +33,29 @@ const (
 	WsMessageCmdGroupAnswer                    // 群聊answer
 	WsMessageCmdGroupIce                       // 群聊ice
 	WsMessageCmdCallInvitation                 // 呼叫邀请
+	WsMessageCmdFriendRequest                  // 新的好友请求
+	WsMessageCmdFriendRequestResult            // 好友请求被同意或拒绝
+	WsMessageCmdFriendRemoved                  // 被好友删除
 	WsMessageCmdRemind              = 100      //提醒
+	WsMessageCmdError               = 101      // 错误通知，data 为 dto.WsError
+	WsMessageCmdAuth                = 102      // 认证，客户端在 token 字段携带 access token，服务端回复 dto.WsAuthResult
+	WsMessageCmdReauth              = 103      // 服务端要求客户端刷新 token 后重新认证
+	WsMessageCmdSystemNotice        = 104      // 系统通知，data 为 dto.SystemNotice
+)
+
+const (
+	WsErrorReasonRateLimited     = "rate_limited"      // 发送过于频繁
+	WsErrorReasonUnauthorized    = "unauthorized"      // 认证失败
+	WsErrorReasonTokenExpired    = "token_expired"     // access token 已过期，需要重新认证
+	WsErrorReasonTokenNotRenewed = "token_not_renewed" // 重新认证的 token 没有延长过期时间，需要先刷新
+	WsErrorReasonReplaced        = "replaced"          // 同一用户建立了新连接，旧连接被关闭
+)
+
+const (
+	WsAuthTimeout       = 10 * time.Second // 建立连接或要求重新认证后等待认证帧的时间
+	WsReauthAhead       = time.Minute      // access token 过期前多久要求重新认证
+	WsCloseUnauthorized = 4001             // 认证失败或超时的关闭码
+	WsCloseReplaced     = 4002             // 同一用户建立了新连接，客户端收到后不再自动重连
 )
 
 const (
@@ -34,10 +64,188 @@ const (
 	GroupRoleOwner  = 3 // 群主
 )
 
+const (
+	GroupTypeNormal = 0 // 普通群
+	GroupTypeLarge  = 1 // 大群
+)
+
+// GroupTypeMaxMember 各群类型的成员上限
+var GroupTypeMaxMember = map[int]int{
+	GroupTypeNormal: 500,
+	GroupTypeLarge:  2000,
+}
+
+const (
+	GroupJoinPolicyOpen     = 0 // 任何人可直接加入
+	GroupJoinPolicyApproval = 1 // 需管理员审批
+	GroupJoinPolicyInvite   = 2 // 仅可邀请加入
+)
+
+const (
+	GroupJoinRequestStatusUntreated = 0 // 待处理
+	GroupJoinRequestStatusAgree     = 1
+	GroupJoinRequestStatusRefuse    = 2
+
+	GroupJoinRequestCooldownHours = 24 // 入群申请被拒绝后的冷却小时数
+)
+
+const (
+	GroupMessageSyncLimit    = 100 // 群消息拉取默认条数
+	GroupMessageSyncMaxLimit = 500 // 群消息拉取最大条数
+)
+
+const (
+	GroupActionEditInfo  = "edit_info"  // 修改群资料
+	GroupActionInvite    = "invite"     // 邀请成员
+	GroupActionRemove    = "remove"     // 移除成员
+	GroupActionMute      = "mute"       // 禁言
+	GroupActionAnnounce  = "announce"   // 发布群公告
+	GroupActionStartCall = "start_call" // 发起群通话
+	GroupActionAtAll     = "at_all"     // @所有人
+
+	GroupActionView          = "view"           // 查看群信息、成员
+	GroupActionManageAdmin   = "manage_admin"   // 设置、取消管理员
+	GroupActionTransfer      = "transfer"       // 转让群主
+	GroupActionDissolve      = "dissolve"       // 解散群
+	GroupActionSetPermission = "set_permission" // 修改群权限配置
+	GroupActionUpgrade       = "upgrade"        // 升级群类型
+	GroupActionDiscovery     = "discovery"      // 修改公开、加群方式等设置
+)
+
+// GroupPermissionDefault 可由群主配置的群操作及其默认最低角色
+var GroupPermissionDefault = map[string]uint{
+	GroupActionEditInfo:  GroupRoleAdmin,
+	GroupActionInvite:    GroupRoleMember,
+	GroupActionRemove:    GroupRoleAdmin,
+	GroupActionMute:      GroupRoleAdmin,
+	GroupActionAnnounce:  GroupRoleAdmin,
+	GroupActionStartCall: GroupRoleMember,
+	GroupActionAtAll:     GroupRoleAdmin,
+}
+
+// GroupPermissionFixed 不可配置的群操作及其最低角色
+var GroupPermissionFixed = map[string]uint{
+	GroupActionView:          GroupRoleMember,
+	GroupActionManageAdmin:   GroupRoleOwner,
+	GroupActionTransfer:      GroupRoleOwner,
+	GroupActionDissolve:      GroupRoleOwner,
+	GroupActionSetPermission: GroupRoleOwner,
+	GroupActionUpgrade:       GroupRoleOwner,
+	GroupActionDiscovery:     GroupRoleOwner,
+}
+
 const (
 	AckGroupMessage = true
 )
 
+const (
+	AckCodeRejected          = 1 // 消息被拒收
+	AckCodeStrangerForbidden = 2 // 对方不接收陌生人消息
+	AckCodeReceiverNotExist  = 3 // 接收者不存在
+	AckCodeNotGroupMember    = 4 // 发送者不是群成员
+	AckCodeContentRejected   = 5 // 内容包含违规信息
+	AckCodeMuted             = 6 // 发送者被禁言
+)
+
+const (
+	AccountDeletionGraceDays     = 7                // 默认注销冷静期天数
+	AccountDeletionCheckInterval = time.Hour        // 注销任务检查间隔
+	AccountDeletionBatchSize     = 100              // 每次处理的注销用户数
+	AccountExportDir             = "export"         // 默认数据导出目录
+	AccountExportTTL             = 24 * time.Hour   // 导出文件有效期
+	AccountExportLockTTL         = 10 * time.Minute // 导出中标记的有效期，防止导出异常退出后无法重新申请
+	DeletedUserNickname          = "已注销用户"          // 注销后的用户昵称
+)
+
+const (
+	AccountExportStatusPending = "pending" // 导出中
+	AccountExportStatusDone    = "done"    // 已完成
+	AccountExportStatusFailed  = "failed"  // 导出失败
+)
+
+const (
+	VerifySceneRegister      = "register"       // 注册
+	VerifySceneLogin         = "login"          // 验证码登录
+	VerifySceneResetPassword = "reset_password" // 重置密码
+)
+
+const (
+	VerifyCodeLength         = 6               // 验证码位数
+	VerifyCodeTTL            = 5 * time.Minute // 验证码有效期
+	VerifyCodeMaxAttempts    = 5               // 每个验证码最多校验次数
+	VerifyCodeResendInterval = time.Minute     // 同一手机号重发间隔
+	VerifyCodeDailyLimit     = 10              // 同一手机号每日发送上限
+)
+
+const (
+	TotpIssuer                = "Loop"           // 认证器中显示的应用名
+	TotpEnrollTTL             = 10 * time.Minute // 绑定两步验证的确认时限
+	LoginChallengeTTL         = 5 * time.Minute  // 两步验证挑战有效期
+	LoginChallengeMaxAttempts = 5                // 每个挑战最多校验次数
+	RecoveryCodeCount         = 10               // 恢复码数量
+	RecoveryCodeBytes         = 10               // 每个恢复码的随机字节数
+)
+
+const (
+	RateLimitScopePublic         = "public"           // 未登录接口，按 IP 限流
+	RateLimitScopeUser           = "user"             // 登录后的接口，按用户和 IP 限流
+	RateLimitScopeLLM            = "llm"              // 大模型接口
+	RateLimitScopeIm             = "im"               // im 相关 HTTP 接口
+	RateLimitScopeWsPrivate      = "ws_private"       // ws 私聊消息
+	RateLimitScopeWsGroup        = "ws_group"         // ws 群聊消息
+	RateLimitScopeWsSignal       = "ws_signal"        // ws 音视频信令
+	RateLimitScopeWsHeartbeatAck = "ws_heartbeat_ack" // ws 心跳和应答
+)
+
+// WsCmdRateLimitScope ws 各命令对应的限流范围，不在表中的命令不限流
+var WsCmdRateLimitScope = map[int]string{
+	WsMessageCmdHeartbeat:           RateLimitScopeWsHeartbeatAck,
+	WsMessageCmdAck:                 RateLimitScopeWsHeartbeatAck,
+	WsMessageCmdPrivateMessage:      RateLimitScopeWsPrivate,
+	WsMessageCmdGroupMessage:        RateLimitScopeWsGroup,
+	WsMessageCmdPrivateOffer:        RateLimitScopeWsSignal,
+	WsMessageCmdPrivateAnswer:       RateLimitScopeWsSignal,
+	WsMessageCmdPrivateIce:          RateLimitScopeWsSignal,
+	WsMessageCmdPrivateHangUp:       RateLimitScopeWsSignal,
+	WsMessageCmdGroupInitiatorOffer: RateLimitScopeWsSignal,
+	WsMessageCmdGroupIce:            RateLimitScopeWsSignal,
+}
+
+const (
+	LoginCaptchaThreshold = 3               // 默认连续失败多少次后需要人机验证
+	LoginLockThreshold    = 10              // 默认连续失败多少次后锁定
+	LoginLockMinutes      = 30              // 默认锁定分钟数
+	LoginIpMaxFailures    = 100             // 默认同一 IP 在统计窗口内的失败上限
+	LoginFailureWindow    = time.Hour       // 失败次数统计窗口
+	LoginBackoffBase      = time.Second     // 退避时间基数，每多失败一次翻倍
+	LoginBackoffMax       = 5 * time.Minute // 最长退避时间
+)
+
+const LoginGuardOperatorPrefix = "operator:" // 操作员登录在登录保护中的账号前缀，与手机号区分
+
+const (
+	SessionTouchInterval = time.Minute // 会话最近使用时间的更新间隔
+	SessionDeviceUnknown = "未知设备"      // 客户端未上报设备名时的默认值
+)
+
+const (
+	PrivacyScopeEveryone = 0 // 所有人
+	PrivacyScopeFriends  = 1 // 仅好友，用于好友请求时表示有共同好友或同群的用户
+	PrivacyScopeNobody   = 2 // 任何人都不可以
+)
+
+const (
+	FriendCategoryMaxCount = 20 // 好友分组数量上限
+)
+
+const (
+	FriendRecommendLimit        = 20               // 默认推荐数量
+	FriendRecommendMaxLimit     = 50               // 最大推荐数量
+	FriendRecommendCacheTTL     = 30 * time.Minute // 推荐结果缓存时间
+	FriendRecommendMutualWeight = 3                // 共同好友权重，共同群权重为 1
+	FriendRecommendSourceLimit  = 1000             // 每种来源最多取的候选关系数
+)
+
 const (
 	WsParticipantInitiatorYes = true
 	WsParticipantInitiatorNo  = false
@@ -53,12 +261,162 @@ const (
 )
 
 const (
-	GroupMessageTypeText    = 0
-	GroupMessageTypePicture = 1
-	GroupMessageTypeFile    = 2
-	GroupMessageTypeVoice   = 3
-	GroupMessageTypeAudio   = 4
-	GroupMessageTypeInvite  = 5 // 邀请入群
+	GroupMessageTypeText     = 0
+	GroupMessageTypePicture  = 1
+	GroupMessageTypeFile     = 2
+	GroupMessageTypeVoice    = 3
+	GroupMessageTypeAudio    = 4
+	GroupMessageTypeInvite   = 5 // 邀请入群
+	GroupMessageTypeDissolve = 6 // 群已解散
+)
+
+const (
+	GroupRetentionArchive = "archive" // 解散后保留消息只读归档
+	GroupRetentionPurge   = "purge"   // 解散后清除消息
+)
+
+const (
+	ModerationActionPass   = "pass"   // 未命中
+	ModerationActionMask   = "mask"   // 命中的词替换为 *
+	ModerationActionFlag   = "flag"   // 原样发送，进入人工审核
+	ModerationActionReject = "reject" // 拒绝发送或保存
+)
+
+const (
+	ModerationScenePrivateMessage = "private_message"
+	ModerationSceneGroupMessage   = "group_message"
+	ModerationSceneNickname       = "nickname"
+	ModerationSceneSignature      = "signature"
+	ModerationSceneGroupName      = "group_name"
+	ModerationSceneGroupDescribe  = "group_describe"
+)
+
+const (
+	ModerationSourceWord       = "word"       // 敏感词命中
+	ModerationSourceClassifier = "classifier" // 分类器判定
+)
+
+const (
+	ModerationReviewPending  = 0 // 待审核
+	ModerationReviewApproved = 1 // 审核通过，内容无问题
+	ModerationReviewRemoved  = 2 // 确认违规，群消息会被删除，其他场景只记录结论，需要时通过举报处理或封禁处置
+)
+
+const (
+	ReportTargetUser           = "user"
+	ReportTargetGroup          = "group"
+	ReportTargetPrivateMessage = "private_message"
+	ReportTargetGroupMessage   = "group_message"
+)
+
+const (
+	ReportStatusPending   = 0 // 待处理
+	ReportStatusResolved  = 1 // 已处理
+	ReportStatusDismissed = 2 // 已驳回
+)
+
+const (
+	ReportActionWarn          = "warn"           // 向被举报用户发送警告
+	ReportActionMute          = "mute"           // 禁言被举报用户
+	ReportActionBan           = "ban"            // 封禁被举报用户
+	ReportActionDissolveGroup = "dissolve_group" // 解散被举报的群
+	ReportActionDeleteMessage = "delete_message" // 删除被举报的群消息
+	ReportActionDismiss       = "dismiss"        // 驳回举报
+)
+
+const (
+	ReportSnapshotServer     = "server"                   // 服务端根据存储生成的快照
+	ReportSnapshotClient     = "client"                   // 客户端提交的快照，私聊消息不在服务端存储
+	ReportContextMessages    = 10                         // 群消息快照前后各保留的消息数
+	ReportPermanentDuration  = 100 * 365 * 24 * time.Hour // 时长为 0 的禁言、封禁按永久处理
+	SystemNoticeTypeWarn     = "warn"                     // 违规警告
+	SystemNoticeWarnTemplate = "你的账号因违反社区规范被警告，请遵守相关规定"
+)
+
+const (
+	OperatorRoleViewer     = "viewer"     // 只读，查看用户、群、消息和统计
+	OperatorRoleModerator  = "moderator"  // 可以封禁用户、处理举报和审核
+	OperatorRoleSuperadmin = "superadmin" // 可以管理操作员账号
+)
+
+// OperatorRoleLevel 操作员角色等级，高等级拥有低等级的全部权限
+var OperatorRoleLevel = map[string]int{
+	OperatorRoleViewer:     1,
+	OperatorRoleModerator:  2,
+	OperatorRoleSuperadmin: 3,
+}
+
+const (
+	OperatorSessionExpiration = 12 * time.Hour // 操作员会话有效期
+)
+
+const (
+	LoginMethodPassword  = "password"
+	LoginMethodCode      = "code"
+	LoginMethodTwoFactor = "2fa"
+)
+
+const (
+	AuditActorOperator = "operator"
+	AuditActorUser     = "user"
+)
+
+const ServerShutdownTimeout = 10 * time.Second // 退出时等待处理中请求完成的最长时间
+
+const (
+	AuditQueueSize     = 4096        // 异步写入队列长度，队列满时改为同步写入
+	AuditBatchSize     = 200         // 每批最多写入的条数
+	AuditFlushInterval = time.Second // 不足一批时的最长等待时间
+	AuditRetryDelay    = time.Second // 写入失败后重试前的等待时间
+)
+
+const (
+	AuditActionOperatorLogin    = "operator.login"
+	AuditActionOperatorLogout   = "operator.logout"
+	AuditActionOperatorCreate   = "operator.create"
+	AuditActionOperatorUpdate   = "operator.update"
+	AuditActionUserUnlock       = "user.unlock"
+	AuditActionUserBan          = "user.ban"
+	AuditActionUserUnban        = "user.unban"
+	AuditActionUserForceLogout  = "user.force_logout"
+	AuditActionModerationReview = "moderation.resolve"
+	AuditActionReportHandle     = "report.handle"
+
+	AuditActionUserLogin          = "user.login"
+	AuditActionUserLoginFailed    = "user.login_failed"
+	AuditActionUserTokenRefresh   = "user.token_refresh"
+	AuditActionUserTokenReused    = "user.token_reused"
+	AuditActionUserPasswordChange = "user.password_change"
+	AuditActionUserPasswordReset  = "user.password_reset"
+	AuditActionUserTotpEnable     = "user.totp_enable"
+	AuditActionUserTotpDisable    = "user.totp_disable"
+	AuditActionUserSessionRevoke  = "user.session_revoke"
+	AuditActionGroupTransferOwner = "group.transfer_owner"
+	AuditActionGroupAddAdmin      = "group.add_admin"
+	AuditActionGroupDeleteAdmin   = "group.delete_admin"
+	AuditActionGroupDeleteMember  = "group.delete_member"
+)
+
+const (
+	AuditTargetOperator         = "operator"
+	AuditTargetUser             = "user"
+	AuditTargetPhone            = "phone"
+	AuditTargetModerationReview = "moderation_review"
+	AuditTargetReport           = "report"
+	AuditTargetGroup            = "group"
+)
+
+const (
+	ModerationClassifierLLM     = "llm"            // 使用配置的大模型分类
+	ModerationClassifyTimeout   = 15 * time.Second // 单次分类超时时间
+	ModerationClassifyMinLength = 4                // 短于该长度的文本不调用分类器
+	ModerationClassifyWorkers   = 4                // 同时调用分类器的数量
+	ModerationClassifyQueueSize = 256              // 待分类队列长度，队列满时丢弃，避免消息高峰耗尽模型额度
+)
+
+const (
+	PromptModeration = "你是内容审核员，判断用户发布的内容是否包含色情、暴力、赌博、诈骗、政治敏感或辱骂等违规信息。" +
+		"只输出 JSON，不要输出其他内容，格式：{\"flagged\":true或false,\"label\":\"违规类型，未违规时为空\"}"
 )
 
 const (
@@ -3,8 +3,56 @@ package consts
 import "errors"
 
 var (
-	ErrPartUserNotExist = errors.New("部分用户不存在")
-	ErrNoPermission     = errors.New("无权限")
+	ErrPartUserNotExist         = errors.New("部分用户不存在")
+	ErrNoPermission             = errors.New("无权限")
+	ErrInvalidPermission        = errors.New("无效的群权限配置")
+	ErrGroupFull                = errors.New("群成员已达上限")
+	ErrInvalidGroupType         = errors.New("无效的群类型")
+	ErrGroupJoinForbidden       = errors.New("该群不允许主动加入")
+	ErrGroupConfirmMismatch     = errors.New("群名称确认不一致")
+	ErrFriendCategoryNotExist   = errors.New("好友分组不存在")
+	ErrFriendCategoryLimit      = errors.New("好友分组数量已达上限")
+	ErrFriendRequestPending     = errors.New("好友请求已发送，请等待对方处理")
+	ErrFriendRequestCooldown    = errors.New("好友请求被拒绝，请稍后再试")
+	ErrFriendRequestLimit       = errors.New("今日好友请求次数已达上限")
+	ErrFriendRequestNotExist    = errors.New("好友请求不存在或已失效")
+	ErrFriendRequestForbidden   = errors.New("对方设置了不允许添加好友")
+	ErrPasswordError            = errors.New("密码错误")
+	ErrAccountExportPending     = errors.New("数据正在导出，请稍后")
+	ErrAccountExportNotExist    = errors.New("导出文件不存在或已过期")
+	ErrVerifyCodeInvalid        = errors.New("验证码错误或已过期")
+	ErrVerifyCodeTooFrequent    = errors.New("验证码发送过于频繁")
+	ErrVerifyCodeLimit          = errors.New("今日验证码发送次数已达上限")
+	ErrPhoneNotExist            = errors.New("手机号未注册")
+	ErrPhoneExist               = errors.New("手机号已被注册")
+	ErrTwoFactorEnabled         = errors.New("已开启两步验证")
+	ErrTwoFactorNotEnabled      = errors.New("未开启两步验证")
+	ErrTwoFactorEnrollExpired   = errors.New("两步验证绑定已过期，请重新获取")
+	ErrTwoFactorCodeInvalid     = errors.New("动态码或恢复码错误")
+	ErrLoginChallengeInvalid    = errors.New("登录验证已过期，请重新登录")
+	ErrSessionNotExist          = errors.New("会话不存在或已失效")
+	ErrRefreshTokenInvalid      = errors.New("无效的refresh token")
+	ErrRefreshTokenReused       = errors.New("refresh token 被重复使用，请重新登录")
+	ErrAccountLocked            = errors.New("登录失败次数过多，账号已临时锁定")
+	ErrLoginTooFrequent         = errors.New("登录尝试过于频繁，请稍后再试")
+	ErrCaptchaRequired          = errors.New("请完成人机验证")
+	ErrCaptchaInvalid           = errors.New("人机验证未通过")
+	ErrRateLimited              = errors.New("请求过于频繁，请稍后再试")
+	ErrWsUnauthorized           = errors.New("ws 认证失败")
+	ErrContentRejected          = errors.New("内容包含违规信息")
+	ErrModerationReviewNotExist = errors.New("审核记录不存在或已处理")
+	ErrReportTargetNotExist     = errors.New("举报对象不存在")
+	ErrReportPending            = errors.New("已举报，请等待处理")
+	ErrReportNotExist           = errors.New("举报不存在")
+	ErrReportActionInvalid      = errors.New("该举报不支持此操作")
+	ErrAccountBanned            = errors.New("账号已被封禁")
+	ErrOperatorLoginFailed      = errors.New("用户名或密码错误")
+	ErrOperatorExist            = errors.New("操作员已存在")
+	ErrOperatorNotExist         = errors.New("操作员不存在")
+	ErrUserNotExist             = errors.New("用户不存在")
+	ErrGroupNotExist            = errors.New("群不存在")
+	ErrMessageNotExist          = errors.New("消息不存在")
+	ErrGroupJoinRequestCooldown = errors.New("入群申请被拒绝，请稍后再试")
 )
 
 const (
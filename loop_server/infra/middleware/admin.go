@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"loop_server/infra/consts"
+	"loop_server/infra/redis"
+	"loop_server/infra/vars"
+	"loop_server/pkg/request"
+	"loop_server/pkg/response"
+	"strconv"
+)
+
+// AdminAuthMiddleware 管理接口认证，校验 Authorization 中的操作员会话 token
+func AdminAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := extractAccessToken(c)
+		if token == "" {
+			response.Fail(c, response.CodeInvalidToken)
+			c.Abort()
+			return
+		}
+		values, err := vars.Redis.HMGet(c, redis.GetOperatorSessionKey(token), "operator_id", "role").Result()
+		if err != nil || values[0] == nil || values[1] == nil {
+			response.Fail(c, response.CodeInvalidToken)
+			c.Abort()
+			return
+		}
+		operatorId, err := strconv.ParseUint(fmt.Sprint(values[0]), 10, 64)
+		if err != nil {
+			response.Fail(c, response.CodeInvalidToken)
+			c.Abort()
+			return
+		}
+		c.Set(request.CtxOperatorIDKey, uint(operatorId))
+		c.Set(request.CtxOperatorRoleKey, fmt.Sprint(values[1]))
+		c.Next()
+	}
+}
+
+// AdminRoleMiddleware 校验操作员角色不低于 role
+func AdminRoleMiddleware(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if consts.OperatorRoleLevel[c.GetString(request.CtxOperatorRoleKey)] < consts.OperatorRoleLevel[role] {
+			response.Fail(c, response.CodeNoPermission)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
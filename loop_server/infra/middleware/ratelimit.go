@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"loop_server/infra/ratelimit"
+	"loop_server/pkg/request"
+	"loop_server/pkg/response"
+	"math"
+	"strconv"
+)
+
+// RateLimit 按范围限流，登录后的请求同时按用户和 IP 计数，需放在认证中间件之后
+func RateLimit(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subjects := []string{"ip:" + c.ClientIP()}
+		if userId := request.GetCurrentUser(c); userId != 0 {
+			subjects = append(subjects, fmt.Sprintf("user:%d", userId))
+		}
+		if ok, wait := ratelimit.Allow(c, scope, subjects...); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			response.Fail(c, response.CodeRateLimited)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/openai"
+	"loop_server/infra/consts"
+	"loop_server/pkg/settings"
+	"strings"
+)
+
+// Classifier 内容分类器，在敏感词检查通过后异步调用，命中时进入人工审核
+type Classifier interface {
+	Classify(ctx context.Context, text string) (flagged bool, label string, err error)
+}
+
+// InitClassifier 根据配置选择分类器，未配置时返回 nil 表示不启用
+func InitClassifier(c *settings.ModerationConfig, llm *openai.LLM) Classifier {
+	if c == nil || c.Classifier != consts.ModerationClassifierLLM || llm == nil {
+		return nil
+	}
+	return &LLMClassifier{llm: llm}
+}
+
+// LLMClassifier 使用配置的大模型判断内容是否违规
+type LLMClassifier struct {
+	llm *openai.LLM
+}
+
+func (l *LLMClassifier) Classify(ctx context.Context, text string) (bool, string, error) {
+	content := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, consts.PromptModeration),
+		llms.TextParts(llms.ChatMessageTypeHuman, text),
+	}
+	resp, err := l.llm.GenerateContent(ctx, content, llms.WithTemperature(0))
+	if err != nil || len(resp.Choices) == 0 {
+		return false, "", err
+	}
+	var result struct {
+		Flagged bool   `json:"flagged"`
+		Label   string `json:"label"`
+	}
+	answer := strings.TrimSpace(resp.Choices[0].Content)
+	answer = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(answer, "```json"), "```"), "```")
+	if err := json.Unmarshal([]byte(strings.TrimSpace(answer)), &result); err != nil {
+		return false, "", err
+	}
+	return result.Flagged, result.Label, nil
+}
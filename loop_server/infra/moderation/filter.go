@@ -0,0 +1,180 @@
+package moderation
+
+import (
+	"bufio"
+	"github.com/fsnotify/fsnotify"
+	"log/slog"
+	"loop_server/infra/consts"
+	"loop_server/pkg/ahocorasick"
+	"loop_server/pkg/settings"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// actionLevel 多个规则同时命中时取级别最高的处理方式
+var actionLevel = map[string]int{
+	consts.ModerationActionPass:   0,
+	consts.ModerationActionMask:   1,
+	consts.ModerationActionFlag:   2,
+	consts.ModerationActionReject: 3,
+}
+
+type rule struct {
+	word   string
+	action string
+}
+
+// Result 敏感词检查结果
+type Result struct {
+	Action string   // 最终处理方式
+	Text   string   // 处理后的文本，mask 规则命中的词替换为 *
+	Words  []string // 命中的敏感词
+}
+
+// Filter 敏感词过滤器，词库文件变化时自动重新加载
+type Filter struct {
+	mu      sync.RWMutex
+	matcher *ahocorasick.Matcher
+	rules   []rule
+}
+
+func InitFilter(c *settings.ModerationConfig) *Filter {
+	f := &Filter{matcher: ahocorasick.New(nil)}
+	if c == nil || c.WordFile == "" {
+		return f
+	}
+	if err := f.Load(c.WordFile, c.DefaultAction); err != nil {
+		slog.Error("infra/moderation/filter.go InitFilter load word file err:", "err", err)
+	}
+	go f.watch(c.WordFile, c.DefaultAction)
+	return f
+}
+
+// Load 加载词库，每行一个词，可用 "词|处理方式" 指定处理方式，# 开头为注释
+func (f *Filter) Load(path, defaultAction string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, ok := actionLevel[defaultAction]; !ok || defaultAction == consts.ModerationActionPass {
+		defaultAction = consts.ModerationActionMask
+	}
+	var rules []rule
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		word, action, _ := strings.Cut(line, "|")
+		word, action = normalize(strings.TrimSpace(word)), strings.TrimSpace(action)
+		if word == "" {
+			continue
+		}
+		if _, ok := actionLevel[action]; !ok || action == consts.ModerationActionPass {
+			action = defaultAction
+		}
+		rules = append(rules, rule{word: word, action: action})
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	patterns := make([]string, len(rules))
+	for i, r := range rules {
+		patterns[i] = r.word
+	}
+	matcher := ahocorasick.New(patterns)
+	f.mu.Lock()
+	f.matcher, f.rules = matcher, rules
+	f.mu.Unlock()
+	slog.Info("moderation word list loaded", "path", path, "count", len(rules))
+	return nil
+}
+
+// watch 监听词库所在目录，兼容编辑器先写临时文件再重命名的保存方式
+func (f *Filter) watch(path, defaultAction string) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		slog.Error("infra/moderation/filter.go watch fsnotify.NewWatcher err:", "err", err)
+		return
+	}
+	defer watcher.Close()
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		slog.Error("infra/moderation/filter.go watch watcher.Add err:", "err", err)
+		return
+	}
+	target := filepath.Clean(path)
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != target || !event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+				continue
+			}
+			if err := f.Load(path, defaultAction); err != nil {
+				slog.Error("infra/moderation/filter.go watch reload err:", "err", err)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Error("infra/moderation/filter.go watch err:", "err", err)
+		}
+	}
+}
+
+// Check 检查文本，匹配时忽略大小写和全角半角差异
+func (f *Filter) Check(text string) *Result {
+	res := &Result{Action: consts.ModerationActionPass, Text: text}
+	if text == "" {
+		return res
+	}
+	f.mu.RLock()
+	matcher, rules := f.matcher, f.rules
+	f.mu.RUnlock()
+
+	origin := []rune(text)
+	matches := matcher.Find([]rune(normalize(text)))
+	if len(matches) == 0 {
+		return res
+	}
+	seen := make(map[int]bool, len(matches))
+	for _, m := range matches {
+		r := rules[m.Index]
+		if actionLevel[r.action] > actionLevel[res.Action] {
+			res.Action = r.action
+		}
+		if r.action == consts.ModerationActionMask {
+			for i := m.Start; i < m.End; i++ {
+				origin[i] = '*'
+			}
+		}
+		if !seen[m.Index] {
+			seen[m.Index] = true
+			res.Words = append(res.Words, r.word)
+		}
+	}
+	res.Text = string(origin)
+	return res
+}
+
+// normalize 转小写并将全角字符转为半角，逐个 rune 替换以保证下标与原文一致
+func normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '　':
+			r = ' '
+		case r >= '！' && r <= '～':
+			r -= 0xfee0
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
@@ -0,0 +1,90 @@
+package moderation
+
+import (
+	"loop_server/infra/consts"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testWords = `# 注释行
+敏感|mask
+感词|mask
+spam|reject
+广告|flag
+badword
+unknown|block
+`
+
+func newTestFilter(t *testing.T, words, defaultAction string) *Filter {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte(words), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f := InitFilter(nil)
+	if err := f.Load(path, defaultAction); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestFilterCheck(t *testing.T) {
+	f := newTestFilter(t, testWords, consts.ModerationActionMask)
+	tests := []struct {
+		name  string
+		text  string
+		want  string
+		out   string
+		words []string
+	}{
+		{name: "empty text", text: "", want: consts.ModerationActionPass, out: "", words: nil},
+		{name: "no match", text: "hello 世界", want: consts.ModerationActionPass, out: "hello 世界", words: nil},
+		{name: "mask multibyte word", text: "这是敏感内容", want: consts.ModerationActionMask, out: "这是**内容", words: []string{"敏感"}},
+		{name: "overlapping mask words", text: "敏感词", want: consts.ModerationActionMask, out: "***", words: []string{"敏感", "感词"}},
+		{name: "ignore case", text: "SpAm here", want: consts.ModerationActionReject, out: "SpAm here", words: []string{"spam"}},
+		{name: "full width matches half width", text: "ｓｐａｍ", want: consts.ModerationActionReject, out: "ｓｐａｍ", words: []string{"spam"}},
+		{name: "mask keeps full width offsets", text: "ＢａｄＷｏｒｄ！", want: consts.ModerationActionMask, out: "*******！", words: []string{"badword"}},
+		{name: "flag keeps text", text: "看广告", want: consts.ModerationActionFlag, out: "看广告", words: []string{"广告"}},
+		{name: "highest action wins", text: "敏感 spam 广告", want: consts.ModerationActionReject, out: "** spam 广告", words: []string{"敏感", "spam", "广告"}},
+		{name: "repeated word reported once", text: "敏感敏感", want: consts.ModerationActionMask, out: "****", words: []string{"敏感"}},
+		{name: "unknown action falls back to default", text: "unknown", want: consts.ModerationActionMask, out: "*******", words: []string{"unknown"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := f.Check(tt.text)
+			if res.Action != tt.want || res.Text != tt.out || !reflect.DeepEqual(res.Words, tt.words) {
+				t.Errorf("Check(%q) = {%s %q %v}, want {%s %q %v}", tt.text, res.Action, res.Text, res.Words, tt.want, tt.out, tt.words)
+			}
+		})
+	}
+}
+
+func TestFilterLoadDefaultAction(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultAction string
+		want          string
+	}{
+		{name: "configured default", defaultAction: consts.ModerationActionReject, want: consts.ModerationActionReject},
+		{name: "empty falls back to mask", defaultAction: "", want: consts.ModerationActionMask},
+		{name: "pass falls back to mask", defaultAction: consts.ModerationActionPass, want: consts.ModerationActionMask},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTestFilter(t, "badword\n", tt.defaultAction)
+			if got := f.Check("badword").Action; got != tt.want {
+				t.Errorf("Check action = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterWithoutWordList(t *testing.T) {
+	f := InitFilter(nil)
+	res := f.Check("任意内容")
+	if res.Action != consts.ModerationActionPass || res.Text != "任意内容" || res.Words != nil {
+		t.Errorf("Check without word list = %+v, want pass", res)
+	}
+}
@@ -29,10 +29,34 @@ func autoMigrate(db *gorm.DB) error {
 		&po.Group{},
 		&po.GroupShip{},
 		&po.GroupMessage{},
+		&po.GroupPermission{},
+		&po.GroupJoinRequest{},
+		&po.FriendBlock{},
+		&po.FriendCategory{},
+		&po.UserRecoveryCode{},
+		&po.ModerationReview{},
+		&po.Report{},
+		&po.ReportAction{},
+		&po.Operator{},
+		&po.AuditLog{},
 		// 如果有其他模型，继续添加
 		// &po.OtherModel{},
 	}
 
+	// 好友请求改为保留历史记录，删除旧的唯一索引
+	if db.Migrator().HasIndex(&po.FriendRequest{}, "idx_requester_id_recipient_id") {
+		if err := db.Migrator().DropIndex(&po.FriendRequest{}, "idx_requester_id_recipient_id"); err != nil {
+			return err
+		}
+	}
+
+	// 恢复码唯一索引改为按用户，删除旧的全局唯一索引
+	if db.Migrator().HasIndex(&po.UserRecoveryCode{}, "idx_user_recovery_code_code_hash") {
+		if err := db.Migrator().DropIndex(&po.UserRecoveryCode{}, "idx_user_recovery_code_code_hash"); err != nil {
+			return err
+		}
+	}
+
 	// 循环迁移所有模型
 	for _, model := range models {
 		if err := db.AutoMigrate(model); err != nil {
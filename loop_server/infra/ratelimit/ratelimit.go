@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"log/slog"
+	"loop_server/infra/redis"
+	"loop_server/infra/vars"
+	"time"
+)
+
+// Rule 滑动窗口限流规则，Window 内最多允许 Limit 次请求
+type Rule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// 基于 zset 的滑动窗口，返回 0 表示放行，否则返回需要等待的毫秒数
+const slidingWindowScript = `
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+if redis.call('ZCARD', key) >= limit then
+    local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+    local wait = tonumber(oldest[2]) + window - now
+    if wait < 1 then
+        wait = 1
+    end
+    return wait
+end
+redis.call('ZADD', key, now, ARGV[4])
+redis.call('PEXPIRE', key, window)
+return 0
+`
+
+// GetRule 获取限流规则，配置优先，未配置时使用默认规则
+func GetRule(scope string) (Rule, bool) {
+	if vars.App.RateLimitConfig != nil {
+		if r, ok := vars.App.RateLimitConfig.Rules[scope]; ok {
+			return Rule{Limit: r.Limit, Window: time.Duration(r.WindowSeconds) * time.Second}, r.Limit > 0 && r.WindowSeconds > 0
+		}
+	}
+	r, ok := defaultRules[scope]
+	return r, ok
+}
+
+// Allow 依次检查每个限流对象，任意一个超限即拒绝，返回建议的重试等待时间
+// Redis 异常时放行，避免限流组件故障导致服务不可用
+func Allow(ctx context.Context, scope string, subjects ...string) (bool, time.Duration) {
+	if vars.App.RateLimitConfig != nil && vars.App.RateLimitConfig.Disabled {
+		return true, 0
+	}
+	rule, ok := GetRule(scope)
+	if !ok {
+		return true, 0
+	}
+	now := time.Now().UnixMilli()
+	for _, subject := range subjects {
+		if subject == "" {
+			continue
+		}
+		wait, err := vars.Redis.Eval(ctx, slidingWindowScript, []string{redis.GetRateLimitKey(scope, subject)},
+			now, rule.Window.Milliseconds(), rule.Limit, uuid.NewString()).Int64()
+		if err != nil {
+			slog.Error("infra/ratelimit/ratelimit.go Allow redis eval err:", "err", err)
+			continue
+		}
+		if wait > 0 {
+			return false, time.Duration(wait) * time.Millisecond
+		}
+	}
+	return true, 0
+}
@@ -0,0 +1,18 @@
+package ratelimit
+
+import (
+	"loop_server/infra/consts"
+	"time"
+)
+
+// defaultRules 各限流范围的默认规则，可通过配置 rate_limit.rules 覆盖
+var defaultRules = map[string]Rule{
+	consts.RateLimitScopePublic:         {Limit: 60, Window: time.Minute},
+	consts.RateLimitScopeUser:           {Limit: 300, Window: time.Minute},
+	consts.RateLimitScopeLLM:            {Limit: 10, Window: time.Minute},
+	consts.RateLimitScopeIm:             {Limit: 120, Window: time.Minute},
+	consts.RateLimitScopeWsPrivate:      {Limit: 30, Window: 10 * time.Second},
+	consts.RateLimitScopeWsGroup:        {Limit: 20, Window: 10 * time.Second},
+	consts.RateLimitScopeWsSignal:       {Limit: 200, Window: 10 * time.Second},
+	consts.RateLimitScopeWsHeartbeatAck: {Limit: 300, Window: 10 * time.Second},
+}
@@ -1,13 +1,16 @@
 package redis
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"time"
 )
 
 func GetOnlineUserKey() string {
 	return fmt.Sprintf("loop:online_users")
 }
 
+// GetUserChatKey 用户离线消息，score 统一为毫秒时间戳
 func GetUserChatKey(id uint) string {
 	return fmt.Sprintf("loop:user:%d:chat", id)
 }
@@ -20,10 +23,77 @@ func GetGroupAckStatusKey(userId uint, group uint) string {
 	return fmt.Sprintf("loop:ack:%d:%d:message_status", userId, group)
 }
 
-func GetAccessTokenKey(userID uint) string {
-	return fmt.Sprintf("loop:%s:%d", "access_token", userID)
+func GetSessionKey(sessionId string) string {
+	return fmt.Sprintf("loop:session:%s", sessionId)
 }
 
-func GetRefreshTokenKey(userID uint) string {
-	return fmt.Sprintf("loop:%s:%d", "refresh_token", userID)
+func GetSessionRotatedKey(sessionId string) string {
+	return fmt.Sprintf("loop:session:%s:rotated", sessionId)
+}
+
+func GetUserSessionsKey(userId uint) string {
+	return fmt.Sprintf("loop:user:%d:sessions", userId)
+}
+
+func GetFriendRecommendKey(userId uint) string {
+	return fmt.Sprintf("loop:friend:%d:recommend", userId)
+}
+
+func GetAccountExportKey(userId uint) string {
+	return fmt.Sprintf("loop:account:%d:export", userId)
+}
+
+func GetAccountExportLockKey(userId uint) string {
+	return fmt.Sprintf("loop:account:%d:export:lock", userId)
+}
+
+func GetVerifyCodeKey(scene, phone string) string {
+	return fmt.Sprintf("loop:verify:%s:%s:code", scene, phone)
+}
+
+func GetVerifyResendKey(scene, phone string) string {
+	return fmt.Sprintf("loop:verify:%s:%s:resend", scene, phone)
+}
+
+func GetVerifyDailyKey(phone string) string {
+	return fmt.Sprintf("loop:verify:%s:daily:%s", phone, time.Now().Format("20060102"))
+}
+
+func GetTotpEnrollKey(userId uint) string {
+	return fmt.Sprintf("loop:2fa:%d:enroll", userId)
+}
+
+func GetTotpUsedKey(userId uint, step int64) string {
+	return fmt.Sprintf("loop:2fa:%d:used:%d", userId, step)
+}
+
+func GetLoginChallengeKey(token string) string {
+	return fmt.Sprintf("loop:2fa:challenge:%s", token)
+}
+
+// GetLoginFailKey 登录失败计数，subject 为 phone 或 ip
+func GetLoginFailKey(subject, value string) string {
+	return fmt.Sprintf("loop:login:fail:%s:%s", subject, value)
+}
+
+func GetLoginBackoffKey(subject, value string) string {
+	return fmt.Sprintf("loop:login:backoff:%s:%s", subject, value)
+}
+
+func GetLoginLockKey(phone string) string {
+	return fmt.Sprintf("loop:login:lock:%s", phone)
+}
+
+// GetRateLimitKey 限流滑动窗口，subject 形如 user:1 或 ip:127.0.0.1
+func GetRateLimitKey(scope, subject string) string {
+	return fmt.Sprintf("loop:ratelimit:%s:%s", scope, subject)
+}
+
+// GetOperatorSessionKey 操作员会话，key 中只保存 token 的 sha256，避免 token 出现在 redis 中
+func GetOperatorSessionKey(token string) string {
+	return fmt.Sprintf("loop:operator:session:%x", sha256.Sum256([]byte(token)))
+}
+
+func GetOperatorSessionsKey(operatorId uint) string {
+	return fmt.Sprintf("loop:operator:%d:sessions", operatorId)
 }
@@ -131,6 +131,18 @@ func (r *Room) broadcastTrack(senderID uint, remoteTrack *webrtc.TrackRemote, lo
 	}
 }
 
+// ParticipantIds 获取房间内参与者 id
+func (r *Room) ParticipantIds() []uint {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	ids := make([]uint, 0, len(r.participants))
+	for id := range r.participants {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 // RemoveParticipant 从房间中移除参与者
 // 1. 关闭 PeerConnection
 // 2. 从房间参与者列表中删除
@@ -82,6 +82,18 @@ func (sfu *SFU) GetRoom(roomID uint) *Room {
 	return sfu.rooms[roomID]
 }
 
+// GetRooms 获取当前全部房间及其参与者
+func (sfu *SFU) GetRooms() map[uint][]uint {
+	sfu.roomsLock.RLock()
+	defer sfu.roomsLock.RUnlock()
+
+	rooms := make(map[uint][]uint, len(sfu.rooms))
+	for id, room := range sfu.rooms {
+		rooms[id] = room.ParticipantIds()
+	}
+	return rooms
+}
+
 // CreateOrGetParticipant 创建或者获取 participant
 func (sfu *SFU) CreateOrGetParticipant(groupId uint, isInitiator bool, userId uint, initiator func()) (*Participant, error) {
 	if participant := sfu.GetParticipant(userId); participant != nil {
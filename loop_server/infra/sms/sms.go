@@ -0,0 +1,64 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"loop_server/pkg/settings"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	DriverLog  = "log"  // 打印到日志，本地调试用
+	DriverFile = "file" // 追加写入文件，本地调试用
+)
+
+// Sender 验证码发送器，接入短信服务商时实现该接口即可
+type Sender interface {
+	Send(ctx context.Context, phone, scene, code string) error
+}
+
+// InitSender 按 driver 创建发送器；log 和 file 会以明文保存验证码，只允许在调试模式下使用，
+// 其他模式返回错误，接入短信服务商后再启用
+func InitSender(c *settings.SmsConfig, mode string) (Sender, error) {
+	driver := DriverLog
+	if c != nil && c.Driver != "" {
+		driver = c.Driver
+	}
+	if driver != DriverLog && driver != DriverFile {
+		return nil, fmt.Errorf("unknown sms driver %q", driver)
+	}
+	if mode != settings.ModeDev {
+		return nil, fmt.Errorf("sms driver %q writes verify codes in plaintext and is only allowed in %s mode", driver, settings.ModeDev)
+	}
+	if driver == DriverFile {
+		return &FileSender{Path: c.FilePath}, nil
+	}
+	return &LogSender{}, nil
+}
+
+type LogSender struct{}
+
+func (l *LogSender) Send(ctx context.Context, phone, scene, code string) error {
+	slog.Info("send verify code", "phone", phone, "scene", scene, "code", code)
+	return nil
+}
+
+type FileSender struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func (f *FileSender) Send(ctx context.Context, phone, scene, code string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.OpenFile(f.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = fmt.Fprintf(file, "%s\t%s\t%s\t%s\n", time.Now().Format(time.DateTime), phone, scene, code)
+	return err
+}
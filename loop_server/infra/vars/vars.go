@@ -6,7 +6,9 @@ import (
 	redis2 "loop_server/infra/redis"
 	"loop_server/infra/sfu"
 	"loop_server/infra/ws"
+	"loop_server/pkg/jwt"
 	"loop_server/pkg/settings"
+	"os"
 )
 
 var Redis *redis.Client
@@ -26,6 +28,18 @@ func init() {
 		return
 	}
 	Redis = redis2.InitRDB(App.RedisConfig)
+	if err = jwt.Init(App.JwtConfig); err != nil {
+		// 密钥加载失败时使用临时密钥会让重启和多实例之间的 token 全部失效，只允许在调试模式下降级
+		if App.Mode != settings.ModeDev {
+			slog.Error("jwt.Init() err:", "err", err)
+			os.Exit(1)
+		}
+		slog.Error("jwt.Init() err, fallback to ephemeral key in dev mode:", "err", err)
+		if err = jwt.InitEphemeral(); err != nil {
+			slog.Error("jwt.InitEphemeral() err:", "err", err)
+			os.Exit(1)
+		}
+	}
 	Ws = ws.NewWsServer()
 	Sfu, err = sfu.NewSFU()
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"github.com/gorilla/websocket"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,9 +18,39 @@ var Upgrade = websocket.Upgrader{
 }
 
 type Client struct {
-	Conn   *websocket.Conn
-	Mu     *sync.Mutex
-	UserId uint
+	Conn      *websocket.Conn
+	Mu        *sync.Mutex
+	UserId    uint         // 首次认证时写入，之后只读
+	SessionId string       // 建立连接时使用的登录会话，首次认证时写入，之后只读
+	expiresAt atomic.Int64 // 认证使用的 access token 过期时间，unix 秒
+}
+
+func (c *Client) SetExpiresAt(t time.Time) {
+	c.expiresAt.Store(t.Unix())
+}
+
+func (c *Client) ExpiresAt() time.Time {
+	return time.Unix(c.expiresAt.Load(), 0)
+}
+
+// Authenticated 连接已认证且 access token 未过期
+func (c *Client) Authenticated() bool {
+	return c.UserId != 0 && time.Now().Before(c.ExpiresAt())
+}
+
+// Write 直接向连接写入消息，用于尚未加入在线列表的连接
+func (c *Client) Write(msg []byte) error {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+	return c.Conn.WriteMessage(websocket.TextMessage, msg)
+}
+
+// CloseWithReason 发送关闭帧后断开连接
+func (c *Client) CloseWithReason(code int, text string) {
+	c.Mu.Lock()
+	c.Conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(time.Second))
+	c.Mu.Unlock()
+	c.Conn.Close()
 }
 
 type Server struct {
@@ -46,10 +77,24 @@ func (s *Server) Get(key uint) *Client {
 	return s.clients[key]
 }
 
-func (s *Server) Delete(key uint) {
+// Swap 保存连接并返回该用户之前的连接
+func (s *Server) Swap(key uint, value *Client) *Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old := s.clients[key]
+	s.clients[key] = value
+	return old
+}
+
+// CompareAndDelete 仅当保存的连接是 value 时删除，避免旧连接断开时删掉新连接
+func (s *Server) CompareAndDelete(key uint, value *Client) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.clients[key] != value {
+		return false
+	}
 	delete(s.clients, key)
+	return true
 }
 
 func (s *Server) SendMessage(userId uint, msg []byte) error {
@@ -0,0 +1,14 @@
+package application
+
+import (
+	"context"
+	"loop_server/internal/model/dto"
+)
+
+type AccountApp interface {
+	DeleteAccount(ctx context.Context, password string) (*dto.AccountDeletion, error)
+	CreateExport(ctx context.Context) (*dto.AccountExportStatus, error)
+	GetExport(ctx context.Context) (*dto.AccountExportStatus, error)
+	GetExportFile(ctx context.Context) (string, error)
+	RunWorker(ctx context.Context)
+}
@@ -0,0 +1,31 @@
+package application
+
+import (
+	"context"
+	"loop_server/internal/model/dto"
+	"loop_server/internal/model/param"
+)
+
+type AdminApp interface {
+	InitSuperadmin(ctx context.Context) error
+	Login(ctx context.Context, username, password, captchaToken string) (*dto.OperatorLogin, error)
+	Logout(ctx context.Context, token string) error
+	GetOperatorList(ctx context.Context) ([]*dto.Operator, error)
+	CreateOperator(ctx context.Context, p *param.CreateOperatorRequest) (*dto.Operator, error)
+	UpdateOperator(ctx context.Context, p *param.UpdateOperatorRequest) error
+	QueryUser(ctx context.Context, userId uint, phone string) (*dto.AdminUserInfo, error)
+	BanUser(ctx context.Context, p *param.AdminBanUserRequest) error
+	UnbanUser(ctx context.Context, p *param.AdminUserRequest) error
+	ForceLogout(ctx context.Context, p *param.AdminUserRequest) error
+	GetGroup(ctx context.Context, groupId uint) (*dto.AdminGroupInfo, error)
+	GetGroupMessage(ctx context.Context, seqId string) (*dto.GroupMessage, error)
+	GetOnlineStat(ctx context.Context) (*dto.OnlineStat, error)
+	GetSfuRooms(ctx context.Context) []*dto.SfuRoom
+	GetAuditLogList(ctx context.Context, p *param.AdminAuditLogRequest) (*dto.AuditLogList, error)
+	UnlockUser(ctx context.Context, phone string) error
+	GetModerationReviewList(ctx context.Context, status, offset, limit int) (*dto.ModerationReviewList, error)
+	ResolveModerationReview(ctx context.Context, id uint, status int) error
+	GetReportList(ctx context.Context, status int, targetType string, offset, limit int) (*dto.ReportList, error)
+	GetReportInfo(ctx context.Context, id uint) (*dto.ReportInfo, error)
+	HandleReport(ctx context.Context, p *param.HandleReportRequest) error
+}
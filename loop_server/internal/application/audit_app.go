@@ -0,0 +1,11 @@
+package application
+
+import (
+	"context"
+	"loop_server/internal/model/dto"
+	"loop_server/internal/model/param"
+)
+
+type AuditApp interface {
+	GetAuditLogList(ctx context.Context, p *param.AuditLogRequest) (*dto.AuditLogList, error)
+}
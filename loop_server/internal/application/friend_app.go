@@ -10,8 +10,21 @@ type FriendApp interface {
 	AddFriend(ctx context.Context, friendId uint, message string) error
 	DeleteFriend(ctx context.Context, friendId uint) error
 	DisposeFriendRequest(ctx context.Context, requesterId uint, status int) error
+	WithdrawFriendRequest(ctx context.Context, recipientId uint) error
 	GetFriendRequest(ctx context.Context) ([]*dto.FriendRequestListNode, error)
-	GetFriendList(ctx context.Context) ([]*dto.User, error)
+	GetFriendList(ctx context.Context) ([]*dto.Friend, error)
 	FriendListStatistics(ctx context.Context) (*dto.FriendListStatistics, error)
 	GetFriendListByGroupId(ctx context.Context, groupId uint) ([]*param.InviteFriendAddGroupList, error)
+	BlockUser(ctx context.Context, userId uint) error
+	UnblockUser(ctx context.Context, userId uint) error
+	GetBlockList(ctx context.Context) ([]*dto.User, error)
+	UpdateFriendRemark(ctx context.Context, friendId uint, remark string) error
+	UpdateFriendTags(ctx context.Context, friendId uint, tags []string) error
+	MoveFriendCategory(ctx context.Context, friendIds []uint, categoryId uint) error
+	GetFriendCategoryList(ctx context.Context) ([]*dto.FriendCategory, error)
+	CreateFriendCategory(ctx context.Context, name string) (*dto.FriendCategory, error)
+	UpdateFriendCategory(ctx context.Context, categoryId uint, name string) error
+	DeleteFriendCategory(ctx context.Context, categoryId uint) error
+	SortFriendCategory(ctx context.Context, categoryIds []uint) error
+	GetFriendRecommend(ctx context.Context, limit int) ([]*dto.FriendRecommend, error)
 }
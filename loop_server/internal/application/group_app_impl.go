@@ -9,7 +9,7 @@ import (
 type GroupApp interface {
 	CreateGroup(ctx context.Context, group *dto.CreateGroupRequest) (*dto.Group, error)
 	UpdateGroup(ctx context.Context, group *dto.UpdateGroupRequest) (*dto.Group, error)
-	DeleteGroup(ctx context.Context, groupId uint) error
+	DeleteGroup(ctx context.Context, groupId uint, confirm string) error
 	GetGroup(ctx context.Context, groupId uint) (*dto.Group, error)
 	GetGroupList(ctx context.Context) ([]*dto.Group, error)
 	ExitGroup(ctx context.Context, groupId uint) error
@@ -20,4 +20,15 @@ type GroupApp interface {
 	GetGroupMemberList(ctx context.Context, groupId uint) ([]*param.Member, error)
 	GetGroupMemberListByLessRole(ctx context.Context, groupId uint) ([]*param.Member, error)
 	TransferGroupOwner(ctx context.Context, groupId uint, userId uint) error
+	UpdateGroupNickname(ctx context.Context, groupId uint, nickname string) error
+	UpdateGroupRemark(ctx context.Context, groupId uint, remark string) error
+	UpgradeGroup(ctx context.Context, groupId uint, groupType int) error
+	SearchGroup(ctx context.Context, p *param.SearchGroupRequest) (*dto.GroupSearchResult, error)
+	UpdateGroupDiscovery(ctx context.Context, p *param.UpdateGroupDiscoveryRequest) error
+	JoinGroup(ctx context.Context, groupId uint, message string) (bool, error)
+	GetGroupJoinRequestList(ctx context.Context, groupId uint) ([]*dto.GroupJoinRequest, error)
+	DisposeGroupJoinRequest(ctx context.Context, groupId, userId uint, status int) error
+	GetGroupPermission(ctx context.Context, groupId uint) (map[string]uint, error)
+	UpdateGroupPermission(ctx context.Context, groupId uint, permission map[string]uint) error
+	ForceDissolveGroup(ctx context.Context, groupId uint) error
 }
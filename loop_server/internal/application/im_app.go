@@ -8,8 +8,11 @@ import (
 
 type ImApp interface {
 	AddOnlineUser(ctx context.Context, client *ws.Client) error
-	RemoveOnlineUser(ctx context.Context, userId uint) error
-	HandleMessage(ctx context.Context, curUserId uint, msgByte []byte) error
+	RemoveOnlineUser(ctx context.Context, userId uint, client *ws.Client) error
+	Authenticate(ctx context.Context, client *ws.Client, msgByte []byte) error
+	RequestReauth(ctx context.Context, client *ws.Client) error
+	HandleMessage(ctx context.Context, client *ws.Client, msgByte []byte) error
 	GetOfflineMessage(ctx context.Context, userId uint) ([]*dto.Message, error)
 	SubmitOfflineMessage(ctx context.Context, userId uint, seqIdList []*dto.Ack) error
+	SyncGroupMessage(ctx context.Context, userId, groupId uint, seqId string, limit int) ([]*dto.Message, error)
 }
@@ -0,0 +1,292 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	redis2 "github.com/go-redis/redis/v8"
+	"log/slog"
+	"loop_server/infra/consts"
+	"loop_server/infra/redis"
+	"loop_server/infra/vars"
+	"loop_server/internal/application"
+	"loop_server/internal/domain"
+	"loop_server/internal/model/dto"
+	"loop_server/pkg/bcrypt"
+	"loop_server/pkg/request"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type accountAppImpl struct {
+	userDomain   domain.UserDomain
+	friendDomain domain.FriendDomain
+	imDomain     domain.ImDomain
+	friendApp    application.FriendApp
+	groupApp     application.GroupApp
+}
+
+func NewAccountAppImpl(userDomain domain.UserDomain, friendDomain domain.FriendDomain, imDomain domain.ImDomain, friendApp application.FriendApp, groupApp application.GroupApp) *accountAppImpl {
+	return &accountAppImpl{
+		userDomain:   userDomain,
+		friendDomain: friendDomain,
+		imDomain:     imDomain,
+		friendApp:    friendApp,
+		groupApp:     groupApp,
+	}
+}
+
+// DeleteAccount 申请注销账号，校验密码后进入冷静期，并使当前登录失效
+func (a *accountAppImpl) DeleteAccount(ctx context.Context, password string) (*dto.AccountDeletion, error) {
+	userId := request.GetCurrentUser(ctx)
+	user, err := a.userDomain.QueryUser(ctx, &dto.QueryUserRequest{UserId: userId})
+	if err != nil {
+		return nil, err
+	}
+	if !bcrypt.ComparePassword(user.Password, password) {
+		return nil, consts.ErrPasswordError
+	}
+	at, err := a.userDomain.ScheduleDeletion(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := a.userDomain.RevokeSessions(ctx, userId, ""); err != nil {
+		return nil, err
+	}
+	a.imDomain.CloseConnection(ctx, userId)
+	return &dto.AccountDeletion{DeleteAt: at.Unix()}, nil
+}
+
+// RunWorker 后台任务，定时执行到期的注销并清理过期的导出文件
+func (a *accountAppImpl) RunWorker(ctx context.Context) {
+	ticker := time.NewTicker(consts.AccountDeletionCheckInterval)
+	defer ticker.Stop()
+	for {
+		a.deleteDueAccounts(ctx)
+		a.cleanExportFiles()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *accountAppImpl) deleteDueAccounts(ctx context.Context) {
+	userIds, err := a.userDomain.GetUserIdsDueForDeletion(ctx, consts.AccountDeletionBatchSize)
+	if err != nil {
+		return
+	}
+	for _, userId := range userIds {
+		if err := a.deleteAccount(request.WithCurrentUser(ctx, userId), userId); err != nil {
+			slog.Error("internal/application/impl/account_app_impl.go deleteAccount err:", "user_id", userId, "err", err)
+		}
+	}
+}
+
+// deleteAccount 执行注销：退出或解散所在群，清除好友关系，匿名化消息，最后清除个人资料
+// 各步骤均可重复执行，单个群退出失败时继续处理其余步骤，但不清除个人资料，
+// 用户仍在待注销列表中，下一轮任务会重试剩余的群
+func (a *accountAppImpl) deleteAccount(ctx context.Context, userId uint) error {
+	groups, err := a.groupApp.GetGroupList(ctx)
+	if err != nil {
+		return err
+	}
+	var groupErrs []error
+	for _, group := range groups {
+		if err := a.groupApp.ExitGroup(ctx, group.ID); err != nil {
+			slog.Error("internal/application/impl/account_app_impl.go deleteAccount exit group err:", "user_id", userId, "group_id", group.ID, "err", err)
+			groupErrs = append(groupErrs, err)
+		}
+	}
+	if err := a.friendDomain.RemoveUserRelations(ctx, userId); err != nil {
+		return err
+	}
+	if err := a.imDomain.AnonymizeGroupMessage(ctx, userId); err != nil {
+		return err
+	}
+	if err := a.imDomain.ClearOfflineMessage(ctx, userId); err != nil {
+		return err
+	}
+	if _, err := a.userDomain.RevokeSessions(ctx, userId, ""); err != nil {
+		return err
+	}
+	a.imDomain.CloseConnection(ctx, userId)
+	if len(groupErrs) > 0 {
+		return errors.Join(groupErrs...)
+	}
+	if status, _ := a.getExportStatus(ctx, userId); status != nil {
+		os.Remove(a.exportFilePath(userId, status.CreatedAt))
+		vars.Redis.Del(ctx, redis.GetAccountExportKey(userId))
+	}
+	return a.userDomain.AnonymizeUser(ctx, userId)
+}
+
+// CreateExport 申请导出个人数据，导出在后台异步进行
+func (a *accountAppImpl) CreateExport(ctx context.Context) (*dto.AccountExportStatus, error) {
+	userId := request.GetCurrentUser(ctx)
+	// 通过 SETNX 抢占导出中标记，保证同一用户同时只有一个导出任务
+	ok, err := vars.Redis.SetNX(ctx, redis.GetAccountExportLockKey(userId), 1, consts.AccountExportLockTTL).Result()
+	if err != nil {
+		slog.Error("internal/application/impl/account_app_impl.go CreateExport redis setnx err:", "err", err)
+		return nil, err
+	}
+	if !ok {
+		return nil, consts.ErrAccountExportPending
+	}
+
+	status := &dto.AccountExportStatus{
+		Status:    consts.AccountExportStatusPending,
+		CreatedAt: time.Now().Unix(),
+	}
+	if err := a.setExportStatus(ctx, userId, status); err != nil {
+		vars.Redis.Del(ctx, redis.GetAccountExportLockKey(userId))
+		return nil, err
+	}
+	go a.export(request.WithCurrentUser(context.Background(), userId), userId, status.CreatedAt)
+	return status, nil
+}
+
+func (a *accountAppImpl) GetExport(ctx context.Context) (*dto.AccountExportStatus, error) {
+	return a.getExportStatus(ctx, request.GetCurrentUser(ctx))
+}
+
+// GetExportFile 获取已完成的导出文件路径
+func (a *accountAppImpl) GetExportFile(ctx context.Context) (string, error) {
+	userId := request.GetCurrentUser(ctx)
+	status, err := a.getExportStatus(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+	if status != nil && status.Status == consts.AccountExportStatusPending {
+		return "", consts.ErrAccountExportPending
+	}
+	if status == nil || status.Status != consts.AccountExportStatusDone {
+		return "", consts.ErrAccountExportNotExist
+	}
+	path := a.exportFilePath(userId, status.CreatedAt)
+	if _, err := os.Stat(path); err != nil {
+		return "", consts.ErrAccountExportNotExist
+	}
+	return path, nil
+}
+
+func (a *accountAppImpl) export(ctx context.Context, userId uint, createdAt int64) {
+	status := &dto.AccountExportStatus{Status: consts.AccountExportStatusFailed, CreatedAt: createdAt}
+	if err := a.writeExport(ctx, userId, createdAt); err != nil {
+		slog.Error("internal/application/impl/account_app_impl.go export err:", "user_id", userId, "err", err)
+	} else {
+		status.Status = consts.AccountExportStatusDone
+		status.ExpireAt = time.Now().Add(consts.AccountExportTTL).Unix()
+	}
+	a.setExportStatus(ctx, userId, status)
+	vars.Redis.Del(ctx, redis.GetAccountExportLockKey(userId))
+}
+
+// writeExport 汇总个人资料、好友、群组及发送过的群消息，写入 JSON 文件
+func (a *accountAppImpl) writeExport(ctx context.Context, userId uint, createdAt int64) error {
+	user, err := a.userDomain.QueryUser(ctx, &dto.QueryUserRequest{UserId: userId})
+	if err != nil {
+		return err
+	}
+	user.Password = ""
+	data := &dto.AccountExport{
+		ExportedAt: time.Now().Unix(),
+		Profile:    user,
+		Privacy:    user.Privacy,
+	}
+	if data.Friends, err = a.friendApp.GetFriendList(ctx); err != nil {
+		return err
+	}
+	if data.FriendCategories, err = a.friendApp.GetFriendCategoryList(ctx); err != nil {
+		return err
+	}
+	if data.BlockList, err = a.friendApp.GetBlockList(ctx); err != nil {
+		return err
+	}
+	if data.Groups, err = a.groupApp.GetGroupList(ctx); err != nil {
+		return err
+	}
+	messages, err := a.imDomain.GetGroupMessageBySenderId(ctx, userId)
+	if err != nil {
+		return err
+	}
+	data.Messages = make([]*dto.AccountExportMessage, 0, len(messages))
+	for _, message := range messages {
+		data.Messages = append(data.Messages, &dto.AccountExportMessage{
+			GroupId:  message.GroupId,
+			SeqId:    message.SeqId,
+			Content:  message.Content,
+			Type:     message.Type,
+			SendTime: message.SendTime,
+		})
+	}
+
+	dataByte, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(a.exportDir(), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(a.exportFilePath(userId, createdAt), dataByte, 0o600)
+}
+
+// cleanExportFiles 删除超过有效期的导出文件
+func (a *accountAppImpl) cleanExportFiles() {
+	entries, err := os.ReadDir(a.exportDir())
+	if err != nil {
+		return
+	}
+	deadline := time.Now().Add(-consts.AccountExportTTL)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || info.ModTime().After(deadline) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(a.exportDir(), entry.Name())); err != nil {
+			slog.Error("internal/application/impl/account_app_impl.go cleanExportFiles err:", "err", err)
+		}
+	}
+}
+
+func (a *accountAppImpl) getExportStatus(ctx context.Context, userId uint) (*dto.AccountExportStatus, error) {
+	val, err := vars.Redis.Get(ctx, redis.GetAccountExportKey(userId)).Bytes()
+	if errors.Is(err, redis2.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		slog.Error("internal/application/impl/account_app_impl.go getExportStatus redis get err:", "err", err)
+		return nil, err
+	}
+	status := &dto.AccountExportStatus{}
+	if err := json.Unmarshal(val, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (a *accountAppImpl) setExportStatus(ctx context.Context, userId uint, status *dto.AccountExportStatus) error {
+	val, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	if err := vars.Redis.Set(ctx, redis.GetAccountExportKey(userId), val, consts.AccountExportTTL).Err(); err != nil {
+		slog.Error("internal/application/impl/account_app_impl.go setExportStatus redis set err:", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (a *accountAppImpl) exportDir() string {
+	if vars.App.AccountConfig != nil && vars.App.ExportDir != "" {
+		return vars.App.ExportDir
+	}
+	return consts.AccountExportDir
+}
+
+func (a *accountAppImpl) exportFilePath(userId uint, createdAt int64) string {
+	return filepath.Join(a.exportDir(), fmt.Sprintf("%d_%d.json", userId, createdAt))
+}
@@ -0,0 +1,426 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"loop_server/infra/consts"
+	"loop_server/infra/vars"
+	"loop_server/internal/application"
+	"loop_server/internal/domain"
+	"loop_server/internal/model/dto"
+	"loop_server/internal/model/param"
+	"loop_server/pkg/request"
+	"time"
+)
+
+type adminAppImpl struct {
+	loginGuard     domain.LoginGuardDomain
+	moderation     domain.ModerationDomain
+	reportDomain   domain.ReportDomain
+	userDomain     domain.UserDomain
+	imDomain       domain.ImDomain
+	groupDomain    domain.GroupDomain
+	operatorDomain domain.OperatorDomain
+	auditDomain    domain.AuditDomain
+	groupApp       application.GroupApp
+	sfuApp         application.SfuAPP
+}
+
+func NewAdminAppImpl(loginGuard domain.LoginGuardDomain, moderation domain.ModerationDomain, reportDomain domain.ReportDomain, userDomain domain.UserDomain, imDomain domain.ImDomain, groupDomain domain.GroupDomain, operatorDomain domain.OperatorDomain, auditDomain domain.AuditDomain, groupApp application.GroupApp, sfuApp application.SfuAPP) *adminAppImpl {
+	return &adminAppImpl{
+		loginGuard:     loginGuard,
+		moderation:     moderation,
+		reportDomain:   reportDomain,
+		userDomain:     userDomain,
+		imDomain:       imDomain,
+		groupDomain:    groupDomain,
+		operatorDomain: operatorDomain,
+		auditDomain:    auditDomain,
+		groupApp:       groupApp,
+		sfuApp:         sfuApp,
+	}
+}
+
+// audit 记录操作员的写操作，未指定操作者时使用当前操作员
+func (a *adminAppImpl) audit(ctx context.Context, entry *auditEntry) {
+	entry.ActorType = consts.AuditActorOperator
+	if entry.ActorId == 0 {
+		entry.ActorId = request.GetCurrentOperator(ctx)
+	}
+	recordAudit(ctx, a.auditDomain, entry)
+}
+
+// InitSuperadmin 按配置创建初始超级管理员，未配置密码时跳过
+func (a *adminAppImpl) InitSuperadmin(ctx context.Context) error {
+	if vars.App.AdminConfig == nil || vars.App.AdminConfig.Username == "" || vars.App.AdminConfig.Password == "" {
+		return nil
+	}
+	return a.operatorDomain.InitSuperadmin(ctx, vars.App.AdminConfig.Username, vars.App.AdminConfig.Password)
+}
+
+// Login 操作员登录，与用户密码登录共用失败退避、锁定和人机验证，按登录名计数
+func (a *adminAppImpl) Login(ctx context.Context, username, password, captchaToken string) (*dto.OperatorLogin, error) {
+	ip, _, _ := request.GetClientInfo(ctx)
+	account := consts.LoginGuardOperatorPrefix + username
+	if err := a.loginGuard.Check(ctx, account, ip, captchaToken); err != nil {
+		return nil, err
+	}
+	data, err := a.operatorDomain.Login(ctx, username, password)
+	if errors.Is(err, consts.ErrOperatorLoginFailed) {
+		if err := a.loginGuard.Fail(ctx, account, ip); err != nil {
+			return nil, err
+		}
+		return nil, consts.ErrOperatorLoginFailed
+	}
+	if err != nil {
+		return nil, err
+	}
+	a.audit(ctx, &auditEntry{
+		ActorId:    data.Operator.ID,
+		Action:     consts.AuditActionOperatorLogin,
+		TargetType: consts.AuditTargetOperator,
+		TargetId:   data.Operator.ID,
+	})
+	return data, a.loginGuard.Success(ctx, account, ip)
+}
+
+func (a *adminAppImpl) Logout(ctx context.Context, token string) error {
+	operatorId := request.GetCurrentOperator(ctx)
+	if err := a.operatorDomain.Logout(ctx, operatorId, token); err != nil {
+		return err
+	}
+	a.audit(ctx, &auditEntry{Action: consts.AuditActionOperatorLogout, TargetType: consts.AuditTargetOperator, TargetId: operatorId})
+	return nil
+}
+
+func (a *adminAppImpl) GetOperatorList(ctx context.Context) ([]*dto.Operator, error) {
+	return a.operatorDomain.GetOperatorList(ctx)
+}
+
+func (a *adminAppImpl) CreateOperator(ctx context.Context, p *param.CreateOperatorRequest) (*dto.Operator, error) {
+	operator := &dto.Operator{
+		Username: p.Username,
+		Password: p.Password,
+		Nickname: p.Nickname,
+		Role:     p.Role,
+	}
+	if operator.Nickname == "" {
+		operator.Nickname = p.Username
+	}
+	if err := a.operatorDomain.CreateOperator(ctx, operator); err != nil {
+		return nil, err
+	}
+	a.audit(ctx, &auditEntry{
+		Action:     consts.AuditActionOperatorCreate,
+		TargetType: consts.AuditTargetOperator,
+		TargetId:   operator.ID,
+		After:      map[string]any{"username": operator.Username, "role": operator.Role},
+	})
+	return operator, nil
+}
+
+// UpdateOperator 修改操作员，不能修改自己的角色和状态，避免误操作后无人可以管理
+func (a *adminAppImpl) UpdateOperator(ctx context.Context, p *param.UpdateOperatorRequest) error {
+	if p.Id == request.GetCurrentOperator(ctx) {
+		return consts.ErrNoPermission
+	}
+	err := a.operatorDomain.UpdateOperator(ctx, &dto.Operator{
+		ID:       p.Id,
+		Nickname: p.Nickname,
+		Role:     p.Role,
+		Disabled: p.Disabled,
+		Password: p.Password,
+	})
+	if err != nil {
+		return err
+	}
+	a.audit(ctx, &auditEntry{
+		Action:     consts.AuditActionOperatorUpdate,
+		TargetType: consts.AuditTargetOperator,
+		TargetId:   p.Id,
+		Detail:     map[string]any{"reset_password": p.Password != ""},
+		After:      map[string]any{"nickname": p.Nickname, "role": p.Role, "disabled": p.Disabled},
+	})
+	return nil
+}
+
+// QueryUser 按 id 或手机号查询用户及其账号状态、在线状态和会话
+func (a *adminAppImpl) QueryUser(ctx context.Context, userId uint, phone string) (*dto.AdminUserInfo, error) {
+	user, err := a.userDomain.QueryUser(ctx, &dto.QueryUserRequest{UserId: userId, Phone: phone})
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.ID == 0 {
+		return nil, consts.ErrUserNotExist
+	}
+	sessions, err := a.userDomain.ListSessions(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	info := &dto.AdminUserInfo{
+		User:              user,
+		Online:            a.imDomain.IsOnline(ctx, user.ID),
+		MutedUntil:        user.MutedUntil,
+		BannedUntil:       user.BannedUntil,
+		DeleteScheduledAt: user.DeleteScheduledAt,
+		TotpEnabled:       user.TotpEnabled,
+		Sessions:          sessions,
+	}
+	user.Password = ""
+	return info, nil
+}
+
+func (a *adminAppImpl) getUser(ctx context.Context, userId uint) (*dto.User, error) {
+	user, err := a.userDomain.QueryUser(ctx, &dto.QueryUserRequest{UserId: userId})
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, consts.ErrUserNotExist
+	}
+	return user, nil
+}
+
+// BanUser 封禁用户，注销全部会话并断开 ws 连接
+func (a *adminAppImpl) BanUser(ctx context.Context, p *param.AdminBanUserRequest) error {
+	user, err := a.getUser(ctx, p.UserId)
+	if err != nil {
+		return err
+	}
+	until := reportUntil(p.DurationMinutes)
+	if err = a.userDomain.BanUser(ctx, p.UserId, until); err != nil {
+		return err
+	}
+	a.imDomain.CloseConnection(ctx, p.UserId)
+	a.audit(ctx, &auditEntry{
+		Action:     consts.AuditActionUserBan,
+		TargetType: consts.AuditTargetUser,
+		TargetId:   p.UserId,
+		Detail:     map[string]any{"note": p.Note},
+		Before:     map[string]any{"banned_until": user.BannedUntil},
+		After:      map[string]any{"banned_until": until},
+	})
+	return nil
+}
+
+func (a *adminAppImpl) UnbanUser(ctx context.Context, p *param.AdminUserRequest) error {
+	user, err := a.getUser(ctx, p.UserId)
+	if err != nil {
+		return err
+	}
+	if err = a.userDomain.BanUser(ctx, p.UserId, nil); err != nil {
+		return err
+	}
+	a.audit(ctx, &auditEntry{
+		Action:     consts.AuditActionUserUnban,
+		TargetType: consts.AuditTargetUser,
+		TargetId:   p.UserId,
+		Detail:     map[string]any{"note": p.Note},
+		Before:     map[string]any{"banned_until": user.BannedUntil},
+		After:      map[string]any{"banned_until": nil},
+	})
+	return nil
+}
+
+// ForceLogout 注销用户的全部会话并断开 ws 连接
+func (a *adminAppImpl) ForceLogout(ctx context.Context, p *param.AdminUserRequest) error {
+	if _, err := a.getUser(ctx, p.UserId); err != nil {
+		return err
+	}
+	if _, err := a.userDomain.RevokeSessions(ctx, p.UserId, ""); err != nil {
+		return err
+	}
+	a.imDomain.CloseConnection(ctx, p.UserId)
+	a.audit(ctx, &auditEntry{
+		Action:     consts.AuditActionUserForceLogout,
+		TargetType: consts.AuditTargetUser,
+		TargetId:   p.UserId,
+		Detail:     map[string]any{"note": p.Note},
+	})
+	return nil
+}
+
+func (a *adminAppImpl) GetGroup(ctx context.Context, groupId uint) (*dto.AdminGroupInfo, error) {
+	group, err := a.groupDomain.GetGroupById(ctx, groupId)
+	if err != nil {
+		return nil, err
+	}
+	if group.ID == 0 {
+		return nil, consts.ErrGroupNotExist
+	}
+	members, err := a.groupDomain.GetGroupShip(ctx, groupId)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AdminGroupInfo{Group: group, Members: members}, nil
+}
+
+func (a *adminAppImpl) GetGroupMessage(ctx context.Context, seqId string) (*dto.GroupMessage, error) {
+	msg, err := a.imDomain.GetGroupMessageBySeqId(ctx, seqId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, consts.ErrMessageNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &dto.GroupMessage{
+		SeqId:      msg.SeqId,
+		SenderId:   msg.SenderId,
+		ReceiverId: msg.GroupId,
+		Content:    msg.Content,
+		Type:       msg.Type,
+		SendTime:   msg.SendTime,
+	}, nil
+}
+
+func (a *adminAppImpl) GetOnlineStat(ctx context.Context) (*dto.OnlineStat, error) {
+	count, err := a.imDomain.GetOnlineUserCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.OnlineStat{Count: count}, nil
+}
+
+func (a *adminAppImpl) GetSfuRooms(ctx context.Context) []*dto.SfuRoom {
+	return a.sfuApp.GetRooms(ctx)
+}
+
+// UnlockUser 解除手机号的登录锁定并清空失败计数
+func (a *adminAppImpl) UnlockUser(ctx context.Context, phone string) error {
+	if err := a.loginGuard.Unlock(ctx, phone); err != nil {
+		return err
+	}
+	a.audit(ctx, &auditEntry{Action: consts.AuditActionUserUnlock, TargetType: consts.AuditTargetPhone, TargetId: phone})
+	return nil
+}
+
+func (a *adminAppImpl) GetModerationReviewList(ctx context.Context, status, offset, limit int) (*dto.ModerationReviewList, error) {
+	return a.moderation.GetReviewList(ctx, status, offset, limit)
+}
+
+// ResolveModerationReview 处理审核队列中的内容，确认违规的群消息直接删除，
+// 私聊消息服务端不保存、资料类内容只记录结论，需要处置时通过举报处理或封禁
+func (a *adminAppImpl) ResolveModerationReview(ctx context.Context, id uint, status int) error {
+	review, err := a.moderation.GetPendingReview(ctx, id)
+	if err != nil {
+		return err
+	}
+	// 先删除消息再标记处理完成，删除失败时记录保持待审核，可以重试
+	if status == consts.ModerationReviewRemoved && review.Scene == consts.ModerationSceneGroupMessage {
+		if err := a.imDomain.DeleteGroupMessage(ctx, review.TargetId); err != nil {
+			return err
+		}
+	}
+	if _, err := a.moderation.ResolveReview(ctx, id, status); err != nil {
+		return err
+	}
+	a.audit(ctx, &auditEntry{
+		Action:     consts.AuditActionModerationReview,
+		TargetType: consts.AuditTargetModerationReview,
+		TargetId:   id,
+		Before:     map[string]any{"status": consts.ModerationReviewPending},
+		After:      map[string]any{"status": status},
+	})
+	return nil
+}
+
+func (a *adminAppImpl) GetReportList(ctx context.Context, status int, targetType string, offset, limit int) (*dto.ReportList, error) {
+	return a.reportDomain.GetReportList(ctx, status, targetType, offset, limit)
+}
+
+func (a *adminAppImpl) GetReportInfo(ctx context.Context, id uint) (*dto.ReportInfo, error) {
+	return a.reportDomain.GetReportInfo(ctx, id)
+}
+
+// HandleReport 对举报执行处理动作，每次处理都追加一条处理记录；
+// 已处理的举报可以继续追加处理，已驳回的举报不能再处理
+func (a *adminAppImpl) HandleReport(ctx context.Context, p *param.HandleReportRequest) error {
+	report, err := a.reportDomain.GetReport(ctx, p.Id)
+	if err != nil {
+		return err
+	}
+	if report.Status == consts.ReportStatusDismissed ||
+		(p.Action == consts.ReportActionDismiss && report.Status != consts.ReportStatusPending) {
+		return consts.ErrReportActionInvalid
+	}
+
+	action := &dto.ReportAction{
+		ReportId:     report.ID,
+		Action:       p.Action,
+		OperatorId:   request.GetCurrentOperator(ctx),
+		TargetUserId: report.TargetUserId,
+		GroupId:      report.GroupId,
+		Note:         p.Note,
+	}
+	status := consts.ReportStatusResolved
+	switch p.Action {
+	case consts.ReportActionWarn:
+		err = a.imDomain.PushMessage(ctx, consts.WsMessageCmdSystemNotice, report.TargetUserId, &dto.SystemNotice{
+			Type:     consts.SystemNoticeTypeWarn,
+			Content:  consts.SystemNoticeWarnTemplate,
+			SendTime: time.Now().UnixMilli(),
+		})
+	case consts.ReportActionMute:
+		action.Until = reportUntil(p.DurationMinutes)
+		err = a.userDomain.MuteUser(ctx, report.TargetUserId, action.Until)
+	case consts.ReportActionBan:
+		action.Until = reportUntil(p.DurationMinutes)
+		if err = a.userDomain.BanUser(ctx, report.TargetUserId, action.Until); err == nil {
+			a.imDomain.CloseConnection(ctx, report.TargetUserId)
+		}
+	case consts.ReportActionDissolveGroup:
+		if report.GroupId == 0 {
+			return consts.ErrReportActionInvalid
+		}
+		err = a.groupApp.ForceDissolveGroup(ctx, report.GroupId)
+	case consts.ReportActionDeleteMessage:
+		if report.TargetType != consts.ReportTargetGroupMessage {
+			return consts.ErrReportActionInvalid
+		}
+		action.TargetId = report.TargetId
+		err = a.imDomain.DeleteGroupMessage(ctx, report.TargetId)
+	case consts.ReportActionDismiss:
+		status = consts.ReportStatusDismissed
+	default:
+		return consts.ErrReportActionInvalid
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = a.reportDomain.HandleReport(ctx, action, status); err != nil {
+		return err
+	}
+	a.audit(ctx, &auditEntry{
+		Action:     consts.AuditActionReportHandle,
+		TargetType: consts.AuditTargetReport,
+		TargetId:   report.ID,
+		Detail:     action,
+		Before:     map[string]any{"status": report.Status},
+		After:      map[string]any{"status": status},
+	})
+	return nil
+}
+
+// GetAuditLogList 按操作者、操作对象和时间范围查询审计日志
+func (a *adminAppImpl) GetAuditLogList(ctx context.Context, p *param.AdminAuditLogRequest) (*dto.AuditLogList, error) {
+	return a.auditDomain.GetAuditLogList(ctx, &dto.AuditLogQuery{
+		ActorType:  p.ActorType,
+		ActorId:    p.ActorId,
+		TargetType: p.TargetType,
+		TargetId:   p.TargetId,
+		StartTime:  unixTime(p.StartTime),
+		EndTime:    unixTime(p.EndTime),
+	}, p.Offset(), p.PageSize)
+}
+
+// reportUntil 计算禁言、封禁截止时间，时长为 0 时按永久处理
+func reportUntil(minutes int) *time.Time {
+	duration := time.Duration(minutes) * time.Minute
+	if minutes == 0 {
+		duration = consts.ReportPermanentDuration
+	}
+	until := time.Now().Add(duration)
+	return &until
+}
@@ -0,0 +1,79 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"loop_server/internal/domain"
+	"loop_server/internal/model/dto"
+	"loop_server/internal/model/param"
+	"loop_server/pkg/request"
+	"time"
+)
+
+type auditAppImpl struct {
+	auditDomain domain.AuditDomain
+}
+
+func NewAuditAppImpl(auditDomain domain.AuditDomain) *auditAppImpl {
+	return &auditAppImpl{auditDomain: auditDomain}
+}
+
+// GetAuditLogList 当前用户本人发起的安全事件，管理员的处理记录包含操作人信息，不返回给用户
+func (a *auditAppImpl) GetAuditLogList(ctx context.Context, p *param.AuditLogRequest) (*dto.AuditLogList, error) {
+	return a.auditDomain.GetAuditLogList(ctx, &dto.AuditLogQuery{
+		UserId:    request.GetCurrentUser(ctx),
+		StartTime: unixTime(p.StartTime),
+		EndTime:   unixTime(p.EndTime),
+	}, p.Offset(), p.PageSize)
+}
+
+// auditEntry 一条审计日志的内容，操作者的 IP 和 User-Agent 从请求中获取
+type auditEntry struct {
+	ActorType  string
+	ActorId    uint
+	Action     string
+	TargetType string
+	TargetId   any
+	Detail     any // 操作参数
+	Before     any // 变更前的状态
+	After      any // 变更后的状态
+}
+
+// recordAudit 记录审计日志，日志异步写入，不影响已经生效的操作
+func recordAudit(ctx context.Context, audit domain.AuditDomain, entry *auditEntry) {
+	ip, userAgent, _ := request.GetClientInfo(ctx)
+	audit.Record(ctx, &dto.AuditLog{
+		ActorType:  entry.ActorType,
+		ActorId:    entry.ActorId,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetId:   fmt.Sprint(entry.TargetId),
+		Ip:         ip,
+		UserAgent:  userAgent,
+		Detail:     auditJson(entry.Detail),
+		Before:     auditJson(entry.Before),
+		After:      auditJson(entry.After),
+	})
+}
+
+func auditJson(v any) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		slog.Error("internal/application/impl/audit_app_impl.go auditJson err:", "err", err)
+		return ""
+	}
+	return string(data)
+}
+
+// unixTime 秒级时间戳转换为时间，0 返回零值表示不限制
+func unixTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
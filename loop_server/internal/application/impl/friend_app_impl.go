@@ -2,20 +2,24 @@ package impl
 
 import (
 	"context"
+	"github.com/samber/lo"
+	"loop_server/infra/consts"
 	"loop_server/internal/domain"
 	"loop_server/internal/model/dto"
 	"loop_server/internal/model/param"
 	"loop_server/pkg/request"
+	"time"
 )
 
 type friendAppImpl struct {
 	friendDomain domain.FriendDomain
 	userDomain   domain.UserDomain
 	groupDomain  domain.GroupDomain
+	imDomain     domain.ImDomain
 }
 
-func NewFriendAppImpl(friendDomain domain.FriendDomain, userDomain domain.UserDomain, groupAppImpl domain.GroupDomain) *friendAppImpl {
-	return &friendAppImpl{friendDomain: friendDomain, userDomain: userDomain, groupDomain: groupAppImpl}
+func NewFriendAppImpl(friendDomain domain.FriendDomain, userDomain domain.UserDomain, groupAppImpl domain.GroupDomain, imDomain domain.ImDomain) *friendAppImpl {
+	return &friendAppImpl{friendDomain: friendDomain, userDomain: userDomain, groupDomain: groupAppImpl, imDomain: imDomain}
 }
 
 // AddFriend 添加好友
@@ -23,17 +27,59 @@ func (u *friendAppImpl) AddFriend(ctx context.Context, friendId uint, message st
 	if friendId == request.GetCurrentUser(ctx) {
 		return nil
 	}
+	// 被对方拉黑时静默丢弃，不暴露拉黑状态
+	blocked, err := u.friendDomain.IsBlocked(ctx, friendId, request.GetCurrentUser(ctx))
+	if err != nil || blocked {
+		return err
+	}
+	if err = u.checkFriendRequestScope(ctx, friendId); err != nil {
+		return err
+	}
 	req := &dto.FriendRequest{
 		RequesterId: request.GetCurrentUser(ctx),
 		RecipientId: friendId,
 		Status:      0,
 		Message:     message,
 	}
-	return u.friendDomain.AddFriend(ctx, req)
+	if err = u.friendDomain.AddFriend(ctx, req); err != nil {
+		return err
+	}
+	u.friendDomain.ClearFriendRecommend(ctx, req.RequesterId, req.RecipientId)
+	u.notify(ctx, consts.WsMessageCmdFriendRequest, friendId, &dto.FriendEvent{Message: message})
+	return nil
+}
+
+// checkFriendRequestScope 校验对方的隐私设置是否允许当前用户发送好友请求
+func (u *friendAppImpl) checkFriendRequestScope(ctx context.Context, friendId uint) error {
+	friend, err := u.userDomain.QueryUser(ctx, &dto.QueryUserRequest{UserId: friendId})
+	if err != nil {
+		return err
+	}
+	if friend.ID == 0 {
+		return nil
+	}
+	switch friend.Privacy.FriendRequestScope {
+	case consts.PrivacyScopeEveryone:
+		return nil
+	case consts.PrivacyScopeFriends:
+		ok, err := u.friendDomain.HasMutualConnection(ctx, request.GetCurrentUser(ctx), friendId)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return consts.ErrFriendRequestForbidden
 }
 
 func (u *friendAppImpl) DeleteFriend(ctx context.Context, friendId uint) error {
-	return u.friendDomain.DeleteFriend(ctx, request.GetCurrentUser(ctx), friendId)
+	if err := u.friendDomain.DeleteFriend(ctx, request.GetCurrentUser(ctx), friendId); err != nil {
+		return err
+	}
+	u.friendDomain.ClearFriendRecommend(ctx, request.GetCurrentUser(ctx), friendId)
+	u.notify(ctx, consts.WsMessageCmdFriendRemoved, friendId, &dto.FriendEvent{})
+	return nil
 }
 
 func (u *friendAppImpl) DisposeFriendRequest(ctx context.Context, requesterId uint, status int) error {
@@ -42,7 +88,34 @@ func (u *friendAppImpl) DisposeFriendRequest(ctx context.Context, requesterId ui
 		RecipientId: request.GetCurrentUser(ctx),
 		Status:      status,
 	}
-	return u.friendDomain.UpdateFriendRequest(ctx, req)
+	if err := u.friendDomain.UpdateFriendRequest(ctx, req); err != nil {
+		return err
+	}
+	u.friendDomain.ClearFriendRecommend(ctx, req.RequesterId, req.RecipientId)
+	u.notify(ctx, consts.WsMessageCmdFriendRequestResult, requesterId, &dto.FriendEvent{Status: status})
+	return nil
+}
+
+func (u *friendAppImpl) WithdrawFriendRequest(ctx context.Context, recipientId uint) error {
+	if err := u.friendDomain.WithdrawFriendRequest(ctx, request.GetCurrentUser(ctx), recipientId); err != nil {
+		return err
+	}
+	u.friendDomain.ClearFriendRecommend(ctx, request.GetCurrentUser(ctx), recipientId)
+	u.notify(ctx, consts.WsMessageCmdFriendRequestResult, recipientId, &dto.FriendEvent{Status: consts.FriendRequestStatusWithdraw})
+	return nil
+}
+
+// notify 以当前用户身份向 receiverId 推送好友事件，不在线时存为离线消息
+func (u *friendAppImpl) notify(ctx context.Context, cmd int, receiverId uint, event *dto.FriendEvent) {
+	user, err := u.userDomain.QueryUser(ctx, &dto.QueryUserRequest{UserId: request.GetCurrentUser(ctx)})
+	if err != nil {
+		return
+	}
+	event.UserId = user.ID
+	event.Nickname = user.Nickname
+	event.Avatar = user.Avatar
+	event.SendTime = time.Now().UnixMilli()
+	u.imDomain.PushMessage(ctx, cmd, receiverId, event)
 }
 
 func (u *friendAppImpl) GetFriendRequest(ctx context.Context) ([]*dto.FriendRequestListNode, error) {
@@ -85,6 +158,8 @@ func (u *friendAppImpl) convertGetFriendRequest(ctx context.Context, requests []
 			avatar = hash[req.RecipientId].Avatar
 		}
 		data = append(data, &dto.FriendRequestListNode{
+			ID:          req.ID,
+			CreatedAt:   req.CreatedAt,
 			RequesterId: req.RequesterId,
 			RecipientId: req.RecipientId,
 			Status:      req.Status,
@@ -96,8 +171,41 @@ func (u *friendAppImpl) convertGetFriendRequest(ctx context.Context, requests []
 	return data
 }
 
-func (u *friendAppImpl) GetFriendList(ctx context.Context) ([]*dto.User, error) {
-	return u.friendDomain.GetFriendList(ctx)
+// GetFriendList 获取好友列表，附带备注、标签与分组
+func (u *friendAppImpl) GetFriendList(ctx context.Context) ([]*dto.Friend, error) {
+	users, err := u.friendDomain.GetFriendList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userId := request.GetCurrentUser(ctx)
+	ships, err := u.friendDomain.GetFriendShipList(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := u.friendDomain.GetFriendCategoryList(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	shipMap := lo.KeyBy(ships, func(ship *dto.FriendShip) uint { return ship.FriendId })
+	categoryMap := lo.KeyBy(categories, func(category *dto.FriendCategory) uint { return category.ID })
+
+	data := make([]*dto.Friend, 0, len(users))
+	for _, user := range users {
+		maskProfile(user, userId, true)
+		friend := &dto.Friend{User: user, Tags: []string{}}
+		if ship, ok := shipMap[user.ID]; ok {
+			friend.Remark = ship.Remark
+			if ship.Tags != nil {
+				friend.Tags = ship.Tags
+			}
+			if category, ok := categoryMap[ship.CategoryId]; ok {
+				friend.CategoryId = category.ID
+				friend.Category = category.Name
+			}
+		}
+		data = append(data, friend)
+	}
+	return data, nil
 }
 
 func (u *friendAppImpl) FriendListStatistics(ctx context.Context) (*dto.FriendListStatistics, error) {
@@ -128,3 +236,98 @@ func (u *friendAppImpl) GetFriendListByGroupId(ctx context.Context, groupId uint
 	}
 	return data, nil
 }
+
+func (u *friendAppImpl) BlockUser(ctx context.Context, userId uint) error {
+	if userId == request.GetCurrentUser(ctx) {
+		return nil
+	}
+	if err := u.friendDomain.BlockUser(ctx, request.GetCurrentUser(ctx), userId); err != nil {
+		return err
+	}
+	u.friendDomain.ClearFriendRecommend(ctx, request.GetCurrentUser(ctx), userId)
+	return nil
+}
+
+func (u *friendAppImpl) UnblockUser(ctx context.Context, userId uint) error {
+	return u.friendDomain.UnblockUser(ctx, request.GetCurrentUser(ctx), userId)
+}
+
+func (u *friendAppImpl) GetBlockList(ctx context.Context) ([]*dto.User, error) {
+	return u.friendDomain.GetBlockList(ctx, request.GetCurrentUser(ctx))
+}
+
+func (u *friendAppImpl) UpdateFriendRemark(ctx context.Context, friendId uint, remark string) error {
+	return u.friendDomain.UpdateFriendRemark(ctx, request.GetCurrentUser(ctx), friendId, remark)
+}
+
+func (u *friendAppImpl) UpdateFriendTags(ctx context.Context, friendId uint, tags []string) error {
+	return u.friendDomain.UpdateFriendTags(ctx, request.GetCurrentUser(ctx), friendId, lo.Uniq(tags))
+}
+
+func (u *friendAppImpl) MoveFriendCategory(ctx context.Context, friendIds []uint, categoryId uint) error {
+	return u.friendDomain.MoveFriendCategory(ctx, request.GetCurrentUser(ctx), friendIds, categoryId)
+}
+
+func (u *friendAppImpl) GetFriendCategoryList(ctx context.Context) ([]*dto.FriendCategory, error) {
+	return u.friendDomain.GetFriendCategoryList(ctx, request.GetCurrentUser(ctx))
+}
+
+func (u *friendAppImpl) CreateFriendCategory(ctx context.Context, name string) (*dto.FriendCategory, error) {
+	return u.friendDomain.CreateFriendCategory(ctx, request.GetCurrentUser(ctx), name)
+}
+
+func (u *friendAppImpl) UpdateFriendCategory(ctx context.Context, categoryId uint, name string) error {
+	return u.friendDomain.UpdateFriendCategory(ctx, &dto.FriendCategory{
+		ID:     categoryId,
+		UserId: request.GetCurrentUser(ctx),
+		Name:   name,
+	})
+}
+
+func (u *friendAppImpl) DeleteFriendCategory(ctx context.Context, categoryId uint) error {
+	return u.friendDomain.DeleteFriendCategory(ctx, request.GetCurrentUser(ctx), categoryId)
+}
+
+func (u *friendAppImpl) SortFriendCategory(ctx context.Context, categoryIds []uint) error {
+	return u.friendDomain.SortFriendCategory(ctx, request.GetCurrentUser(ctx), lo.Uniq(categoryIds))
+}
+
+// GetFriendRecommend 获取好友推荐，按共同好友与共同群排序
+func (u *friendAppImpl) GetFriendRecommend(ctx context.Context, limit int) ([]*dto.FriendRecommend, error) {
+	if limit <= 0 {
+		limit = consts.FriendRecommendLimit
+	}
+	limit = min(limit, consts.FriendRecommendMaxLimit)
+
+	list, err := u.friendDomain.GetFriendRecommend(ctx, request.GetCurrentUser(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	userIds := lo.Map(list, func(item *dto.FriendRecommend, _ int) uint { return item.UserId })
+	users, err := u.userDomain.GetUserListByUserIds(ctx, userIds)
+	if err != nil {
+		return nil, err
+	}
+	userMap := lo.KeyBy(users, func(user *dto.User) uint { return user.ID })
+
+	data := make([]*dto.FriendRecommend, 0, len(list))
+	for _, item := range list {
+		user, ok := userMap[item.UserId]
+		if !ok || user.Privacy.FriendRequestScope == consts.PrivacyScopeNobody {
+			continue
+		}
+		maskProfile(user, request.GetCurrentUser(ctx), false)
+		data = append(data, &dto.FriendRecommend{
+			UserId:            item.UserId,
+			Nickname:          user.Nickname,
+			Avatar:            user.Avatar,
+			Signature:         user.Signature,
+			MutualFriendCount: item.MutualFriendCount,
+			SharedGroupCount:  item.SharedGroupCount,
+		})
+	}
+	return data, nil
+}
@@ -3,25 +3,64 @@ package impl
 import (
 	"context"
 	"encoding/json"
+	"github.com/google/uuid"
 	"github.com/samber/lo"
 	"loop_server/infra/consts"
 	"loop_server/internal/domain"
 	"loop_server/internal/model/dto"
 	"loop_server/internal/model/param"
 	"loop_server/pkg/request"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type groupAppImpl struct {
-	group domain.GroupDomain
-	user  domain.UserDomain
-	im    domain.ImDomain
+	group      domain.GroupDomain
+	user       domain.UserDomain
+	im         domain.ImDomain
+	friend     domain.FriendDomain
+	moderation domain.ModerationDomain
+	audit      domain.AuditDomain
 }
 
-func NewGroupAppImpl(group domain.GroupDomain, user domain.UserDomain, im domain.ImDomain) *groupAppImpl {
-	return &groupAppImpl{group: group, user: user, im: im}
+func NewGroupAppImpl(group domain.GroupDomain, user domain.UserDomain, im domain.ImDomain, friend domain.FriendDomain, moderation domain.ModerationDomain, audit domain.AuditDomain) *groupAppImpl {
+	return &groupAppImpl{group: group, user: user, im: im, friend: friend, moderation: moderation, audit: audit}
+}
+
+// auditMember 记录群主转让、管理员任免和移除成员，before、after 为变更前后的群角色
+func (g *groupAppImpl) auditMember(ctx context.Context, groupId uint, action string, before, after any) {
+	recordAudit(ctx, g.audit, &auditEntry{
+		ActorType:  consts.AuditActorUser,
+		ActorId:    request.GetCurrentUser(ctx),
+		Action:     action,
+		TargetType: consts.AuditTargetGroup,
+		TargetId:   groupId,
+		Before:     before,
+		After:      after,
+	})
+}
+
+// memberRoles 获取用户在群内的角色，不在群内的用户不返回
+func (g *groupAppImpl) memberRoles(ctx context.Context, groupId uint, userIds []uint) (map[uint]uint, error) {
+	ships, err := g.group.GetGroupShipByUserIds(ctx, groupId, userIds)
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[uint]uint, len(ships))
+	for _, ship := range ships {
+		roles[ship.UserId] = ship.Role
+	}
+	return roles, nil
 }
 
 func (g *groupAppImpl) CreateGroup(ctx context.Context, group *dto.CreateGroupRequest) (*dto.Group, error) {
+	userIds, err := g.excludeBlocker(ctx, group.UserIds)
+	if err != nil {
+		return nil, err
+	}
+	group.UserIds = userIds
+
 	// 判断用户是否存在
 	exist, err := g.isUserExist(ctx, group.UserIds)
 	if !exist || err != nil {
@@ -57,35 +96,128 @@ func (g *groupAppImpl) CreateGroup(ctx context.Context, group *dto.CreateGroupRe
 	return data, nil
 }
 
-func (g *groupAppImpl) UpdateGroup(ctx context.Context, group *dto.UpdateGroupRequest) (*dto.Group, error) {
-
-	ship, err := g.group.GetGroupShipByUserId(ctx, group.GroupId, request.GetCurrentUser(ctx))
+// excludeBlocker 过滤掉拉黑了当前用户的用户，被拉黑时不能邀请对方入群
+func (g *groupAppImpl) excludeBlocker(ctx context.Context, userIds []uint) ([]uint, error) {
+	blockers, err := g.friend.GetBlockerIds(ctx, userIds, request.GetCurrentUser(ctx))
 	if err != nil {
 		return nil, err
 	}
-	if ship.Role <= consts.GroupRoleAdmin {
-		return nil, consts.ErrNoPermission
+	return lo.Without(userIds, blockers...), nil
+}
+
+// authorize 校验当前用户在群内是否有执行 action 的权限，返回当前用户的群关系
+func (g *groupAppImpl) authorize(ctx context.Context, groupId uint, action string) (*dto.GroupShip, error) {
+	return g.group.CheckPermission(ctx, groupId, request.GetCurrentUser(ctx), action)
+}
+
+func (g *groupAppImpl) UpdateGroup(ctx context.Context, group *dto.UpdateGroupRequest) (*dto.Group, error) {
+	if _, err := g.authorize(ctx, group.GroupId, consts.GroupActionEditInfo); err != nil {
+		return nil, err
 	}
 
+	userId, targetId := request.GetCurrentUser(ctx), strconv.FormatUint(uint64(group.GroupId), 10)
+	for scene, content := range map[string]*string{
+		consts.ModerationSceneGroupName:     &group.Name,
+		consts.ModerationSceneGroupDescribe: &group.Describe,
+	} {
+		allowed, err := moderateText(ctx, g.moderation, scene, userId, targetId, content)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, consts.ErrContentRejected
+		}
+	}
 	return g.group.UpdateGroup(ctx, group)
 }
 
-func (g *groupAppImpl) DeleteGroup(ctx context.Context, groupId uint) error {
+func (g *groupAppImpl) DeleteGroup(ctx context.Context, groupId uint, confirm string) error {
+	if _, err := g.authorize(ctx, groupId, consts.GroupActionDissolve); err != nil {
+		return err
+	}
 	group, err := g.group.GetGroupById(ctx, groupId)
 	if err != nil {
 		return err
 	}
-	if group.OwnerId != request.GetCurrentUser(ctx) {
-		return consts.ErrNoPermission
+	// 需输入群名称确认解散
+	if group.Name != strings.TrimSpace(confirm) {
+		return consts.ErrGroupConfirmMismatch
+	}
+	return g.dissolveGroup(ctx, group)
+}
+
+// ForceDissolveGroup 管理后台处理举报时强制解散群，不校验群内权限，群已不存在时视为举报对象不存在
+func (g *groupAppImpl) ForceDissolveGroup(ctx context.Context, groupId uint) error {
+	group, err := g.group.GetGroupById(ctx, groupId)
+	if err != nil {
+		return err
+	}
+	if group.ID == 0 {
+		return consts.ErrReportTargetNotExist
+	}
+	return g.dissolveGroup(ctx, group)
+}
+
+// dissolveGroup 解散群，并向其他群成员推送群解散的系统消息
+func (g *groupAppImpl) dissolveGroup(ctx context.Context, group *dto.Group) error {
+	userIds, err := g.group.GetGroupUserId(ctx, group.ID)
+	if err != nil {
+		return err
+	}
+	if err = g.group.DeleteGroup(ctx, group.ID); err != nil {
+		return err
+	}
+
+	operator := request.GetCurrentUser(ctx)
+	msg := &dto.GroupMessage{
+		SeqId:       uuid.New().String(),
+		SenderId:    operator,
+		ReceiverId:  group.ID,
+		Content:     "该群已解散",
+		Type:        consts.GroupMessageTypeDissolve,
+		SendTime:    time.Now().UnixMilli(),
+		GroupName:   group.Name,
+		GroupAvatar: group.Avatar,
+	}
+	for _, id := range userIds {
+		if id == operator {
+			continue
+		}
+		g.im.PushMessage(ctx, consts.WsMessageCmdGroupMessage, id, msg)
 	}
-	return g.group.DeleteGroup(ctx, groupId)
+	return nil
 }
 
 func (g *groupAppImpl) GetGroupList(ctx context.Context) ([]*dto.Group, error) {
-	return g.group.GetGroupList(ctx, request.GetCurrentUser(ctx))
+	groups, err := g.group.GetGroupList(ctx, request.GetCurrentUser(ctx))
+	if err != nil {
+		return nil, err
+	}
+	ships, err := g.group.GetGroupShipListByUserId(ctx, request.GetCurrentUser(ctx))
+	if err != nil {
+		return nil, err
+	}
+	shipMap := make(map[uint]*dto.GroupShip, len(ships))
+	for _, ship := range ships {
+		shipMap[ship.GroupId] = ship
+	}
+	for _, group := range groups {
+		if ship, ok := shipMap[group.ID]; ok {
+			group.Remark = ship.GroupRemark
+			group.MyNickname = ship.Remark
+		}
+	}
+	return groups, nil
 }
 
 func (g *groupAppImpl) AddMember(ctx context.Context, groupId uint, userIds []uint) error {
+	if _, err := g.authorize(ctx, groupId, consts.GroupActionInvite); err != nil {
+		return err
+	}
+	userIds, err := g.excludeBlocker(ctx, userIds)
+	if err != nil || len(userIds) == 0 {
+		return err
+	}
 	exist, err := g.isUserExist(ctx, userIds)
 	if !exist || err != nil {
 		return err
@@ -103,12 +235,26 @@ func (g *groupAppImpl) AddMember(ctx context.Context, groupId uint, userIds []ui
 }
 
 func (g *groupAppImpl) DeleteMember(ctx context.Context, groupId uint, userIds []uint) error {
-	curShip, err := g.group.GetGroupShipByUserId(ctx, groupId, request.GetCurrentUser(ctx))
+	curShip, err := g.authorize(ctx, groupId, consts.GroupActionRemove)
+	if err != nil {
+		return err
+	}
+	before, err := g.memberRoles(ctx, groupId, userIds)
 	if err != nil {
 		return err
 	}
+	// 只能移除角色低于自己的成员
+	for userId, role := range before {
+		if role >= curShip.Role {
+			delete(before, userId)
+		}
+	}
 
-	return g.group.DeleteMember(ctx, groupId, userIds, curShip.Role)
+	if err = g.group.DeleteMember(ctx, groupId, userIds, curShip.Role); err != nil {
+		return err
+	}
+	g.auditMember(ctx, groupId, consts.AuditActionGroupDeleteMember, before, nil)
+	return nil
 }
 
 func (g *groupAppImpl) isUserExist(ctx context.Context, userIds []uint) (bool, error) {
@@ -124,35 +270,52 @@ func (g *groupAppImpl) isUserExist(ctx context.Context, userIds []uint) (bool, e
 }
 
 func (g *groupAppImpl) AddAdmin(ctx context.Context, groupId uint, userId []uint) error {
-	group, err := g.group.GetGroupById(ctx, groupId)
+	if _, err := g.authorize(ctx, groupId, consts.GroupActionManageAdmin); err != nil {
+		return err
+	}
+	before, err := g.memberRoles(ctx, groupId, userId)
 	if err != nil {
 		return err
 	}
-
-	if group.ID == 0 || group.OwnerId != request.GetCurrentUser(ctx) {
-		return consts.ErrNoPermission
+	if err = g.group.AddAdmin(ctx, groupId, userId); err != nil {
+		return err
 	}
-	return g.group.AddAdmin(ctx, groupId, userId)
+	after := make(map[uint]uint, len(before))
+	for id := range before {
+		after[id] = consts.GroupRoleAdmin
+	}
+	g.auditMember(ctx, groupId, consts.AuditActionGroupAddAdmin, before, after)
+	return nil
 }
 
 func (g *groupAppImpl) DeleteAdmin(ctx context.Context, groupId, userId uint) error {
-	group, err := g.group.GetGroupById(ctx, groupId)
+	if _, err := g.authorize(ctx, groupId, consts.GroupActionManageAdmin); err != nil {
+		return err
+	}
+	before, err := g.memberRoles(ctx, groupId, []uint{userId})
 	if err != nil {
 		return err
 	}
 
-	if group.ID == 0 || group.OwnerId != request.GetCurrentUser(ctx) {
-		return consts.ErrNoPermission
+	if err = g.group.DeleteAdmin(ctx, groupId, userId); err != nil {
+		return err
 	}
-
-	return g.group.DeleteAdmin(ctx, groupId, userId)
+	g.auditMember(ctx, groupId, consts.AuditActionGroupDeleteAdmin, before, map[uint]uint{userId: consts.GroupRoleMember})
+	return nil
 }
 
 func (g *groupAppImpl) GetGroup(ctx context.Context, groupId uint) (*dto.Group, error) {
+	curShip, err := g.authorize(ctx, groupId, consts.GroupActionView)
+	if err != nil {
+		return nil, err
+	}
 	group, err := g.group.GetGroupById(ctx, groupId)
 	if err != nil {
 		return nil, err
 	}
+	group.Remark = curShip.GroupRemark
+	group.MyNickname = curShip.Remark
+
 	ship, err := g.group.GetGroupShipByRole(ctx, groupId, consts.GroupRoleAdmin)
 	if err != nil {
 		return nil, err
@@ -164,35 +327,62 @@ func (g *groupAppImpl) GetGroup(ctx context.Context, groupId uint) (*dto.Group,
 
 	group.AdminIds = userIds
 
+	group.Permission, err = g.group.GetGroupPermission(ctx, groupId)
+	if err != nil {
+		return nil, err
+	}
+
 	return group, nil
 }
 
 func (g *groupAppImpl) GetGroupMemberList(ctx context.Context, groupId uint) ([]*param.Member, error) {
+	if _, err := g.authorize(ctx, groupId, consts.GroupActionView); err != nil {
+		return nil, err
+	}
 	ships, err := g.group.GetGroupShip(ctx, groupId)
 	if err != nil {
 		return nil, err
 	}
+	return g.convertMember(ctx, ships)
+}
+
+// convertMember 组装群成员信息，设置了群昵称的成员展示群昵称
+func (g *groupAppImpl) convertMember(ctx context.Context, ships []*dto.GroupShip) ([]*param.Member, error) {
 	userIds := make([]uint, 0, len(ships))
-	shipMap := make(map[uint]uint, len(ships))
+	shipMap := make(map[uint]*dto.GroupShip, len(ships))
 	for _, ship := range ships {
 		userIds = append(userIds, ship.UserId)
-		shipMap[ship.UserId] = ship.Role
+		shipMap[ship.UserId] = ship
 	}
 	users, err := g.user.GetUserListByUserIds(ctx, userIds)
 	if err != nil {
 		return nil, err
 	}
+	curUserId := request.GetCurrentUser(ctx)
+	friends, err := g.friend.GetFriendShipList(ctx, curUserId)
+	if err != nil {
+		return nil, err
+	}
+	friendMap := lo.KeyBy(friends, func(f *dto.FriendShip) uint { return f.FriendId })
 
 	members := make([]*param.Member, 0, len(users))
 	for _, user := range users {
+		_, isFriend := friendMap[user.ID]
+		maskProfile(user, curUserId, isFriend)
+		ship := shipMap[user.ID]
+		nickname := user.Nickname
+		if ship.Remark != "" {
+			nickname = ship.Remark
+		}
 		members = append(members, &param.Member{
-			UserID:    user.ID,
-			Nickname:  user.Nickname,
-			Avatar:    user.Avatar,
-			Signature: user.Signature,
-			Gender:    user.Gender,
-			Age:       user.Age,
-			Role:      shipMap[user.ID],
+			UserID:        user.ID,
+			Nickname:      nickname,
+			GroupNickname: ship.Remark,
+			Avatar:        user.Avatar,
+			Signature:     user.Signature,
+			Gender:        user.Gender,
+			Age:           user.Age,
+			Role:          ship.Role,
 		})
 	}
 
@@ -200,7 +390,7 @@ func (g *groupAppImpl) GetGroupMemberList(ctx context.Context, groupId uint) ([]
 }
 
 func (g *groupAppImpl) GetGroupMemberListByLessRole(ctx context.Context, groupId uint) ([]*param.Member, error) {
-	ship, err := g.group.GetGroupShipByUserId(ctx, groupId, request.GetCurrentUser(ctx))
+	ship, err := g.authorize(ctx, groupId, consts.GroupActionView)
 	if err != nil {
 		return nil, err
 	}
@@ -209,51 +399,186 @@ func (g *groupAppImpl) GetGroupMemberListByLessRole(ctx context.Context, groupId
 	if err != nil {
 		return nil, err
 	}
-	userIds := make([]uint, 0, len(ships))
-	shipMap := make(map[uint]uint, len(ships))
-	for _, ship := range ships {
-		userIds = append(userIds, ship.UserId)
-		shipMap[ship.UserId] = ship.Role
+	return g.convertMember(ctx, ships)
+}
+
+func (g *groupAppImpl) ExitGroup(ctx context.Context, groupId uint) error {
+	ship, err := g.authorize(ctx, groupId, consts.GroupActionView)
+	if err != nil {
+		return err
 	}
-	users, err := g.user.GetUserListByUserIds(ctx, userIds)
+	userId := request.GetCurrentUser(ctx)
+	if ship.Role != consts.GroupRoleOwner {
+		return g.group.DeleteMember(ctx, groupId, []uint{userId}, consts.GroupRoleOwner)
+	}
+
+	// 群主退群自动转让群主，群内没有其他成员时直接解散
+	successor, err := g.group.OwnerExitGroup(ctx, groupId, userId)
+	if err != nil || successor != 0 {
+		return err
+	}
+	group, err := g.group.GetGroupById(ctx, groupId)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	return g.dissolveGroup(ctx, group)
+}
 
-	members := make([]*param.Member, 0, len(users))
-	for _, user := range users {
-		members = append(members, &param.Member{
-			UserID:    user.ID,
-			Nickname:  user.Nickname,
-			Avatar:    user.Avatar,
-			Signature: user.Signature,
-			Gender:    user.Gender,
-			Age:       user.Age,
-			Role:      shipMap[user.ID],
-		})
+func (g *groupAppImpl) TransferGroupOwner(ctx context.Context, groupId uint, userId uint) error {
+	if _, err := g.authorize(ctx, groupId, consts.GroupActionTransfer); err != nil {
+		return err
 	}
+	curOwner := request.GetCurrentUser(ctx)
+	if err := g.group.TransferGroupOwner(ctx, groupId, curOwner, userId); err != nil {
+		return err
+	}
+	g.auditMember(ctx, groupId, consts.AuditActionGroupTransferOwner,
+		map[string]uint{"owner_id": curOwner}, map[string]uint{"owner_id": userId})
+	return nil
+}
 
-	return members, nil
+func (g *groupAppImpl) UpdateGroupNickname(ctx context.Context, groupId uint, nickname string) error {
+	if _, err := g.authorize(ctx, groupId, consts.GroupActionView); err != nil {
+		return err
+	}
+	return g.group.UpdateGroupNickname(ctx, groupId, request.GetCurrentUser(ctx), nickname)
 }
 
-func (g *groupAppImpl) ExitGroup(ctx context.Context, groupId uint) error {
-	group, err := g.group.GetGroupById(ctx, groupId)
-	if err != nil {
+func (g *groupAppImpl) UpdateGroupRemark(ctx context.Context, groupId uint, remark string) error {
+	if _, err := g.authorize(ctx, groupId, consts.GroupActionView); err != nil {
 		return err
 	}
-	if group.OwnerId != request.GetCurrentUser(ctx) {
-		return g.group.DeleteGroup(ctx, groupId)
+	return g.group.UpdateGroupRemark(ctx, groupId, request.GetCurrentUser(ctx), remark)
+}
+
+func (g *groupAppImpl) UpgradeGroup(ctx context.Context, groupId uint, groupType int) error {
+	if _, err := g.authorize(ctx, groupId, consts.GroupActionUpgrade); err != nil {
+		return err
 	}
-	return g.group.DeleteMember(ctx, groupId, []uint{request.GetCurrentUser(ctx)}, consts.GroupRoleOwner)
+	return g.group.UpgradeGroup(ctx, groupId, groupType)
 }
 
-func (g *groupAppImpl) TransferGroupOwner(ctx context.Context, groupId uint, userId uint) error {
+func (g *groupAppImpl) SearchGroup(ctx context.Context, p *param.SearchGroupRequest) (*dto.GroupSearchResult, error) {
+	p.Init()
+	return g.group.SearchGroup(ctx, p.Keyword, p.Category, p.Offset(), p.PageSize)
+}
+
+func (g *groupAppImpl) UpdateGroupDiscovery(ctx context.Context, p *param.UpdateGroupDiscoveryRequest) error {
+	if _, err := g.authorize(ctx, p.GroupId, consts.GroupActionDiscovery); err != nil {
+		return err
+	}
+	return g.group.UpdateGroupDiscovery(ctx, &dto.Group{
+		ID:         p.GroupId,
+		IsPublic:   p.IsPublic,
+		Tags:       lo.Uniq(p.Tags),
+		Category:   p.Category,
+		JoinPolicy: p.JoinPolicy,
+	})
+}
+
+// JoinGroup 主动加入公开群，返回是否已入群；需审批的群会生成入群申请
+func (g *groupAppImpl) JoinGroup(ctx context.Context, groupId uint, message string) (bool, error) {
+	userId := request.GetCurrentUser(ctx)
 	group, err := g.group.GetGroupById(ctx, groupId)
+	if err != nil {
+		return false, err
+	}
+	if group.ID == 0 || !group.IsPublic {
+		return false, consts.ErrGroupJoinForbidden
+	}
+	ship, err := g.group.GetGroupShipByUserId(ctx, groupId, userId)
+	if err != nil {
+		return false, err
+	}
+	if ship.ID != 0 {
+		return true, nil
+	}
+
+	switch group.JoinPolicy {
+	case consts.GroupJoinPolicyOpen:
+		err = g.group.AddMember(ctx, []*dto.GroupShip{{GroupId: groupId, UserId: userId, Role: consts.GroupRoleMember}})
+		return err == nil, err
+	case consts.GroupJoinPolicyApproval:
+		// 被拒绝后冷却期内不允许重新申请，避免覆盖拒绝记录反复打扰管理员
+		req, err := g.group.GetGroupJoinRequest(ctx, groupId, userId)
+		if err != nil {
+			return false, err
+		}
+		if req.ID != 0 && req.Status == consts.GroupJoinRequestStatusRefuse && req.UpdatedAt != nil &&
+			time.Since(*req.UpdatedAt) < consts.GroupJoinRequestCooldownHours*time.Hour {
+			return false, consts.ErrGroupJoinRequestCooldown
+		}
+		return false, g.group.SaveGroupJoinRequest(ctx, &dto.GroupJoinRequest{
+			GroupId: groupId,
+			UserId:  userId,
+			Status:  consts.GroupJoinRequestStatusUntreated,
+			Message: message,
+		})
+	default:
+		return false, consts.ErrGroupJoinForbidden
+	}
+}
+
+func (g *groupAppImpl) GetGroupJoinRequestList(ctx context.Context, groupId uint) ([]*dto.GroupJoinRequest, error) {
+	if _, err := g.authorize(ctx, groupId, consts.GroupActionInvite); err != nil {
+		return nil, err
+	}
+	list, err := g.group.GetGroupJoinRequestList(ctx, groupId)
+	if err != nil {
+		return nil, err
+	}
+	userIds := make([]uint, 0, len(list))
+	for _, req := range list {
+		userIds = append(userIds, req.UserId)
+	}
+	users, err := g.user.GetUserListByUserIds(ctx, userIds)
+	if err != nil {
+		return nil, err
+	}
+	userMap := make(map[uint]*dto.User, len(users))
+	for _, user := range users {
+		userMap[user.ID] = user
+	}
+	for _, req := range list {
+		if user, ok := userMap[req.UserId]; ok {
+			req.Nickname = user.Nickname
+			req.Avatar = user.Avatar
+		}
+	}
+	return list, nil
+}
+
+func (g *groupAppImpl) DisposeGroupJoinRequest(ctx context.Context, groupId, userId uint, status int) error {
+	if _, err := g.authorize(ctx, groupId, consts.GroupActionInvite); err != nil {
+		return err
+	}
+	req, err := g.group.GetGroupJoinRequest(ctx, groupId, userId)
 	if err != nil {
 		return err
 	}
-	if group.OwnerId != request.GetCurrentUser(ctx) {
-		return consts.ErrNoPermission
+	if req.ID == 0 || req.Status != consts.GroupJoinRequestStatusUntreated {
+		return nil
+	}
+	if status == consts.GroupJoinRequestStatusAgree {
+		err = g.group.AddMember(ctx, []*dto.GroupShip{{GroupId: groupId, UserId: userId, Role: consts.GroupRoleMember}})
+		if err != nil {
+			return err
+		}
+	}
+	req.Status = status
+	return g.group.UpdateGroupJoinRequest(ctx, req)
+}
+
+func (g *groupAppImpl) GetGroupPermission(ctx context.Context, groupId uint) (map[string]uint, error) {
+	if _, err := g.authorize(ctx, groupId, consts.GroupActionView); err != nil {
+		return nil, err
+	}
+	return g.group.GetGroupPermission(ctx, groupId)
+}
+
+func (g *groupAppImpl) UpdateGroupPermission(ctx context.Context, groupId uint, permission map[string]uint) error {
+	if _, err := g.authorize(ctx, groupId, consts.GroupActionSetPermission); err != nil {
+		return err
 	}
-	return g.group.TransferGroupOwner(ctx, groupId, request.GetCurrentUser(ctx), userId)
+	return g.group.UpdateGroupPermission(ctx, groupId, permission)
 }
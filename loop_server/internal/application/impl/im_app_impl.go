@@ -3,8 +3,13 @@ package impl
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/samber/lo"
 	"log/slog"
 	"loop_server/infra/consts"
+	"loop_server/infra/middleware"
+	"loop_server/infra/ratelimit"
 	"loop_server/infra/redis"
 	"loop_server/infra/vars"
 	"loop_server/infra/ws"
@@ -12,35 +17,114 @@ import (
 	"loop_server/internal/domain"
 	"loop_server/internal/model/dto"
 	"loop_server/internal/model/po"
+	"loop_server/pkg/request"
 	"strings"
+	"time"
 )
 
 type imAppImpl struct {
-	sfuApp      application.SfuAPP
-	imDomain    domain.ImDomain
-	groupDomain domain.GroupDomain
-	userDomain  domain.UserDomain
+	sfuApp       application.SfuAPP
+	imDomain     domain.ImDomain
+	groupDomain  domain.GroupDomain
+	userDomain   domain.UserDomain
+	friendDomain domain.FriendDomain
+	moderation   domain.ModerationDomain
 }
 
-func NewImAppImpl(sfuApp application.SfuAPP, imDomain domain.ImDomain, groupDomain domain.GroupDomain, userDomain domain.UserDomain) *imAppImpl {
-	return &imAppImpl{sfuApp: sfuApp, imDomain: imDomain, groupDomain: groupDomain, userDomain: userDomain}
+func NewImAppImpl(sfuApp application.SfuAPP, imDomain domain.ImDomain, groupDomain domain.GroupDomain, userDomain domain.UserDomain, friendDomain domain.FriendDomain, moderation domain.ModerationDomain) *imAppImpl {
+	return &imAppImpl{sfuApp: sfuApp, imDomain: imDomain, groupDomain: groupDomain, userDomain: userDomain, friendDomain: friendDomain, moderation: moderation}
 }
 
-func (i *imAppImpl) HandleMessage(ctx context.Context, curUserId uint, msgByte []byte) error {
+// Authenticate 处理认证帧，校验通过后记录连接的用户和 token 过期时间并回复认证结果
+// 已认证的连接重新认证时必须属于同一会话
+func (i *imAppImpl) Authenticate(ctx context.Context, client *ws.Client, msgByte []byte) error {
+	msg := &dto.Message{}
+	if err := json.Unmarshal(msgByte, msg); err != nil || msg.Cmd != consts.WsMessageCmdAuth || msg.Token == "" {
+		i.writeFrame(client, consts.WsMessageCmdError, &dto.WsError{Cmd: consts.WsMessageCmdAuth, Reason: consts.WsErrorReasonUnauthorized})
+		return consts.ErrWsUnauthorized
+	}
+	claims, err := middleware.ValidateAccessToken(ctx, msg.Token)
+	if err == nil && client.UserId != 0 && (client.UserId != claims.UserClaims.ID || client.SessionId != claims.UserClaims.SessionId) {
+		err = consts.ErrWsUnauthorized
+	}
+	if err != nil {
+		i.writeFrame(client, consts.WsMessageCmdError, &dto.WsError{Cmd: consts.WsMessageCmdAuth, Reason: consts.WsErrorReasonUnauthorized})
+		return consts.ErrWsUnauthorized
+	}
+	// 重新认证必须携带刷新后的 token，否则过期时间不变，连接仍会因超时被断开
+	if client.UserId != 0 && !claims.ExpiresAt.Time.After(client.ExpiresAt()) {
+		i.writeFrame(client, consts.WsMessageCmdError, &dto.WsError{Cmd: consts.WsMessageCmdAuth, Reason: consts.WsErrorReasonTokenNotRenewed})
+		return consts.ErrWsUnauthorized
+	}
+
+	// UserId、SessionId 只在首次认证时写入，之后会被其他 goroutine 并发读取
+	if client.UserId == 0 {
+		client.UserId = claims.UserClaims.ID
+		client.SessionId = claims.UserClaims.SessionId
+	}
+	client.SetExpiresAt(claims.ExpiresAt.Time)
+	return i.writeFrame(client, consts.WsMessageCmdAuth, &dto.WsAuthResult{
+		UserId:    client.UserId,
+		ExpiresAt: claims.ExpiresAt.Unix(),
+	})
+}
+
+// RequestReauth 通知客户端在 access token 过期前刷新并重新认证
+func (i *imAppImpl) RequestReauth(ctx context.Context, client *ws.Client) error {
+	return i.writeFrame(client, consts.WsMessageCmdReauth, &dto.WsAuthResult{
+		UserId:    client.UserId,
+		ExpiresAt: client.ExpiresAt().Unix(),
+	})
+}
+
+// writeFrame 直接写入当前连接，认证完成前连接尚未加入在线列表
+func (i *imAppImpl) writeFrame(client *ws.Client, cmd int, data any) error {
+	dataByte, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	msgByte, err := json.Marshal(dto.Message{Cmd: cmd, Data: dataByte})
+	if err != nil {
+		return err
+	}
+	return client.Write(msgByte)
+}
+
+func (i *imAppImpl) HandleMessage(ctx context.Context, client *ws.Client, msgByte []byte) error {
 	msg := &dto.Message{}
 	if err := json.Unmarshal(msgByte, msg); err != nil {
 		slog.Error("message unmarshal err:", err)
 		return err
 	}
+	if msg.Cmd == consts.WsMessageCmdAuth {
+		return i.Authenticate(ctx, client, msgByte)
+	}
+	if !client.Authenticated() {
+		return i.writeFrame(client, consts.WsMessageCmdError, &dto.WsError{Cmd: msg.Cmd, Reason: consts.WsErrorReasonTokenExpired})
+	}
+	curUserId := client.UserId
+	if scope, ok := consts.WsCmdRateLimitScope[msg.Cmd]; ok {
+		subjects := []string{fmt.Sprintf("user:%d", curUserId)}
+		if ip, _, _ := request.GetClientInfo(ctx); ip != "" {
+			subjects = append(subjects, "ip:"+ip)
+		}
+		if allowed, wait := ratelimit.Allow(ctx, scope, subjects...); !allowed {
+			return i.writeFrame(client, consts.WsMessageCmdError, &dto.WsError{
+				Cmd:        msg.Cmd,
+				Reason:     consts.WsErrorReasonRateLimited,
+				RetryAfter: wait.Milliseconds(),
+			})
+		}
+	}
 	switch msg.Cmd {
 	case consts.WsMessageCmdHeartbeat:
 		return i.handleHeartbeat(ctx, curUserId, msgByte)
 	case consts.WsMessageCmdPrivateMessage:
-		return i.handlePrivateMessage(ctx, msg)
+		return i.handlePrivateMessage(ctx, curUserId, msg)
 	case consts.WsMessageCmdAck:
-		return i.handlerAck(ctx, msg)
+		return i.handlerAck(ctx, curUserId, msg)
 	case consts.WsMessageCmdGroupMessage:
-		return i.handlerGroupMessage(ctx, msg)
+		return i.handlerGroupMessage(ctx, curUserId, msg)
 	case consts.WsMessageCmdPrivateOffer, consts.WsMessageCmdPrivateAnswer, consts.WsMessageCmdPrivateIce, consts.WsMessageCmdPrivateHangUp:
 		return i.handlerPrivateOffer(ctx, msg)
 	case consts.WsMessageCmdGroupInitiatorOffer:
@@ -59,15 +143,104 @@ func (i *imAppImpl) handlerPrivateOffer(ctx context.Context, msg *dto.Message) e
 		return err
 	}
 
+	// 被对方拉黑或对方不允许通话时按对方挂断处理
+	senderId := request.GetCurrentUser(ctx)
+	if _, err := i.stampSender(ctx, senderId, &sdpMessage.SenderId, &sdpMessage.SenderNickname, &sdpMessage.SenderAvatar); err != nil {
+		return err
+	}
+	allowed, err := i.allowPrivateCall(ctx, sdpMessage.ReceiverId, senderId)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		if msg.Cmd != consts.WsMessageCmdPrivateOffer {
+			return nil
+		}
+		return i.imDomain.SendMessage(ctx, consts.WsMessageCmdPrivateHangUp, senderId, &dto.WebRTCMessage{
+			SenderId:   sdpMessage.ReceiverId,
+			ReceiverId: senderId,
+			MediaType:  sdpMessage.MediaType,
+		})
+	}
+
 	return i.imDomain.SendMessage(ctx, msg.Cmd, sdpMessage.ReceiverId, sdpMessage)
 }
 
-func (i *imAppImpl) handlerGroupMessage(ctx context.Context, msg *dto.Message) error {
+// allowPrivateCall 判断 senderId 是否可以向 receiverId 发起通话
+func (i *imAppImpl) allowPrivateCall(ctx context.Context, receiverId, senderId uint) (bool, error) {
+	blocked, err := i.friendDomain.IsBlocked(ctx, receiverId, senderId)
+	if err != nil || blocked {
+		return false, err
+	}
+	receiver, err := i.userDomain.QueryUser(ctx, &dto.QueryUserRequest{UserId: receiverId})
+	if err != nil {
+		return false, err
+	}
+	if receiver.ID == 0 || receiver.Privacy.CallScope == consts.PrivacyScopeEveryone {
+		return true, nil
+	}
+	isFriend, err := i.friendDomain.IsFriend(ctx, receiverId, senderId)
+	if err != nil {
+		return false, err
+	}
+	return allowScope(receiver.Privacy.CallScope, isFriend), nil
+}
+
+func (i *imAppImpl) handlerGroupMessage(ctx context.Context, curUserId uint, msg *dto.Message) error {
 	gMsg := &dto.GroupMessage{}
 	json.Unmarshal(msg.Data, gMsg)
 	if gMsg.SeqId == "" || gMsg.ReceiverId == 0 {
 		return nil
 	}
+	sender, err := i.stampSender(ctx, curUserId, &gMsg.SenderId, &gMsg.SenderNickname, &gMsg.SenderAvatar)
+	if err != nil {
+		return err
+	}
+	if sender.MutedUntil != nil && time.Now().Before(*sender.MutedUntil) {
+		return i.imDomain.SendAck(ctx, &dto.Ack{
+			SeqId:      gMsg.SeqId,
+			SenderId:   gMsg.ReceiverId,
+			ReceiverId: gMsg.SenderId,
+			IsGroup:    consts.AckGroupMessage,
+			Code:       consts.AckCodeMuted,
+		})
+	}
+
+	// 只有群成员可以发送群消息
+	ship, err := i.groupDomain.GetGroupShipByUserId(ctx, gMsg.ReceiverId, gMsg.SenderId)
+	if err != nil {
+		return err
+	}
+	if ship.ID == 0 {
+		return i.imDomain.SendAck(ctx, &dto.Ack{
+			SeqId:      gMsg.SeqId,
+			SenderId:   gMsg.ReceiverId,
+			ReceiverId: gMsg.SenderId,
+			IsGroup:    consts.AckGroupMessage,
+			Code:       consts.AckCodeNotGroupMember,
+		})
+	}
+	if gMsg.Type == consts.GroupMessageTypeText {
+		allowed, err := moderateText(ctx, i.moderation, consts.ModerationSceneGroupMessage, gMsg.SenderId, gMsg.SeqId, &gMsg.Content)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return i.imDomain.SendAck(ctx, &dto.Ack{
+				SeqId:      gMsg.SeqId,
+				SenderId:   gMsg.ReceiverId,
+				ReceiverId: gMsg.SenderId,
+				IsGroup:    consts.AckGroupMessage,
+				Code:       consts.AckCodeContentRejected,
+			})
+		}
+	}
+	// 无@所有人权限时降级为普通消息
+	if gMsg.AtAll {
+		if _, err := i.groupDomain.CheckPermission(ctx, gMsg.ReceiverId, gMsg.SenderId, consts.GroupActionAtAll); err != nil {
+			gMsg.AtAll = false
+		}
+	}
 
 	if err := i.imDomain.SaveGroupMessage(ctx, &po.GroupMessage{
 		GroupId:  gMsg.ReceiverId,
@@ -107,11 +280,23 @@ func (i *imAppImpl) groupMessageInfoOnlineUser(ctx context.Context, gMsg *dto.Gr
 	gMsg.GroupName = group.Name
 	gMsg.GroupAvatar = group.Avatar
 
+	// 发送者设置了群昵称时使用群昵称
+	ship, err := i.groupDomain.GetGroupShipByUserId(ctx, gMsg.ReceiverId, gMsg.SenderId)
+	if err != nil {
+		return err
+	}
+	if ship.Remark != "" {
+		gMsg.SenderNickname = ship.Remark
+	}
+
 	// 获取群用户id
 	userIds, err := i.groupDomain.GetGroupUserId(ctx, gMsg.ReceiverId)
 	if err != nil {
 		return err
 	}
+	if group.Type == consts.GroupTypeLarge {
+		return i.imDomain.SendLargeGroupMessage(ctx, gMsg, userIds)
+	}
 	if err := i.imDomain.SendGroupMessage(ctx, gMsg, userIds); err != nil {
 		return err
 	}
@@ -122,7 +307,33 @@ func (i *imAppImpl) handleHeartbeat(ctx context.Context, curUserId uint, msgByte
 	return i.imDomain.HandleHeartbeat(ctx, curUserId, msgByte)
 }
 
-func (i *imAppImpl) handlePrivateMessage(ctx context.Context, msg *dto.Message) error {
+// moderateText 审核文本，命中 mask 规则时替换 content，返回 false 表示拒绝
+func moderateText(ctx context.Context, moderation domain.ModerationDomain, scene string, userId uint, targetId string, content *string) (bool, error) {
+	if *content == "" {
+		return true, nil
+	}
+	res, err := moderation.Check(ctx, &dto.ModerationItem{Scene: scene, UserId: userId, TargetId: targetId, Content: *content})
+	if err != nil {
+		return false, err
+	}
+	if res.Action == consts.ModerationActionReject {
+		return false, nil
+	}
+	*content = res.Content
+	return true, nil
+}
+
+// stampSender 使用连接认证的用户覆盖客户端传入的发送者信息，返回发送者
+func (i *imAppImpl) stampSender(ctx context.Context, userId uint, senderId *uint, nickname, avatar *string) (*dto.User, error) {
+	user, err := i.userDomain.QueryUser(ctx, &dto.QueryUserRequest{UserId: userId})
+	if err != nil {
+		return nil, err
+	}
+	*senderId, *nickname, *avatar = userId, user.Nickname, user.Avatar
+	return user, nil
+}
+
+func (i *imAppImpl) handlePrivateMessage(ctx context.Context, curUserId uint, msg *dto.Message) error {
 	/*
 		A —> B 发送消息：
 		1. 在线转发
@@ -133,12 +344,82 @@ func (i *imAppImpl) handlePrivateMessage(ctx context.Context, msg *dto.Message)
 	if pMsg.SeqId == "" || pMsg.ReceiverId == 0 {
 		return nil
 	}
+	sender, err := i.stampSender(ctx, curUserId, &pMsg.SenderId, &pMsg.SenderNickname, &pMsg.SenderAvatar)
+	if err != nil {
+		return err
+	}
+	if sender.MutedUntil != nil && time.Now().Before(*sender.MutedUntil) {
+		return i.imDomain.SendAck(ctx, &dto.Ack{
+			SeqId:      pMsg.SeqId,
+			SenderId:   pMsg.ReceiverId,
+			ReceiverId: pMsg.SenderId,
+			Code:       consts.AckCodeMuted,
+		})
+	}
+
+	// 被对方拉黑时回复拒收应答，不暴露拉黑状态
+	blocked, err := i.friendDomain.IsBlocked(ctx, pMsg.ReceiverId, pMsg.SenderId)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return i.imDomain.SendAck(ctx, &dto.Ack{
+			SeqId:      pMsg.SeqId,
+			SenderId:   pMsg.ReceiverId,
+			ReceiverId: pMsg.SenderId,
+			Code:       consts.AckCodeRejected,
+		})
+	}
+
+	// 对方不接收陌生人消息
+	receiver, err := i.userDomain.QueryUser(ctx, &dto.QueryUserRequest{UserId: pMsg.ReceiverId})
+	if err != nil {
+		return err
+	}
+	if receiver.ID == 0 {
+		return i.imDomain.SendAck(ctx, &dto.Ack{
+			SeqId:      pMsg.SeqId,
+			SenderId:   pMsg.ReceiverId,
+			ReceiverId: pMsg.SenderId,
+			Code:       consts.AckCodeReceiverNotExist,
+		})
+	}
+	if !receiver.Privacy.StrangerMessage {
+		isFriend, err := i.friendDomain.IsFriend(ctx, pMsg.ReceiverId, pMsg.SenderId)
+		if err != nil {
+			return err
+		}
+		if !isFriend {
+			return i.imDomain.SendAck(ctx, &dto.Ack{
+				SeqId:      pMsg.SeqId,
+				SenderId:   pMsg.ReceiverId,
+				ReceiverId: pMsg.SenderId,
+				Code:       consts.AckCodeStrangerForbidden,
+			})
+		}
+	}
+
+	// 私聊消息与群消息的类型取值一致
+	if pMsg.Type == consts.GroupMessageTypeText {
+		allowed, err := moderateText(ctx, i.moderation, consts.ModerationScenePrivateMessage, pMsg.SenderId, pMsg.SeqId, &pMsg.Content)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return i.imDomain.SendAck(ctx, &dto.Ack{
+				SeqId:      pMsg.SeqId,
+				SenderId:   pMsg.ReceiverId,
+				ReceiverId: pMsg.SenderId,
+				Code:       consts.AckCodeContentRejected,
+			})
+		}
+	}
 
 	// 在线
 	if i.imDomain.IsOnline(ctx, pMsg.ReceiverId) {
 		ok, err := i.imDomain.HandleOnlinePrivateMessage(ctx, pMsg)
 		if !ok {
-			i.RemoveOnlineUser(ctx, pMsg.ReceiverId)
+			i.RemoveOnlineUser(ctx, pMsg.ReceiverId, vars.Ws.Get(pMsg.ReceiverId))
 			return i.handleOfflinePrivateMessage(ctx, pMsg)
 		}
 		return err
@@ -159,37 +440,87 @@ func (i *imAppImpl) handleOfflinePrivateMessage(ctx context.Context, message *dt
 	})
 }
 
+// AddOnlineUser 每个用户只保留一个连接，新连接建立后关闭之前的连接
 func (i *imAppImpl) AddOnlineUser(ctx context.Context, client *ws.Client) error {
-	if i.imDomain.IsOnline(ctx, client.UserId) {
-		i.RemoveOnlineUser(ctx, client.UserId)
+	if old := vars.Ws.Swap(client.UserId, client); old != nil && old != client {
+		old.CloseWithReason(consts.WsCloseReplaced, consts.WsErrorReasonReplaced)
 	}
 	if err := vars.Redis.SAdd(ctx, redis.GetOnlineUserKey(), client.UserId).Err(); err != nil {
 		slog.Error("redis set online user err:", err)
 		return err
 	}
-
-	vars.Ws.Set(client.UserId, client)
 	return nil
 }
 
-func (i *imAppImpl) RemoveOnlineUser(ctx context.Context, userId uint) error {
+// RemoveOnlineUser 移除在线状态，client 不为空时仅当它仍是该用户当前的连接才移除
+func (i *imAppImpl) RemoveOnlineUser(ctx context.Context, userId uint, client *ws.Client) error {
+	if client != nil && !vars.Ws.CompareAndDelete(userId, client) {
+		return nil
+	}
 	if err := vars.Redis.SRem(ctx, redis.GetOnlineUserKey(), userId).Err(); err != nil {
 		slog.Error("redis remove online user err:", err)
 		return err
 	}
-	vars.Ws.Delete(userId)
 	return nil
 }
 
-func (i *imAppImpl) handlerAck(ctx context.Context, msg *dto.Message) error {
+func (i *imAppImpl) handlerAck(ctx context.Context, curUserId uint, msg *dto.Message) error {
 	ack := &dto.Ack{}
 	if err := json.Unmarshal(msg.Data, ack); err != nil {
 		slog.Error("imAppImpl.handlerAck ack unmarshal err:", err)
 		return err
 	}
+	// ack 的发送者以连接认证的用户为准，不信任客户端上报的 senderId
+	ack.SenderId = curUserId
+	if ack.IsGroup {
+		group, err := i.groupDomain.GetGroupById(ctx, ack.ReceiverId)
+		if err != nil {
+			return err
+		}
+		if group.Type == consts.GroupTypeLarge {
+			return i.imDomain.HandleLargeGroupAck(ctx, curUserId, ack)
+		}
+	}
 	return i.imDomain.HandleAck(ctx, ack)
 }
 
+// SyncGroupMessage 拉取群内 seqId 之后的消息，大群成员通过该接口补齐消息
+func (i *imAppImpl) SyncGroupMessage(ctx context.Context, userId, groupId uint, seqId string, limit int) ([]*dto.Message, error) {
+	group, err := i.readableGroup(ctx, userId, groupId)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = consts.GroupMessageSyncLimit
+	}
+	limit = min(limit, consts.GroupMessageSyncMaxLimit)
+
+	gmsg, err := i.imDomain.GetGroupMessageAfterSeqId(ctx, groupId, userId, seqId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return i.convertGroupMessage(ctx, gmsg, map[uint]*dto.Group{groupId: group})
+}
+
+// readableGroup 获取用户可拉取消息的群，已解散并归档的群仅解散时的成员可只读查看
+func (i *imAppImpl) readableGroup(ctx context.Context, userId, groupId uint) (*dto.Group, error) {
+	_, err := i.groupDomain.CheckPermission(ctx, groupId, userId, consts.GroupActionView)
+	if err == nil {
+		return i.groupDomain.GetGroupById(ctx, groupId)
+	}
+	if !errors.Is(err, consts.ErrNoPermission) {
+		return nil, err
+	}
+	group, err := i.groupDomain.GetArchivedGroup(ctx, groupId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, consts.ErrNoPermission
+	}
+	return group, nil
+}
+
 func (i *imAppImpl) GetOfflineMessage(ctx context.Context, userId uint) ([]*dto.Message, error) {
 	msg, err := i.imDomain.GetOfflinePrivateMessage(ctx, userId)
 	if err != nil {
@@ -210,16 +541,25 @@ func (i *imAppImpl) getOfflineGroupMessage(ctx context.Context, userId uint) ([]
 	if err != nil {
 		return nil, err
 	}
-	groupIdList := make([]uint, len(groupList))
+	// 大群不走逐个成员的离线消息，由客户端按游标调用 SyncGroupMessage 拉取
+	groupIdList := make([]uint, 0, len(groupList))
 	groupHash := make(map[uint]*dto.Group, len(groupList))
-	for i, group := range groupList {
-		groupIdList[i] = group.ID
+	for _, group := range groupList {
+		if group.Type == consts.GroupTypeLarge {
+			continue
+		}
+		groupIdList = append(groupIdList, group.ID)
 		groupHash[group.ID] = group
 	}
 	gmsg, err := i.imDomain.GetOfflineGroupMessage(ctx, groupIdList, userId)
 	if err != nil {
 		return nil, err
 	}
+	return i.convertGroupMessage(ctx, gmsg, groupHash)
+}
+
+// convertGroupMessage 将群消息组装为ws消息，填充发送者与群信息
+func (i *imAppImpl) convertGroupMessage(ctx context.Context, gmsg []*po.GroupMessage, groupHash map[uint]*dto.Group) ([]*dto.Message, error) {
 	userIdMap := make(map[uint]*dto.User)
 	for _, message := range gmsg {
 		userIdMap[message.SenderId] = nil
@@ -235,9 +575,36 @@ func (i *imAppImpl) getOfflineGroupMessage(ctx context.Context, userId uint) ([]
 	for _, user := range userList {
 		userIdMap[user.ID] = user
 	}
+	// 已注销用户的消息发送者查不到，使用占位信息
+	for id, user := range userIdMap {
+		if user == nil {
+			userIdMap[id] = &dto.User{ID: id, Nickname: consts.DeletedUserNickname}
+		}
+	}
+
+	// 发送者的群昵称 groupId -> userId -> 群昵称
+	groupSenders := make(map[uint][]uint)
+	for _, message := range gmsg {
+		groupSenders[message.GroupId] = append(groupSenders[message.GroupId], message.SenderId)
+	}
+	groupNickname := make(map[uint]map[uint]string, len(groupSenders))
+	for groupId, senderIds := range groupSenders {
+		ships, err := i.groupDomain.GetGroupShipByUserIds(ctx, groupId, lo.Uniq(senderIds))
+		if err != nil {
+			return nil, err
+		}
+		groupNickname[groupId] = make(map[uint]string, len(ships))
+		for _, ship := range ships {
+			groupNickname[groupId][ship.UserId] = ship.Remark
+		}
+	}
 
 	resp := make([]*dto.Message, 0, len(gmsg))
 	for _, message := range gmsg {
+		nickname := userIdMap[message.SenderId].Nickname
+		if remark := groupNickname[message.GroupId][message.SenderId]; remark != "" {
+			nickname = remark
+		}
 		data := &dto.GroupMessage{
 			SeqId:          message.SeqId,
 			SenderId:       message.SenderId,
@@ -245,7 +612,7 @@ func (i *imAppImpl) getOfflineGroupMessage(ctx context.Context, userId uint) ([]
 			Content:        message.Content,
 			Type:           message.Type,
 			SendTime:       message.SendTime,
-			SenderNickname: userIdMap[message.SenderId].Nickname,
+			SenderNickname: nickname,
 			SenderAvatar:   userIdMap[message.SenderId].Avatar,
 			GroupName:      groupHash[message.GroupId].Name,
 			GroupAvatar:    groupHash[message.GroupId].Avatar,
@@ -270,6 +637,12 @@ func (i *imAppImpl) handlerGroupOffer(ctx context.Context, msg *dto.Message) err
 	if len(sdpMessage.ReceiverList) == 0 {
 		return nil
 	}
+	if _, err := i.groupDomain.CheckPermission(ctx, sdpMessage.ReceiverId, request.GetCurrentUser(ctx), consts.GroupActionStartCall); err != nil {
+		return err
+	}
+	if _, err := i.stampSender(ctx, request.GetCurrentUser(ctx), &sdpMessage.SenderId, &sdpMessage.SenderNickname, &sdpMessage.SenderAvatar); err != nil {
+		return err
+	}
 
 	userId := sdpMessage.SenderId
 	answer, err := i.sfuApp.SetOfferGetAnswer(ctx, sdpMessage.ReceiverId, sdpMessage.SenderNickname, sdpMessage.SenderAvatar,
@@ -290,6 +663,9 @@ func (i *imAppImpl) handlerGroupIce(ctx context.Context, msg *dto.Message) error
 		slog.Error("handlerGroupOffer unmarshal err:", err)
 		return err
 	}
+	if _, err := i.stampSender(ctx, request.GetCurrentUser(ctx), &sdpMessage.SenderId, &sdpMessage.SenderNickname, &sdpMessage.SenderAvatar); err != nil {
+		return err
+	}
 	return i.sfuApp.SetIceCandidateInit(ctx, sdpMessage.ReceiverId, sdpMessage.SenderNickname, sdpMessage.SenderAvatar,
 		sdpMessage.ReceiverList, sdpMessage.MediaType, sdpMessage.CandidateInit)
 }
@@ -0,0 +1,210 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gorm.io/gorm"
+	"loop_server/infra/consts"
+	"loop_server/internal/domain"
+	"loop_server/internal/model/dto"
+	"loop_server/internal/model/param"
+	"loop_server/pkg/request"
+	"strconv"
+)
+
+type reportAppImpl struct {
+	reportDomain domain.ReportDomain
+	userDomain   domain.UserDomain
+	groupDomain  domain.GroupDomain
+	imDomain     domain.ImDomain
+}
+
+func NewReportAppImpl(reportDomain domain.ReportDomain, userDomain domain.UserDomain, groupDomain domain.GroupDomain, imDomain domain.ImDomain) *reportAppImpl {
+	return &reportAppImpl{
+		reportDomain: reportDomain,
+		userDomain:   userDomain,
+		groupDomain:  groupDomain,
+		imDomain:     imDomain,
+	}
+}
+
+// CreateReport 举报用户、群或消息，同时保存举报对象及上下文快照，避免对象被修改或删除后无从核查
+func (r *reportAppImpl) CreateReport(ctx context.Context, p *param.CreateReportRequest) error {
+	report := &dto.Report{
+		ReporterId:     request.GetCurrentUser(ctx),
+		TargetType:     p.TargetType,
+		TargetId:       p.TargetId,
+		Reason:         p.Reason,
+		Description:    p.Description,
+		SnapshotSource: consts.ReportSnapshotServer,
+	}
+	var (
+		snapshot *dto.ReportSnapshot
+		err      error
+	)
+	switch p.TargetType {
+	case consts.ReportTargetUser:
+		snapshot, err = r.snapshotUser(ctx, report)
+	case consts.ReportTargetGroup:
+		snapshot, err = r.snapshotGroup(ctx, report)
+	case consts.ReportTargetGroupMessage:
+		snapshot, err = r.snapshotGroupMessage(ctx, report)
+	case consts.ReportTargetPrivateMessage:
+		snapshot, err = r.snapshotPrivateMessage(ctx, report, p.Messages)
+	default:
+		err = consts.ErrReportTargetNotExist
+	}
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	report.Snapshot = string(data)
+	return r.reportDomain.CreateReport(ctx, report)
+}
+
+func (r *reportAppImpl) snapshotUser(ctx context.Context, report *dto.Report) (*dto.ReportSnapshot, error) {
+	userId, err := strconv.ParseUint(report.TargetId, 10, 64)
+	if err != nil || uint(userId) == report.ReporterId {
+		return nil, consts.ErrReportTargetNotExist
+	}
+	user, err := r.publicUser(ctx, uint(userId))
+	if err != nil {
+		return nil, err
+	}
+	report.TargetUserId = user.ID
+	return &dto.ReportSnapshot{User: user}, nil
+}
+
+// snapshotGroup 只能举报自己所在的群或公开的群
+func (r *reportAppImpl) snapshotGroup(ctx context.Context, report *dto.Report) (*dto.ReportSnapshot, error) {
+	groupId, err := strconv.ParseUint(report.TargetId, 10, 64)
+	if err != nil {
+		return nil, consts.ErrReportTargetNotExist
+	}
+	group, err := r.groupDomain.GetGroupById(ctx, uint(groupId))
+	if err != nil {
+		return nil, err
+	}
+	if group.ID == 0 {
+		return nil, consts.ErrReportTargetNotExist
+	}
+	if !group.IsPublic {
+		ship, err := r.groupDomain.GetGroupShipByUserId(ctx, group.ID, report.ReporterId)
+		if err != nil {
+			return nil, err
+		}
+		if ship.ID == 0 {
+			return nil, consts.ErrReportTargetNotExist
+		}
+	}
+	report.TargetUserId, report.GroupId = group.OwnerId, group.ID
+	return &dto.ReportSnapshot{Group: group}, nil
+}
+
+// snapshotGroupMessage 群消息由服务端截取被举报消息前后的上下文
+func (r *reportAppImpl) snapshotGroupMessage(ctx context.Context, report *dto.Report) (*dto.ReportSnapshot, error) {
+	msg, err := r.imDomain.GetGroupMessageBySeqId(ctx, report.TargetId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, consts.ErrReportTargetNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	if msg.SenderId == 0 || msg.SenderId == report.ReporterId {
+		return nil, consts.ErrReportTargetNotExist
+	}
+	ship, err := r.groupDomain.GetGroupShipByUserId(ctx, msg.GroupId, report.ReporterId)
+	if err != nil {
+		return nil, err
+	}
+	if ship.ID == 0 {
+		return nil, consts.ErrReportTargetNotExist
+	}
+
+	around, err := r.imDomain.GetGroupMessageAround(ctx, msg.GroupId, msg.ID, consts.ReportContextMessages)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]*dto.ReportMessage, 0, len(around))
+	for _, m := range around {
+		messages = append(messages, &dto.ReportMessage{
+			SeqId:    m.SeqId,
+			SenderId: m.SenderId,
+			Content:  m.Content,
+			Type:     m.Type,
+			SendTime: m.SendTime,
+			Reported: m.SeqId == msg.SeqId,
+		})
+	}
+	group, err := r.groupDomain.GetGroupById(ctx, msg.GroupId)
+	if err != nil {
+		return nil, err
+	}
+	user, err := r.publicUser(ctx, msg.SenderId)
+	if err != nil {
+		return nil, err
+	}
+	report.TargetUserId, report.GroupId = msg.SenderId, msg.GroupId
+	return &dto.ReportSnapshot{User: user, Group: group, Messages: messages}, nil
+}
+
+// snapshotPrivateMessage 私聊消息不在服务端保存，使用客户端提交的上下文，
+// 上下文只能是举报人与被举报人之间的消息，且被举报的消息必须由对方发出
+func (r *reportAppImpl) snapshotPrivateMessage(ctx context.Context, report *dto.Report, msgs []*param.ReportMessage) (*dto.ReportSnapshot, error) {
+	var reported *param.ReportMessage
+	for _, m := range msgs {
+		if m.SeqId == report.TargetId {
+			reported = m
+			break
+		}
+	}
+	if reported == nil || reported.SenderId == report.ReporterId || reported.ReceiverId != report.ReporterId {
+		return nil, consts.ErrReportTargetNotExist
+	}
+
+	peer := reported.SenderId
+	messages := make([]*dto.ReportMessage, 0, len(msgs))
+	for _, m := range msgs {
+		if !(m.SenderId == peer && m.ReceiverId == report.ReporterId) && !(m.SenderId == report.ReporterId && m.ReceiverId == peer) {
+			return nil, consts.ErrReportTargetNotExist
+		}
+		messages = append(messages, &dto.ReportMessage{
+			SeqId:      m.SeqId,
+			SenderId:   m.SenderId,
+			ReceiverId: m.ReceiverId,
+			Content:    m.Content,
+			Type:       m.Type,
+			SendTime:   m.SendTime,
+			Reported:   m == reported,
+		})
+	}
+	user, err := r.publicUser(ctx, peer)
+	if err != nil {
+		return nil, err
+	}
+	report.TargetUserId, report.SnapshotSource = peer, consts.ReportSnapshotClient
+	return &dto.ReportSnapshot{User: user, Messages: messages}, nil
+}
+
+// publicUser 快照中只保留用户的公开资料
+func (r *reportAppImpl) publicUser(ctx context.Context, userId uint) (*dto.User, error) {
+	user, err := r.userDomain.QueryUser(ctx, &dto.QueryUserRequest{UserId: userId})
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, consts.ErrReportTargetNotExist
+	}
+	return &dto.User{
+		ID:        user.ID,
+		Nickname:  user.Nickname,
+		Avatar:    user.Avatar,
+		Signature: user.Signature,
+		Gender:    user.Gender,
+		Age:       user.Age,
+	}, nil
+}
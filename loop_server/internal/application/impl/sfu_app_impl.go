@@ -8,6 +8,7 @@ import (
 	"loop_server/internal/domain"
 	"loop_server/internal/model/dto"
 	"loop_server/pkg/request"
+	"sort"
 )
 
 type sfuAppImpl struct {
@@ -96,3 +97,15 @@ func (s *sfuAppImpl) SetIceCandidateInit(ctx context.Context, groupId uint, send
 	}
 	return participant.PeerConn.AddICECandidate(*init)
 }
+
+// GetRooms 当前进行中的音视频房间，按群 id 排序
+func (s *sfuAppImpl) GetRooms(ctx context.Context) []*dto.SfuRoom {
+	rooms := make([]*dto.SfuRoom, 0)
+	for groupId, participantIds := range vars.Sfu.GetRooms() {
+		rooms = append(rooms, &dto.SfuRoom{GroupId: groupId, ParticipantIds: participantIds})
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].GroupId < rooms[j].GroupId
+	})
+	return rooms
+}
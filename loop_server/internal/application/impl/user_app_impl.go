@@ -2,44 +2,211 @@ package impl
 
 import (
 	"context"
-	"loop_server/infra/middleware"
+	"errors"
+	"loop_server/infra/consts"
 	"loop_server/internal/domain"
 	"loop_server/internal/model/dto"
 	"loop_server/pkg/bcrypt"
+	"loop_server/pkg/jwt"
 	"loop_server/pkg/request"
+	"strconv"
 )
 
 type userAppImpl struct {
 	userDomain   domain.UserDomain
 	friendDomain domain.FriendDomain
+	verifyDomain domain.VerifyDomain
+	imDomain     domain.ImDomain
+	loginGuard   domain.LoginGuardDomain
+	moderation   domain.ModerationDomain
+	auditDomain  domain.AuditDomain
 }
 
-func NewUserAppImpl(userDomain domain.UserDomain, friendDomain domain.FriendDomain) *userAppImpl {
+func NewUserAppImpl(userDomain domain.UserDomain, friendDomain domain.FriendDomain, verifyDomain domain.VerifyDomain, imDomain domain.ImDomain, loginGuard domain.LoginGuardDomain, moderation domain.ModerationDomain, auditDomain domain.AuditDomain) *userAppImpl {
 	return &userAppImpl{
 		userDomain:   userDomain,
 		friendDomain: friendDomain,
+		verifyDomain: verifyDomain,
+		imDomain:     imDomain,
+		loginGuard:   loginGuard,
+		moderation:   moderation,
+		auditDomain:  auditDomain,
 	}
 }
 
-// Login 登录
-func (u *userAppImpl) Login(ctx context.Context, phone, password string) (*dto.UserLogin, error) {
-	return u.userDomain.Login(ctx, phone, password)
+// audit 记录用户对自己账号的安全操作
+func (u *userAppImpl) audit(ctx context.Context, userId uint, action string, detail any) {
+	recordAudit(ctx, u.auditDomain, &auditEntry{
+		ActorType:  consts.AuditActorUser,
+		ActorId:    userId,
+		Action:     action,
+		TargetType: consts.AuditTargetUser,
+		TargetId:   userId,
+		Detail:     detail,
+	})
 }
 
-// Register 注册
-func (u *userAppImpl) Register(ctx context.Context, user *dto.User) error {
+// auditLogin 登录成功时记录，开启两步验证的用户在完成两步验证时记录
+func (u *userAppImpl) auditLogin(ctx context.Context, login *dto.UserLogin, method string) {
+	if login.User == nil {
+		return
+	}
+	u.audit(ctx, login.User.ID, consts.AuditActionUserLogin, map[string]any{"method": method})
+}
+
+// Login 密码登录，连续失败后依次要求人机验证、退避等待和临时锁定
+func (u *userAppImpl) Login(ctx context.Context, phone, password, captchaToken string) (*dto.UserLogin, error) {
+	ip, _, _ := request.GetClientInfo(ctx)
+	if err := u.loginGuard.Check(ctx, phone, ip, captchaToken); err != nil {
+		return nil, err
+	}
+	login, err := u.userDomain.Login(ctx, phone, password)
+	if err != nil {
+		return nil, err
+	}
+	if login == nil {
+		u.auditLoginFailed(ctx, phone, consts.LoginMethodPassword)
+		return nil, u.loginGuard.Fail(ctx, phone, ip)
+	}
+	// 需要两步验证时保留失败记录，第二步的失败继续累计，完成登录后才清除
+	if login.TwoFactorRequired {
+		return login, nil
+	}
+	u.auditLogin(ctx, login, consts.LoginMethodPassword)
+	return login, u.loginGuard.Success(ctx, phone, ip)
+}
+
+// auditLoginFailed 手机号已注册时记录到对应用户下，用户可以在安全事件中看到
+func (u *userAppImpl) auditLoginFailed(ctx context.Context, phone, method string) {
+	entry := &auditEntry{
+		ActorType:  consts.AuditActorUser,
+		Action:     consts.AuditActionUserLoginFailed,
+		TargetType: consts.AuditTargetPhone,
+		TargetId:   phone,
+		Detail:     map[string]any{"method": method},
+	}
+	if user, err := u.userDomain.QueryUser(ctx, &dto.QueryUserRequest{Phone: phone}); err == nil && user != nil && user.ID != 0 {
+		entry.ActorId = user.ID
+		entry.TargetType = consts.AuditTargetUser
+		entry.TargetId = user.ID
+	}
+	recordAudit(ctx, u.auditDomain, entry)
+}
+
+// Register 校验手机验证码后注册
+func (u *userAppImpl) Register(ctx context.Context, user *dto.User, code string) error {
+	if err := u.verifyDomain.CheckCode(ctx, consts.VerifySceneRegister, user.Phone, code); err != nil {
+		return err
+	}
 	return u.userDomain.Register(ctx, user)
 }
 
+// SendVerifyCode 发送验证码，注册只发给未注册的手机号，登录和重置密码只发给已注册的手机号；
+// 无论是否发送都返回相同的结果，避免通过该接口探测手机号是否注册
+func (u *userAppImpl) SendVerifyCode(ctx context.Context, scene, phone string) error {
+	user, err := u.userDomain.QueryUser(ctx, &dto.QueryUserRequest{Phone: phone})
+	if err != nil {
+		return err
+	}
+	exist := user != nil && user.ID != 0
+	return u.verifyDomain.SendCode(ctx, scene, phone, exist != (scene == consts.VerifySceneRegister))
+}
+
+// LoginByCode 验证码登录
+func (u *userAppImpl) LoginByCode(ctx context.Context, phone, code string) (*dto.UserLogin, error) {
+	if err := u.verifyDomain.CheckCode(ctx, consts.VerifySceneLogin, phone, code); err != nil {
+		return nil, err
+	}
+	login, err := u.userDomain.LoginByPhone(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+	if login == nil {
+		return nil, consts.ErrPhoneNotExist
+	}
+	u.auditLogin(ctx, login, consts.LoginMethodCode)
+	return login, nil
+}
+
+// ResetPassword 通过验证码重置密码，重置后已登录的设备需要重新登录
+func (u *userAppImpl) ResetPassword(ctx context.Context, phone, code, password string) error {
+	if err := u.verifyDomain.CheckCode(ctx, consts.VerifySceneResetPassword, phone, code); err != nil {
+		return err
+	}
+	user, err := u.userDomain.QueryUser(ctx, &dto.QueryUserRequest{Phone: phone})
+	if err != nil {
+		return err
+	}
+	if user == nil || user.ID == 0 {
+		return consts.ErrPhoneNotExist
+	}
+	if err := u.userDomain.UpdateUserPassword(ctx, user.ID, password); err != nil {
+		return err
+	}
+	if _, err := u.userDomain.RevokeSessions(ctx, user.ID, ""); err != nil {
+		return err
+	}
+	u.imDomain.CloseConnection(ctx, user.ID)
+	u.audit(ctx, user.ID, consts.AuditActionUserPasswordReset, nil)
+	return nil
+}
+
+// ListSessions 获取当前用户已登录的设备
+func (u *userAppImpl) ListSessions(ctx context.Context) ([]*dto.Session, error) {
+	sessions, err := u.userDomain.ListSessions(ctx, request.GetCurrentUser(ctx))
+	if err != nil {
+		return nil, err
+	}
+	current := request.GetCurrentSession(ctx)
+	for _, session := range sessions {
+		session.Current = session.ID == current
+	}
+	return sessions, nil
+}
+
+// RevokeSession 注销指定设备，并断开该设备的 ws 连接
+func (u *userAppImpl) RevokeSession(ctx context.Context, sessionId string) error {
+	userId := request.GetCurrentUser(ctx)
+	if err := u.userDomain.RevokeSession(ctx, userId, sessionId); err != nil {
+		return err
+	}
+	u.imDomain.CloseSessionConnection(ctx, userId, sessionId)
+	u.audit(ctx, userId, consts.AuditActionUserSessionRevoke, map[string]any{"session_ids": []string{sessionId}})
+	return nil
+}
+
+// Logout 退出当前设备
+func (u *userAppImpl) Logout(ctx context.Context) error {
+	return u.RevokeSession(ctx, request.GetCurrentSession(ctx))
+}
+
+// RevokeOtherSessions 退出除当前设备外的其他设备
+func (u *userAppImpl) RevokeOtherSessions(ctx context.Context) error {
+	userId := request.GetCurrentUser(ctx)
+	revoked, err := u.userDomain.RevokeSessions(ctx, userId, request.GetCurrentSession(ctx))
+	if err != nil {
+		return err
+	}
+	u.imDomain.CloseSessionConnection(ctx, userId, revoked...)
+	u.audit(ctx, userId, consts.AuditActionUserSessionRevoke, map[string]any{"session_ids": revoked})
+	return nil
+}
+
 func (u *userAppImpl) QueryUser(ctx context.Context, param *dto.QueryUserRequest) (*dto.UserInfo, error) {
 	user, err := u.userDomain.QueryUser(ctx, param)
 	if err != nil || user == nil || user.ID == 0 {
 		return nil, err
 	}
-	isFriend, err := u.friendDomain.IsFriend(ctx, request.GetCurrentUser(ctx), user.ID)
+	curUserId := request.GetCurrentUser(ctx)
+	// 不允许通过手机号搜索时按用户不存在处理
+	if param.Phone != "" && user.ID != curUserId && !user.Privacy.SearchByPhone {
+		return nil, nil
+	}
+	isFriend, err := u.friendDomain.IsFriend(ctx, curUserId, user.ID)
 	if err != nil {
 		return nil, err
 	}
+	maskProfile(user, curUserId, isFriend)
 	return &dto.UserInfo{
 		Id:        user.ID,
 		Nickname:  user.Nickname,
@@ -51,7 +218,21 @@ func (u *userAppImpl) QueryUser(ctx context.Context, param *dto.QueryUserRequest
 	}, nil
 }
 
+// UpdateUserInfo 修改资料，昵称和签名需通过内容审核
 func (u *userAppImpl) UpdateUserInfo(ctx context.Context, user *dto.User) (*dto.User, error) {
+	targetId := strconv.FormatUint(uint64(user.ID), 10)
+	for scene, content := range map[string]*string{
+		consts.ModerationSceneNickname:  &user.Nickname,
+		consts.ModerationSceneSignature: &user.Signature,
+	} {
+		allowed, err := moderateText(ctx, u.moderation, scene, user.ID, targetId, content)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, consts.ErrContentRejected
+		}
+	}
 	err := u.userDomain.UpdateUser(ctx, user)
 	if err != nil {
 		return nil, err
@@ -76,13 +257,120 @@ func (u *userAppImpl) UpdateUserPassword(ctx context.Context, old string, new st
 	if err != nil {
 		return false, err
 	}
+	u.audit(ctx, user.ID, consts.AuditActionUserPasswordChange, nil)
 	return true, nil
 }
 
-func (u *userAppImpl) RefreshToken(ctx context.Context, refreshToken string) (string, error) {
-	accessToken, err := middleware.TryRefreshToken(ctx, refreshToken)
+// RefreshToken 刷新双 token，检测到 refresh token 重复使用时会话被注销，同时断开该会话的 ws 连接
+func (u *userAppImpl) RefreshToken(ctx context.Context, refreshToken string) (*dto.TokenPair, error) {
+	pair, err := u.userDomain.RefreshToken(ctx, refreshToken)
+	if err != nil && !errors.Is(err, consts.ErrRefreshTokenReused) {
+		return nil, err
+	}
+	claims, parseErr := jwt.ParseToken(refreshToken, jwt.RefreshToken)
+	if parseErr != nil {
+		return pair, err
+	}
+	userId, sessionId := claims.UserClaims.ID, claims.UserClaims.SessionId
 	if err != nil {
-		return "", err
+		u.imDomain.CloseSessionConnection(ctx, userId, sessionId)
+		u.audit(ctx, userId, consts.AuditActionUserTokenReused, map[string]any{"session_id": sessionId})
+		return nil, err
+	}
+	u.audit(ctx, userId, consts.AuditActionUserTokenRefresh, map[string]any{"session_id": sessionId})
+	return pair, nil
+}
+
+// CompleteLogin 两步验证登录
+// CompleteLogin 两步验证登录第二步，失败次数与密码登录一起按手机号计入登录保护，
+// 避免拿到密码后反复申请挑战暴力破解动态码
+func (u *userAppImpl) CompleteLogin(ctx context.Context, challengeToken, code, captchaToken string) (*dto.UserLogin, error) {
+	user, err := u.userDomain.GetLoginChallengeUser(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	ip, _, _ := request.GetClientInfo(ctx)
+	if err := u.loginGuard.Check(ctx, user.Phone, ip, captchaToken); err != nil {
+		return nil, err
+	}
+	login, err := u.userDomain.CompleteLogin(ctx, challengeToken, code)
+	if errors.Is(err, consts.ErrTwoFactorCodeInvalid) {
+		u.auditLoginFailed(ctx, user.Phone, consts.LoginMethodTwoFactor)
+		if err := u.loginGuard.Fail(ctx, user.Phone, ip); err != nil {
+			return nil, err
+		}
+		return nil, consts.ErrTwoFactorCodeInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	u.auditLogin(ctx, login, consts.LoginMethodTwoFactor)
+	return login, u.loginGuard.Success(ctx, user.Phone, ip)
+}
+
+func (u *userAppImpl) GetTotpStatus(ctx context.Context) (*dto.TotpStatus, error) {
+	return u.userDomain.GetTotpStatus(ctx, request.GetCurrentUser(ctx))
+}
+
+func (u *userAppImpl) EnrollTotp(ctx context.Context) (*dto.TotpEnroll, error) {
+	return u.userDomain.EnrollTotp(ctx, request.GetCurrentUser(ctx))
+}
+
+func (u *userAppImpl) ConfirmTotp(ctx context.Context, code string) ([]string, error) {
+	userId := request.GetCurrentUser(ctx)
+	codes, err := u.userDomain.ConfirmTotp(ctx, userId, code)
+	if err != nil {
+		return nil, err
+	}
+	u.audit(ctx, userId, consts.AuditActionUserTotpEnable, nil)
+	return codes, nil
+}
+
+// DisableTotp 关闭两步验证，需同时校验密码和动态码
+func (u *userAppImpl) DisableTotp(ctx context.Context, password, code string) error {
+	user, err := u.userDomain.QueryUser(ctx, &dto.QueryUserRequest{UserId: request.GetCurrentUser(ctx)})
+	if err != nil {
+		return err
+	}
+	if !bcrypt.ComparePassword(user.Password, password) {
+		return consts.ErrPasswordError
+	}
+	if err := u.userDomain.DisableTotp(ctx, user.ID, code); err != nil {
+		return err
+	}
+	u.audit(ctx, user.ID, consts.AuditActionUserTotpDisable, nil)
+	return nil
+}
+
+func (u *userAppImpl) GetUserPrivacy(ctx context.Context) (*dto.UserPrivacy, error) {
+	user, err := u.userDomain.QueryUser(ctx, &dto.QueryUserRequest{UserId: request.GetCurrentUser(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	return user.Privacy, nil
+}
+
+func (u *userAppImpl) UpdateUserPrivacy(ctx context.Context, privacy *dto.UserPrivacy) error {
+	return u.userDomain.UpdateUserPrivacy(ctx, request.GetCurrentUser(ctx), privacy)
+}
+
+// allowScope 判断隐私范围是否允许访问
+func allowScope(scope int, isFriend bool) bool {
+	switch scope {
+	case consts.PrivacyScopeEveryone:
+		return true
+	case consts.PrivacyScopeFriends:
+		return isFriend
+	}
+	return false
+}
+
+// maskProfile 按隐私设置对 viewerId 隐藏用户的签名、年龄、性别
+func maskProfile(user *dto.User, viewerId uint, isFriend bool) {
+	if user.ID == viewerId || user.Privacy == nil || allowScope(user.Privacy.ProfileScope, isFriend) {
+		return
 	}
-	return accessToken, nil
+	user.Signature = ""
+	user.Age = 0
+	user.Gender = 0
 }
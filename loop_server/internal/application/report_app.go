@@ -0,0 +1,10 @@
+package application
+
+import (
+	"context"
+	"loop_server/internal/model/param"
+)
+
+type ReportApp interface {
+	CreateReport(ctx context.Context, p *param.CreateReportRequest) error
+}
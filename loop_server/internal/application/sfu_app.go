@@ -9,4 +9,5 @@ import (
 type SfuAPP interface {
 	SetOfferGetAnswer(ctx context.Context, groupId uint, senderNickname, senderAvatar string, receiverList []*dto.Receiver, mediaType uint, offer *webrtc.SessionDescription) (*webrtc.SessionDescription, error)
 	SetIceCandidateInit(ctx context.Context, groupId uint, senderNickname, senderAvatar string, receiverList []*dto.Receiver, mediaType uint, init *webrtc.ICECandidateInit) error
+	GetRooms(ctx context.Context) []*dto.SfuRoom
 }
@@ -6,10 +6,24 @@ import (
 )
 
 type UserApp interface {
-	Login(ctx context.Context, phone, password string) (*dto.UserLogin, error)
-	Register(ctx context.Context, user *dto.User) error
+	Login(ctx context.Context, phone, password, captchaToken string) (*dto.UserLogin, error)
+	Register(ctx context.Context, user *dto.User, code string) error
+	SendVerifyCode(ctx context.Context, scene, phone string) error
+	LoginByCode(ctx context.Context, phone, code string) (*dto.UserLogin, error)
+	ResetPassword(ctx context.Context, phone, code, password string) error
+	CompleteLogin(ctx context.Context, challengeToken, code, captchaToken string) (*dto.UserLogin, error)
+	GetTotpStatus(ctx context.Context) (*dto.TotpStatus, error)
+	EnrollTotp(ctx context.Context) (*dto.TotpEnroll, error)
+	ConfirmTotp(ctx context.Context, code string) ([]string, error)
+	DisableTotp(ctx context.Context, password, code string) error
+	ListSessions(ctx context.Context) ([]*dto.Session, error)
+	RevokeSession(ctx context.Context, sessionId string) error
+	Logout(ctx context.Context) error
+	RevokeOtherSessions(ctx context.Context) error
 	QueryUser(ctx context.Context, user *dto.QueryUserRequest) (*dto.UserInfo, error)
 	UpdateUserInfo(ctx context.Context, user *dto.User) (*dto.User, error)
 	UpdateUserPassword(ctx context.Context, old string, new string) (bool, error)
-	RefreshToken(ctx context.Context, refreshToken string) (string, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*dto.TokenPair, error)
+	GetUserPrivacy(ctx context.Context) (*dto.UserPrivacy, error)
+	UpdateUserPrivacy(ctx context.Context, privacy *dto.UserPrivacy) error
 }
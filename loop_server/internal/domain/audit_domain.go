@@ -0,0 +1,12 @@
+package domain
+
+import (
+	"context"
+	"loop_server/internal/model/dto"
+)
+
+type AuditDomain interface {
+	Record(ctx context.Context, log *dto.AuditLog)
+	Run(ctx context.Context)
+	GetAuditLogList(ctx context.Context, query *dto.AuditLogQuery, offset, limit int) (*dto.AuditLogList, error)
+}
@@ -9,8 +9,27 @@ type FriendDomain interface {
 	AddFriend(ctx context.Context, req *dto.FriendRequest) error
 	DeleteFriend(ctx context.Context, userId, friendId uint) error
 	UpdateFriendRequest(ctx context.Context, req *dto.FriendRequest) error
+	WithdrawFriendRequest(ctx context.Context, requesterId, recipientId uint) error
 	GetFriendRequestList(ctx context.Context) ([]*dto.FriendRequest, error)
 	GetFriendList(ctx context.Context) ([]*dto.User, error)
 	IsFriend(ctx context.Context, userId, fiends uint) (bool, error)
 	FriendRequestStatistics(ctx context.Context, userId uint) (*dto.FriendListStatistics, error)
+	BlockUser(ctx context.Context, userId, blockedId uint) error
+	UnblockUser(ctx context.Context, userId, blockedId uint) error
+	GetBlockList(ctx context.Context, userId uint) ([]*dto.User, error)
+	IsBlocked(ctx context.Context, userId, blockedId uint) (bool, error)
+	GetBlockerIds(ctx context.Context, userIds []uint, blockedId uint) ([]uint, error)
+	GetFriendShipList(ctx context.Context, userId uint) ([]*dto.FriendShip, error)
+	UpdateFriendRemark(ctx context.Context, userId, friendId uint, remark string) error
+	UpdateFriendTags(ctx context.Context, userId, friendId uint, tags []string) error
+	MoveFriendCategory(ctx context.Context, userId uint, friendIds []uint, categoryId uint) error
+	GetFriendCategoryList(ctx context.Context, userId uint) ([]*dto.FriendCategory, error)
+	CreateFriendCategory(ctx context.Context, userId uint, name string) (*dto.FriendCategory, error)
+	UpdateFriendCategory(ctx context.Context, category *dto.FriendCategory) error
+	DeleteFriendCategory(ctx context.Context, userId, categoryId uint) error
+	SortFriendCategory(ctx context.Context, userId uint, categoryIds []uint) error
+	GetFriendRecommend(ctx context.Context, userId uint) ([]*dto.FriendRecommend, error)
+	ClearFriendRecommend(ctx context.Context, userIds ...uint) error
+	HasMutualConnection(ctx context.Context, userId, targetId uint) (bool, error)
+	RemoveUserRelations(ctx context.Context, userId uint) error
 }
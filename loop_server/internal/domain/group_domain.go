@@ -10,6 +10,8 @@ type GroupDomain interface {
 	CreateGroup(ctx context.Context, dto *dto.CreateGroupRequest) (*dto.Group, *po.GroupMessage, error)
 	UpdateGroup(ctx context.Context, group *dto.UpdateGroupRequest) (*dto.Group, error)
 	DeleteGroup(ctx context.Context, groupId uint) error
+	OwnerExitGroup(ctx context.Context, groupId, ownerId uint) (uint, error)
+	GetArchivedGroup(ctx context.Context, groupId, userId uint) (*dto.Group, error)
 	GetGroupList(ctx context.Context, userId uint) ([]*dto.Group, error)
 	GetGroupById(ctx context.Context, groupId uint) (*dto.Group, error)
 	DeleteMember(ctx context.Context, groupId uint, userIds []uint, role uint) error
@@ -22,4 +24,18 @@ type GroupDomain interface {
 	GetGroupShip(ctx context.Context, groupId uint) ([]*dto.GroupShip, error)
 	GetGroupShipByLessRole(ctx context.Context, groupId uint, role uint) ([]*dto.GroupShip, error)
 	TransferGroupOwner(ctx context.Context, groupId uint, curOwner, userId uint) error
+	GetGroupShipByUserIds(ctx context.Context, groupId uint, userIds []uint) ([]*dto.GroupShip, error)
+	GetGroupShipListByUserId(ctx context.Context, userId uint) ([]*dto.GroupShip, error)
+	UpdateGroupNickname(ctx context.Context, groupId, userId uint, nickname string) error
+	UpdateGroupRemark(ctx context.Context, groupId, userId uint, remark string) error
+	UpgradeGroup(ctx context.Context, groupId uint, groupType int) error
+	SearchGroup(ctx context.Context, keyword, category string, offset, limit int) (*dto.GroupSearchResult, error)
+	UpdateGroupDiscovery(ctx context.Context, group *dto.Group) error
+	SaveGroupJoinRequest(ctx context.Context, req *dto.GroupJoinRequest) error
+	GetGroupJoinRequest(ctx context.Context, groupId, userId uint) (*dto.GroupJoinRequest, error)
+	GetGroupJoinRequestList(ctx context.Context, groupId uint) ([]*dto.GroupJoinRequest, error)
+	UpdateGroupJoinRequest(ctx context.Context, req *dto.GroupJoinRequest) error
+	GetGroupPermission(ctx context.Context, groupId uint) (map[string]uint, error)
+	UpdateGroupPermission(ctx context.Context, groupId uint, permission map[string]uint) error
+	CheckPermission(ctx context.Context, groupId, userId uint, action string) (*dto.GroupShip, error)
 }
@@ -8,17 +8,29 @@ import (
 
 type ImDomain interface {
 	IsOnline(ctx context.Context, userId uint) bool
+	GetOnlineUserCount(ctx context.Context) (int64, error)
 	HandleHeartbeat(ctx context.Context, curUserId uint, msgByte []byte) error
 	HandleOnlinePrivateMessage(ctx context.Context, pMsg *dto.PrivateMessage) (bool, error)
 	HandleOfflinePrivateMessage(ctx context.Context, pMsg *dto.PrivateMessage) error
 	HandleAck(ctx context.Context, ack *dto.Ack) error
 	SendAck(ctx context.Context, ack *dto.Ack) error
 	SendGroupMessage(ctx context.Context, pMsg *dto.GroupMessage, userIds []uint) error
+	SendLargeGroupMessage(ctx context.Context, pMsg *dto.GroupMessage, userIds []uint) error
+	HandleLargeGroupAck(ctx context.Context, userId uint, ack *dto.Ack) error
+	GetGroupMessageAfterSeqId(ctx context.Context, groupId, userId uint, seqId string, limit int) ([]*po.GroupMessage, error)
 	GetOfflinePrivateMessage(ctx context.Context, userId uint) ([]*dto.Message, error)
 	GetOfflineGroupMessage(ctx context.Context, groupIds []uint, userId uint) ([]*po.GroupMessage, error)
 	SendMessage(ctx context.Context, cmd int, receiverId uint, data any) error
+	PushMessage(ctx context.Context, cmd int, receiverId uint, data any) error
 	SaveGroupMessage(ctx context.Context, pMsg *po.GroupMessage) error
 	GetGroupMessageBySeqId(ctx context.Context, seqId string) (*po.GroupMessage, error)
 	DeleteOfflinePrivateMessage(ctx context.Context, userId uint, messages []*dto.Message) error
 	HandleOfflineGroupMessage(ctx context.Context, acks []*dto.Ack) error
+	GetGroupMessageBySenderId(ctx context.Context, senderId uint) ([]*po.GroupMessage, error)
+	AnonymizeGroupMessage(ctx context.Context, senderId uint) error
+	ClearOfflineMessage(ctx context.Context, userId uint) error
+	CloseConnection(ctx context.Context, userId uint)
+	CloseSessionConnection(ctx context.Context, userId uint, sessionIds ...string)
+	GetGroupMessageAround(ctx context.Context, groupId, id uint, limit int) ([]*po.GroupMessage, error)
+	DeleteGroupMessage(ctx context.Context, seqId string) error
 }
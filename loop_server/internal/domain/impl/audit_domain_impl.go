@@ -0,0 +1,105 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"loop_server/infra/consts"
+	"loop_server/internal/model/dto"
+	"loop_server/internal/repository"
+	"sync"
+	"time"
+)
+
+type auditDomainImpl struct {
+	auditRepo repository.AuditRepo
+	queue     chan *dto.AuditLog
+	mu        sync.RWMutex // 保护 stopped，保证 Run 开始排空队列后不会再有日志入队
+	stopped   bool         // Run 已退出，之后的日志改为同步写入
+}
+
+func NewAuditDomainImpl(auditRepo repository.AuditRepo) *auditDomainImpl {
+	return &auditDomainImpl{
+		auditRepo: auditRepo,
+		queue:     make(chan *dto.AuditLog, consts.AuditQueueSize),
+	}
+}
+
+// Record 将审计日志放入队列由 Run 批量写入，时间取事件发生时间；队列满或 Run 已退出时同步写入，不丢弃日志
+func (a *auditDomainImpl) Record(ctx context.Context, log *dto.AuditLog) {
+	log.CreatedAt = time.Now()
+	queued := false
+	a.mu.RLock()
+	if !a.stopped {
+		select {
+		case a.queue <- log:
+			queued = true
+		default:
+		}
+	}
+	a.mu.RUnlock()
+	if !queued {
+		a.write(context.WithoutCancel(ctx), []*dto.AuditLog{log})
+	}
+}
+
+// write 写入审计日志，失败时重试一次，仍失败则把完整内容输出到错误日志
+func (a *auditDomainImpl) write(ctx context.Context, logs []*dto.AuditLog) {
+	if err := a.auditRepo.CreateAuditLogs(ctx, logs); err == nil {
+		return
+	}
+	time.Sleep(consts.AuditRetryDelay)
+	if err := a.auditRepo.CreateAuditLogs(ctx, logs); err == nil {
+		return
+	}
+	for _, log := range logs {
+		data, _ := json.Marshal(log)
+		slog.Error("internal/domain/impl/audit_domain_impl.go write audit log dropped", "audit_log", string(data))
+	}
+}
+
+// Run 批量写入审计日志，攒满一批或到达刷新间隔时写入，ctx 结束时写入剩余日志后退出
+func (a *auditDomainImpl) Run(ctx context.Context) {
+	ticker := time.NewTicker(consts.AuditFlushInterval)
+	defer ticker.Stop()
+	batch := make([]*dto.AuditLog, 0, consts.AuditBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		a.write(context.Background(), batch)
+		batch = make([]*dto.AuditLog, 0, consts.AuditBatchSize)
+	}
+	for {
+		select {
+		case log := <-a.queue:
+			if batch = append(batch, log); len(batch) >= consts.AuditBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-ctx.Done():
+			// 持有写锁设置 stopped，之后 Record 不会再入队，排空队列即可保证不丢日志
+			a.mu.Lock()
+			a.stopped = true
+			a.mu.Unlock()
+			for {
+				select {
+				case log := <-a.queue:
+					batch = append(batch, log)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (a *auditDomainImpl) GetAuditLogList(ctx context.Context, query *dto.AuditLogQuery, offset, limit int) (*dto.AuditLogList, error) {
+	list, total, err := a.auditRepo.GetAuditLogList(ctx, query, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AuditLogList{Total: total, List: list}, nil
+}
@@ -2,9 +2,15 @@ package impl
 
 import (
 	"context"
+	"encoding/json"
+	"log/slog"
+	"loop_server/infra/consts"
+	"loop_server/infra/redis"
+	"loop_server/infra/vars"
 	"loop_server/internal/model/dto"
 	"loop_server/internal/repository"
 	"loop_server/pkg/request"
+	"time"
 )
 
 type friendDomainImpl struct {
@@ -17,9 +23,43 @@ func NewFriendDomainImpl(friendRepo repository.FriendRepo) *friendDomainImpl {
 	}
 }
 
-// AddFriend 添加好友
+// AddFriend 发送好友请求，每次请求单独记录
+// 已有待处理请求、被拒绝后处于冷却期或超过每日上限时拒绝发送
 func (u *friendDomainImpl) AddFriend(ctx context.Context, req *dto.FriendRequest) error {
-	return u.friendRepo.SaveOrUpdateFriendRequest(ctx, req)
+	if err := u.expireFriendRequest(ctx, req.RequesterId); err != nil {
+		return err
+	}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return u.friendRepo.CreateFriendRequest(ctx, req, friendRequestCooldown(), friendRequestDailyLimit(), today)
+}
+
+// expireFriendRequest 将用户相关的过期请求标记为已过期
+func (u *friendDomainImpl) expireFriendRequest(ctx context.Context, userId uint) error {
+	return u.friendRepo.ExpireFriendRequest(ctx, userId, time.Now().Add(-friendRequestExpire()))
+}
+
+func friendRequestExpire() time.Duration {
+	days := consts.FriendRequestExpireDays
+	if vars.App.FriendConfig != nil && vars.App.RequestExpireDays > 0 {
+		days = vars.App.RequestExpireDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func friendRequestCooldown() time.Duration {
+	hours := consts.FriendRequestCooldownHours
+	if vars.App.FriendConfig != nil && vars.App.RequestCooldownHours > 0 {
+		hours = vars.App.RequestCooldownHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+func friendRequestDailyLimit() int {
+	if vars.App.FriendConfig != nil && vars.App.RequestDailyLimit > 0 {
+		return vars.App.RequestDailyLimit
+	}
+	return consts.FriendRequestDailyLimit
 }
 
 func (u *friendDomainImpl) DeleteFriend(ctx context.Context, userId, friendId uint) error {
@@ -27,10 +67,28 @@ func (u *friendDomainImpl) DeleteFriend(ctx context.Context, userId, friendId ui
 }
 
 func (u *friendDomainImpl) UpdateFriendRequest(ctx context.Context, request *dto.FriendRequest) error {
+	if err := u.expireFriendRequest(ctx, request.RecipientId); err != nil {
+		return err
+	}
 	return u.friendRepo.UpdateFriendRequest(ctx, request)
 }
 
+// WithdrawFriendRequest 撤回待处理的好友请求
+func (u *friendDomainImpl) WithdrawFriendRequest(ctx context.Context, requesterId, recipientId uint) error {
+	if err := u.expireFriendRequest(ctx, requesterId); err != nil {
+		return err
+	}
+	return u.friendRepo.UpdateFriendRequest(ctx, &dto.FriendRequest{
+		RequesterId: requesterId,
+		RecipientId: recipientId,
+		Status:      consts.FriendRequestStatusWithdraw,
+	})
+}
+
 func (u *friendDomainImpl) GetFriendRequestList(ctx context.Context) ([]*dto.FriendRequest, error) {
+	if err := u.expireFriendRequest(ctx, request.GetCurrentUser(ctx)); err != nil {
+		return nil, err
+	}
 	return u.friendRepo.GetFriendRequestListByRequesterIdOrRecipientId(ctx, request.GetCurrentUser(ctx))
 }
 
@@ -43,5 +101,144 @@ func (u *friendDomainImpl) IsFriend(ctx context.Context, userId, friendId uint)
 }
 
 func (u *friendDomainImpl) FriendRequestStatistics(ctx context.Context, userId uint) (*dto.FriendListStatistics, error) {
+	if err := u.expireFriendRequest(ctx, userId); err != nil {
+		return nil, err
+	}
 	return u.friendRepo.FriendRequestStatistics(ctx, userId)
 }
+
+func (u *friendDomainImpl) BlockUser(ctx context.Context, userId, blockedId uint) error {
+	return u.friendRepo.BlockUser(ctx, userId, blockedId)
+}
+
+func (u *friendDomainImpl) UnblockUser(ctx context.Context, userId, blockedId uint) error {
+	return u.friendRepo.UnblockUser(ctx, userId, blockedId)
+}
+
+func (u *friendDomainImpl) GetBlockList(ctx context.Context, userId uint) ([]*dto.User, error) {
+	return u.friendRepo.GetBlockList(ctx, userId)
+}
+
+// IsBlocked userId 是否拉黑了 blockedId
+func (u *friendDomainImpl) IsBlocked(ctx context.Context, userId, blockedId uint) (bool, error) {
+	ids, err := u.friendRepo.GetBlockerIds(ctx, []uint{userId}, blockedId)
+	if err != nil {
+		return false, err
+	}
+	return len(ids) > 0, nil
+}
+
+func (u *friendDomainImpl) GetBlockerIds(ctx context.Context, userIds []uint, blockedId uint) ([]uint, error) {
+	return u.friendRepo.GetBlockerIds(ctx, userIds, blockedId)
+}
+
+func (u *friendDomainImpl) GetFriendShipList(ctx context.Context, userId uint) ([]*dto.FriendShip, error) {
+	return u.friendRepo.GetFriendShipList(ctx, userId)
+}
+
+func (u *friendDomainImpl) UpdateFriendRemark(ctx context.Context, userId, friendId uint, remark string) error {
+	return u.friendRepo.UpdateFriendRemark(ctx, userId, friendId, remark)
+}
+
+func (u *friendDomainImpl) UpdateFriendTags(ctx context.Context, userId, friendId uint, tags []string) error {
+	return u.friendRepo.UpdateFriendTags(ctx, userId, friendId, tags)
+}
+
+// MoveFriendCategory 将好友移动到指定分组，categoryId 为 0 时移回默认分组
+func (u *friendDomainImpl) MoveFriendCategory(ctx context.Context, userId uint, friendIds []uint, categoryId uint) error {
+	if categoryId != 0 {
+		category, err := u.friendRepo.GetFriendCategory(ctx, userId, categoryId)
+		if err != nil {
+			return err
+		}
+		if category.ID == 0 {
+			return consts.ErrFriendCategoryNotExist
+		}
+	}
+	return u.friendRepo.MoveFriendCategory(ctx, userId, friendIds, categoryId)
+}
+
+func (u *friendDomainImpl) GetFriendCategoryList(ctx context.Context, userId uint) ([]*dto.FriendCategory, error) {
+	return u.friendRepo.GetFriendCategoryList(ctx, userId)
+}
+
+func (u *friendDomainImpl) CreateFriendCategory(ctx context.Context, userId uint, name string) (*dto.FriendCategory, error) {
+	count, err := u.friendRepo.CountFriendCategory(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if count >= consts.FriendCategoryMaxCount {
+		return nil, consts.ErrFriendCategoryLimit
+	}
+	return u.friendRepo.CreateFriendCategory(ctx, &dto.FriendCategory{UserId: userId, Name: name})
+}
+
+func (u *friendDomainImpl) UpdateFriendCategory(ctx context.Context, category *dto.FriendCategory) error {
+	return u.friendRepo.UpdateFriendCategory(ctx, category)
+}
+
+func (u *friendDomainImpl) DeleteFriendCategory(ctx context.Context, userId, categoryId uint) error {
+	return u.friendRepo.DeleteFriendCategory(ctx, userId, categoryId)
+}
+
+func (u *friendDomainImpl) SortFriendCategory(ctx context.Context, userId uint, categoryIds []uint) error {
+	return u.friendRepo.SortFriendCategory(ctx, userId, categoryIds)
+}
+
+// GetFriendRecommend 获取好友推荐，结果缓存一段时间
+func (u *friendDomainImpl) GetFriendRecommend(ctx context.Context, userId uint) ([]*dto.FriendRecommend, error) {
+	key := redis.GetFriendRecommendKey(userId)
+	if cache, err := vars.Redis.Get(ctx, key).Bytes(); err == nil {
+		var data []*dto.FriendRecommend
+		if err = json.Unmarshal(cache, &data); err == nil {
+			return data, nil
+		}
+	}
+
+	data, err := u.friendRepo.GetFriendRecommend(ctx, userId, consts.FriendRecommendMaxLimit)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := json.Marshal(data)
+	if err != nil {
+		slog.Error("internal/domain/impl/firend_domain_impl.go GetFriendRecommend json.Marshal err:", "err", err)
+		return data, nil
+	}
+	if err = vars.Redis.Set(ctx, key, cache, consts.FriendRecommendCacheTTL).Err(); err != nil {
+		slog.Error("internal/domain/impl/firend_domain_impl.go GetFriendRecommend redis set err:", "err", err)
+	}
+	return data, nil
+}
+
+// ClearFriendRecommend 好友关系变化时清除推荐缓存
+func (u *friendDomainImpl) ClearFriendRecommend(ctx context.Context, userIds ...uint) error {
+	keys := make([]string, 0, len(userIds))
+	for _, id := range userIds {
+		keys = append(keys, redis.GetFriendRecommendKey(id))
+	}
+	if err := vars.Redis.Del(ctx, keys...).Err(); err != nil {
+		slog.Error("internal/domain/impl/firend_domain_impl.go ClearFriendRecommend redis del err:", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (u *friendDomainImpl) HasMutualConnection(ctx context.Context, userId, targetId uint) (bool, error) {
+	return u.friendRepo.HasMutualConnection(ctx, userId, targetId)
+}
+
+// RemoveUserRelations 清除用户的全部好友关系，并清理受影响好友的推荐缓存
+func (u *friendDomainImpl) RemoveUserRelations(ctx context.Context, userId uint) error {
+	ships, err := u.friendRepo.GetFriendShipList(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if err := u.friendRepo.RemoveUserRelations(ctx, userId); err != nil {
+		return err
+	}
+	userIds := []uint{userId}
+	for _, ship := range ships {
+		userIds = append(userIds, ship.FriendId)
+	}
+	return u.ClearFriendRecommend(ctx, userIds...)
+}
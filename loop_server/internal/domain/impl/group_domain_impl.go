@@ -2,10 +2,13 @@ package impl
 
 import (
 	"context"
+	"loop_server/infra/consts"
+	"loop_server/infra/vars"
 	"loop_server/internal/model/dto"
 	"loop_server/internal/model/po"
 	"loop_server/internal/repository"
 	"loop_server/pkg/request"
+	"strings"
 )
 
 type groupDomainImpl struct {
@@ -36,8 +39,18 @@ func (g *groupDomainImpl) UpdateGroup(ctx context.Context, group *dto.UpdateGrou
 	return g.group.UpdateGroup(ctx, gp)
 }
 
+// DeleteGroup 解散群，按配置决定归档还是清除群消息
 func (g *groupDomainImpl) DeleteGroup(ctx context.Context, groupId uint) error {
-	return g.group.DeleteGroup(ctx, groupId)
+	purge := vars.App.GroupConfig != nil && vars.App.DissolveRetention == consts.GroupRetentionPurge
+	return g.group.DeleteGroup(ctx, groupId, purge)
+}
+
+func (g *groupDomainImpl) OwnerExitGroup(ctx context.Context, groupId, ownerId uint) (uint, error) {
+	return g.group.OwnerExitGroup(ctx, groupId, ownerId)
+}
+
+func (g *groupDomainImpl) GetArchivedGroup(ctx context.Context, groupId, userId uint) (*dto.Group, error) {
+	return g.group.GetArchivedGroup(ctx, groupId, userId)
 }
 
 func (g *groupDomainImpl) GetGroupList(ctx context.Context, userId uint) ([]*dto.Group, error) {
@@ -88,3 +101,122 @@ func (g *groupDomainImpl) GetGroupShipByLessRole(ctx context.Context, groupId ui
 func (g *groupDomainImpl) TransferGroupOwner(ctx context.Context, groupId uint, curOwner, userId uint) error {
 	return g.group.TransferGroupOwner(ctx, groupId, curOwner, userId)
 }
+
+func (g *groupDomainImpl) GetGroupShipByUserIds(ctx context.Context, groupId uint, userIds []uint) ([]*dto.GroupShip, error) {
+	if len(userIds) == 0 {
+		return nil, nil
+	}
+	return g.group.GetGroupShipByUserIds(ctx, groupId, userIds)
+}
+
+func (g *groupDomainImpl) GetGroupShipListByUserId(ctx context.Context, userId uint) ([]*dto.GroupShip, error) {
+	return g.group.GetGroupShipListByUserId(ctx, userId)
+}
+
+func (g *groupDomainImpl) UpdateGroupNickname(ctx context.Context, groupId, userId uint, nickname string) error {
+	return g.group.UpdateGroupNickname(ctx, groupId, userId, nickname)
+}
+
+func (g *groupDomainImpl) UpdateGroupRemark(ctx context.Context, groupId, userId uint, remark string) error {
+	return g.group.UpdateGroupRemark(ctx, groupId, userId, remark)
+}
+
+// UpgradeGroup 升级群类型，只允许升级到成员上限更高的类型
+func (g *groupDomainImpl) UpgradeGroup(ctx context.Context, groupId uint, groupType int) error {
+	group, err := g.group.GetGroup(ctx, groupId)
+	if err != nil {
+		return err
+	}
+	maxMember, ok := consts.GroupTypeMaxMember[groupType]
+	if !ok || maxMember <= group.MaxMember {
+		return consts.ErrInvalidGroupType
+	}
+	return g.group.UpdateGroupType(ctx, groupId, groupType)
+}
+
+func (g *groupDomainImpl) SearchGroup(ctx context.Context, keyword, category string, offset, limit int) (*dto.GroupSearchResult, error) {
+	return g.group.SearchGroup(ctx, &dto.GroupSearchRequest{
+		Keyword:  strings.TrimSpace(keyword),
+		Category: category,
+		Offset:   offset,
+		Limit:    limit,
+	})
+}
+
+func (g *groupDomainImpl) UpdateGroupDiscovery(ctx context.Context, group *dto.Group) error {
+	return g.group.UpdateGroupDiscovery(ctx, group)
+}
+
+func (g *groupDomainImpl) SaveGroupJoinRequest(ctx context.Context, req *dto.GroupJoinRequest) error {
+	return g.group.SaveGroupJoinRequest(ctx, req)
+}
+
+func (g *groupDomainImpl) GetGroupJoinRequest(ctx context.Context, groupId, userId uint) (*dto.GroupJoinRequest, error) {
+	return g.group.GetGroupJoinRequest(ctx, groupId, userId)
+}
+
+func (g *groupDomainImpl) GetGroupJoinRequestList(ctx context.Context, groupId uint) ([]*dto.GroupJoinRequest, error) {
+	return g.group.GetGroupJoinRequestList(ctx, groupId)
+}
+
+func (g *groupDomainImpl) UpdateGroupJoinRequest(ctx context.Context, req *dto.GroupJoinRequest) error {
+	return g.group.UpdateGroupJoinRequest(ctx, req)
+}
+
+// GetGroupPermission 获取群权限配置，未配置的操作使用默认值
+func (g *groupDomainImpl) GetGroupPermission(ctx context.Context, groupId uint) (map[string]uint, error) {
+	list, err := g.group.GetGroupPermission(ctx, groupId)
+	if err != nil {
+		return nil, err
+	}
+	permission := make(map[string]uint, len(consts.GroupPermissionDefault))
+	for action, role := range consts.GroupPermissionDefault {
+		permission[action] = role
+	}
+	for _, p := range list {
+		if _, ok := permission[p.Action]; ok {
+			permission[p.Action] = p.MinRole
+		}
+	}
+	return permission, nil
+}
+
+func (g *groupDomainImpl) UpdateGroupPermission(ctx context.Context, groupId uint, permission map[string]uint) error {
+	list := make([]*dto.GroupPermission, 0, len(permission))
+	for action, role := range permission {
+		if _, ok := consts.GroupPermissionDefault[action]; !ok {
+			return consts.ErrInvalidPermission
+		}
+		if role < consts.GroupRoleMember || role > consts.GroupRoleOwner {
+			return consts.ErrInvalidPermission
+		}
+		list = append(list, &dto.GroupPermission{GroupId: groupId, Action: action, MinRole: role})
+	}
+	return g.group.SaveGroupPermission(ctx, list)
+}
+
+// CheckPermission 校验用户在群内是否有执行 action 的权限，返回用户的群关系
+func (g *groupDomainImpl) CheckPermission(ctx context.Context, groupId, userId uint, action string) (*dto.GroupShip, error) {
+	ship, err := g.group.GetGroupShipByUserId(ctx, groupId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if ship.ID == 0 {
+		return nil, consts.ErrNoPermission
+	}
+
+	minRole, ok := consts.GroupPermissionFixed[action]
+	if !ok {
+		permission, err := g.GetGroupPermission(ctx, groupId)
+		if err != nil {
+			return nil, err
+		}
+		if minRole, ok = permission[action]; !ok {
+			return nil, consts.ErrNoPermission
+		}
+	}
+	if ship.Role < minRole {
+		return nil, consts.ErrNoPermission
+	}
+	return ship, nil
+}
@@ -29,6 +29,16 @@ func (i *imDomainImpl) IsOnline(ctx context.Context, userId uint) bool {
 	return is
 }
 
+// GetOnlineUserCount 当前建立 ws 连接的用户数
+func (i *imDomainImpl) GetOnlineUserCount(ctx context.Context) (int64, error) {
+	count, err := vars.Redis.SCard(ctx, redis.GetOnlineUserKey()).Result()
+	if err != nil {
+		slog.Error("internal/domain/impl/im_domain_impl.go GetOnlineUserCount err:", "err", err)
+		return 0, err
+	}
+	return count, nil
+}
+
 func (i *imDomainImpl) HandleHeartbeat(ctx context.Context, curUserId uint, msgByte []byte) error {
 	if err := vars.Ws.Get(curUserId).Conn.WriteMessage(websocket.TextMessage, msgByte); err != nil {
 		slog.Error("internal/domain/impl/im_domain_impl.go HandleHeartbeat write message err:", err)
@@ -187,6 +197,41 @@ func (i *imDomainImpl) SendGroupMessage(ctx context.Context, pMsg *dto.GroupMess
 	return nil
 }
 
+// SendLargeGroupMessage 大群消息只推送给在线成员，不做逐人ack跟踪，成员通过拉取同步补齐消息
+func (i *imDomainImpl) SendLargeGroupMessage(ctx context.Context, pMsg *dto.GroupMessage, userIds []uint) error {
+	pMsgByte, err := json.Marshal(pMsg)
+	if err != nil {
+		slog.Error("internal/domain/impl/im_domain_impl.go json.Marshal(pMsg) err:", "err", err)
+		return err
+	}
+	msgByte, err := json.Marshal(&dto.Message{
+		Cmd:  consts.WsMessageCmdGroupMessage,
+		Data: pMsgByte,
+	})
+	if err != nil {
+		slog.Error("internal/domain/impl/im_domain_impl.go json.Marshal(msg) err:", "err", err)
+		return err
+	}
+	for _, userId := range userIds {
+		if userId == pMsg.SenderId || vars.Ws.Get(userId) == nil {
+			continue
+		}
+		if err := vars.Ws.SendMessage(userId, msgByte); err != nil {
+			slog.Error("internal/domain/impl/im_domain_impl.go SendLargeGroupMessage err:", "err", err)
+		}
+	}
+	return nil
+}
+
+// HandleLargeGroupAck 大群ack直接推进 userId 的同步游标，userId 为连接认证的用户
+func (i *imDomainImpl) HandleLargeGroupAck(ctx context.Context, userId uint, ack *dto.Ack) error {
+	return i.imRepo.AdvanceGroupMessageLastSeqId(ctx, ack.ReceiverId, userId, ack.SeqId)
+}
+
+func (i *imDomainImpl) GetGroupMessageAfterSeqId(ctx context.Context, groupId, userId uint, seqId string, limit int) ([]*po.GroupMessage, error) {
+	return i.imRepo.GetGroupMessageAfterSeqId(ctx, groupId, userId, seqId, limit)
+}
+
 func (i *imDomainImpl) sendGroupMessage(ctx context.Context, userId uint, receiverId uint, seqId string, msgByte []byte, replay int) (err error) {
 	if replay < 0 {
 		return nil
@@ -258,7 +303,7 @@ func (i *imDomainImpl) saveOfflineGroupMessage(ctx context.Context, userIdList [
 	}
 	for _, id := range userIdList {
 		vars.Redis.ZAdd(ctx, redis.GetUserChatKey(id), &redis2.Z{
-			Score:  float64(time.Now().Unix()),
+			Score:  float64(time.Now().UnixMilli()),
 			Member: messageByte,
 		})
 	}
@@ -306,6 +351,32 @@ func (i *imDomainImpl) SendMessage(ctx context.Context, cmd int, receiverId uint
 	return vars.Ws.SendMessage(receiverId, msgByte)
 }
 
+// PushMessage 推送消息，用户不在线或推送失败时存为离线消息
+func (i *imDomainImpl) PushMessage(ctx context.Context, cmd int, receiverId uint, data any) error {
+	dataByte, err := json.Marshal(data)
+	if err != nil {
+		slog.Error("internal/domain/impl/im_domain_impl.go PushMessage json.Marshal(data) err:", "err", err)
+		return err
+	}
+	msgByte, err := json.Marshal(dto.Message{Cmd: cmd, Data: dataByte})
+	if err != nil {
+		slog.Error("internal/domain/impl/im_domain_impl.go PushMessage json.Marshal(msg) err:", "err", err)
+		return err
+	}
+	if i.IsOnline(ctx, receiverId) && vars.Ws.SendMessage(receiverId, msgByte) == nil {
+		return nil
+	}
+	err = vars.Redis.ZAdd(ctx, redis.GetUserChatKey(receiverId), &redis2.Z{
+		Score:  float64(time.Now().UnixMilli()),
+		Member: msgByte,
+	}).Err()
+	if err != nil {
+		slog.Error("internal/domain/impl/im_domain_impl.go PushMessage redis zadd err:", "err", err)
+		return err
+	}
+	return nil
+}
+
 func (i *imDomainImpl) DeleteOfflinePrivateMessage(ctx context.Context, userId uint, messages []*dto.Message) error {
 	if len(messages) == 0 {
 		return nil
@@ -341,3 +412,49 @@ func (i *imDomainImpl) HandleOfflineGroupMessage(ctx context.Context, acks []*dt
 	}
 	return nil
 }
+
+func (i *imDomainImpl) GetGroupMessageBySenderId(ctx context.Context, senderId uint) ([]*po.GroupMessage, error) {
+	return i.imRepo.GetGroupMessageBySenderId(ctx, senderId)
+}
+
+func (i *imDomainImpl) AnonymizeGroupMessage(ctx context.Context, senderId uint) error {
+	return i.imRepo.AnonymizeGroupMessage(ctx, senderId)
+}
+
+// ClearOfflineMessage 清除用户的离线消息
+func (i *imDomainImpl) ClearOfflineMessage(ctx context.Context, userId uint) error {
+	if err := vars.Redis.Del(ctx, redis.GetUserChatKey(userId)).Err(); err != nil {
+		slog.Error("internal/domain/impl/im_domain_impl.go ClearOfflineMessage redis del err:", "err", err)
+		return err
+	}
+	return nil
+}
+
+// CloseConnection 关闭用户的 ws 连接，连接的读循环退出后会移除在线状态
+func (i *imDomainImpl) CloseConnection(ctx context.Context, userId uint) {
+	if client := vars.Ws.Get(userId); client != nil {
+		client.Conn.Close()
+	}
+}
+
+// CloseSessionConnection 关闭由指定会话建立的 ws 连接
+func (i *imDomainImpl) CloseSessionConnection(ctx context.Context, userId uint, sessionIds ...string) {
+	client := vars.Ws.Get(userId)
+	if client == nil {
+		return
+	}
+	for _, sessionId := range sessionIds {
+		if client.SessionId == sessionId {
+			client.Conn.Close()
+			return
+		}
+	}
+}
+
+func (i *imDomainImpl) GetGroupMessageAround(ctx context.Context, groupId, id uint, limit int) ([]*po.GroupMessage, error) {
+	return i.imRepo.GetGroupMessageAround(ctx, groupId, id, limit)
+}
+
+func (i *imDomainImpl) DeleteGroupMessage(ctx context.Context, seqId string) error {
+	return i.imRepo.DeleteGroupMessage(ctx, seqId)
+}
@@ -0,0 +1,171 @@
+package impl
+
+import (
+	"context"
+	"log/slog"
+	"loop_server/infra/captcha"
+	"loop_server/infra/consts"
+	"loop_server/infra/redis"
+	"loop_server/infra/vars"
+	"time"
+)
+
+const (
+	loginSubjectPhone = "phone"
+	loginSubjectIp    = "ip"
+)
+
+type loginGuardDomainImpl struct {
+	verifier captcha.Verifier
+}
+
+func NewLoginGuardDomainImpl(verifier captcha.Verifier) *loginGuardDomainImpl {
+	return &loginGuardDomainImpl{verifier: verifier}
+}
+
+// Check 登录前检查锁定、退避和人机验证
+func (l *loginGuardDomainImpl) Check(ctx context.Context, phone, ip, captchaToken string) error {
+	locked, err := vars.Redis.Exists(ctx, redis.GetLoginLockKey(phone)).Result()
+	if err != nil {
+		slog.Error("internal/domain/impl/login_guard_domain_impl.go Check redis exists err:", "err", err)
+		return err
+	}
+	if locked > 0 {
+		return consts.ErrAccountLocked
+	}
+
+	backoff, err := vars.Redis.Exists(ctx, redis.GetLoginBackoffKey(loginSubjectPhone, phone), redis.GetLoginBackoffKey(loginSubjectIp, ip)).Result()
+	if err != nil {
+		slog.Error("internal/domain/impl/login_guard_domain_impl.go Check redis exists err:", "err", err)
+		return err
+	}
+	if backoff > 0 {
+		return consts.ErrLoginTooFrequent
+	}
+
+	phoneFailures, _ := vars.Redis.Get(ctx, redis.GetLoginFailKey(loginSubjectPhone, phone)).Int()
+	ipFailures, _ := vars.Redis.Get(ctx, redis.GetLoginFailKey(loginSubjectIp, ip)).Int()
+	if ipFailures >= l.ipMaxFailures() {
+		return consts.ErrLoginTooFrequent
+	}
+	// 未配置人机验证时只靠退避和锁定限制
+	if l.verifier == nil || phoneFailures < l.captchaThreshold() {
+		return nil
+	}
+	if captchaToken == "" {
+		return consts.ErrCaptchaRequired
+	}
+	ok, err := l.verifier.Verify(ctx, captchaToken, ip)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return consts.ErrCaptchaInvalid
+	}
+	return nil
+}
+
+// Fail 记录一次登录失败，按失败次数指数退避，达到阈值后锁定手机号
+func (l *loginGuardDomainImpl) Fail(ctx context.Context, phone, ip string) error {
+	phoneFailures, err := l.incrFailure(ctx, loginSubjectPhone, phone)
+	if err != nil {
+		return err
+	}
+	if _, err := l.incrFailure(ctx, loginSubjectIp, ip); err != nil {
+		return err
+	}
+	// 同一 IP 可能是共享出口，只做固定的短退避，总量由 ipMaxFailures 限制
+	if err := l.setBackoff(ctx, loginSubjectIp, ip, consts.LoginBackoffBase); err != nil {
+		return err
+	}
+
+	if phoneFailures >= int64(l.lockThreshold()) {
+		pipe := vars.Redis.TxPipeline()
+		pipe.Set(ctx, redis.GetLoginLockKey(phone), 1, time.Duration(l.lockMinutes())*time.Minute)
+		pipe.Del(ctx, redis.GetLoginFailKey(loginSubjectPhone, phone), redis.GetLoginBackoffKey(loginSubjectPhone, phone))
+		if _, err := pipe.Exec(ctx); err != nil {
+			slog.Error("internal/domain/impl/login_guard_domain_impl.go Fail redis pipe exec err:", "err", err)
+			return err
+		}
+		slog.Warn("login locked after repeated failures", "phone", phone, "ip", ip)
+		return nil
+	}
+
+	backoff := consts.LoginBackoffBase << (phoneFailures - 1)
+	if backoff <= 0 || backoff > consts.LoginBackoffMax {
+		backoff = consts.LoginBackoffMax
+	}
+	return l.setBackoff(ctx, loginSubjectPhone, phone, backoff)
+}
+
+// setBackoff 退避期间拒绝该手机号或 IP 的登录
+func (l *loginGuardDomainImpl) setBackoff(ctx context.Context, subject, value string, backoff time.Duration) error {
+	if err := vars.Redis.Set(ctx, redis.GetLoginBackoffKey(subject, value), 1, backoff).Err(); err != nil {
+		slog.Error("internal/domain/impl/login_guard_domain_impl.go setBackoff redis set err:", "err", err)
+		return err
+	}
+	return nil
+}
+
+// Success 登录成功后清除该手机号的失败记录，IP 的失败计数保留到窗口结束
+func (l *loginGuardDomainImpl) Success(ctx context.Context, phone, ip string) error {
+	err := vars.Redis.Del(ctx, redis.GetLoginFailKey(loginSubjectPhone, phone), redis.GetLoginBackoffKey(loginSubjectPhone, phone)).Err()
+	if err != nil {
+		slog.Error("internal/domain/impl/login_guard_domain_impl.go Success redis del err:", "err", err)
+	}
+	return err
+}
+
+// Unlock 管理员解除手机号的锁定
+func (l *loginGuardDomainImpl) Unlock(ctx context.Context, phone string) error {
+	err := vars.Redis.Del(ctx,
+		redis.GetLoginLockKey(phone),
+		redis.GetLoginFailKey(loginSubjectPhone, phone),
+		redis.GetLoginBackoffKey(loginSubjectPhone, phone),
+	).Err()
+	if err != nil {
+		slog.Error("internal/domain/impl/login_guard_domain_impl.go Unlock redis del err:", "err", err)
+	}
+	return err
+}
+
+func (l *loginGuardDomainImpl) incrFailure(ctx context.Context, subject, value string) (int64, error) {
+	key := redis.GetLoginFailKey(subject, value)
+	count, err := vars.Redis.Incr(ctx, key).Result()
+	if err != nil {
+		slog.Error("internal/domain/impl/login_guard_domain_impl.go incrFailure redis incr err:", "err", err)
+		return 0, err
+	}
+	if count == 1 {
+		vars.Redis.Expire(ctx, key, consts.LoginFailureWindow)
+	}
+	return count, nil
+}
+
+func (l *loginGuardDomainImpl) captchaThreshold() int {
+	if vars.App.LoginGuardConfig != nil && vars.App.CaptchaThreshold > 0 {
+		return vars.App.CaptchaThreshold
+	}
+	return consts.LoginCaptchaThreshold
+}
+
+func (l *loginGuardDomainImpl) lockThreshold() int {
+	if vars.App.LoginGuardConfig != nil && vars.App.LockThreshold > 0 {
+		return vars.App.LockThreshold
+	}
+	return consts.LoginLockThreshold
+}
+
+func (l *loginGuardDomainImpl) lockMinutes() int {
+	if vars.App.LoginGuardConfig != nil && vars.App.LockMinutes > 0 {
+		return vars.App.LockMinutes
+	}
+	return consts.LoginLockMinutes
+}
+
+func (l *loginGuardDomainImpl) ipMaxFailures() int {
+	if vars.App.LoginGuardConfig != nil && vars.App.IpMaxFailures > 0 {
+		return vars.App.IpMaxFailures
+	}
+	return consts.LoginIpMaxFailures
+}
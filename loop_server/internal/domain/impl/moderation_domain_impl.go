@@ -0,0 +1,146 @@
+package impl
+
+import (
+	"context"
+	"log/slog"
+	"loop_server/infra/consts"
+	"loop_server/infra/moderation"
+	"loop_server/internal/model/dto"
+	"loop_server/internal/repository"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+type moderationDomainImpl struct {
+	moderationRepo repository.ModerationRepo
+	filter         *moderation.Filter
+	classifier     moderation.Classifier
+	queue          chan *dto.ModerationItem // 待分类器复查的内容
+}
+
+func NewModerationDomainImpl(moderationRepo repository.ModerationRepo, filter *moderation.Filter, classifier moderation.Classifier) *moderationDomainImpl {
+	return &moderationDomainImpl{
+		moderationRepo: moderationRepo,
+		filter:         filter,
+		classifier:     classifier,
+		queue:          make(chan *dto.ModerationItem, consts.ModerationClassifyQueueSize),
+	}
+}
+
+// Check 同步执行敏感词检查，命中 flag 规则时写入审核队列，未命中时交给分类器异步复查
+func (m *moderationDomainImpl) Check(ctx context.Context, item *dto.ModerationItem) (*dto.ModerationResult, error) {
+	res := m.filter.Check(item.Content)
+	result := &dto.ModerationResult{Action: res.Action, Content: res.Text, Words: res.Words}
+	switch res.Action {
+	case consts.ModerationActionReject:
+		slog.Info("moderation reject content", "scene", item.Scene, "user_id", item.UserId, "words", res.Words)
+	case consts.ModerationActionFlag:
+		if err := m.createReview(ctx, item, consts.ModerationSourceWord, strings.Join(res.Words, ",")); err != nil {
+			return nil, err
+		}
+	case consts.ModerationActionPass:
+		m.classify(item)
+	}
+	return result, nil
+}
+
+// classify 放入队列由 Run 调用分类器异步复查，不阻塞消息发送；队列满时丢弃
+func (m *moderationDomainImpl) classify(item *dto.ModerationItem) {
+	if m.classifier == nil || utf8.RuneCountInString(item.Content) < consts.ModerationClassifyMinLength {
+		return
+	}
+	select {
+	case m.queue <- item:
+	default:
+		slog.Warn("moderation classify queue full, skip", "scene", item.Scene, "user_id", item.UserId, "target_id", item.TargetId)
+	}
+}
+
+// Run 启动固定数量的分类 worker，ctx 结束时退出，未处理的内容不再复查
+func (m *moderationDomainImpl) Run(ctx context.Context) {
+	if m.classifier == nil {
+		return
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < consts.ModerationClassifyWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case item := <-m.queue:
+					m.classifyItem(item)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (m *moderationDomainImpl) classifyItem(item *dto.ModerationItem) {
+	ctx, cancel := context.WithTimeout(context.Background(), consts.ModerationClassifyTimeout)
+	defer cancel()
+	flagged, label, err := m.classifier.Classify(ctx, item.Content)
+	if err != nil {
+		slog.Error("internal/domain/impl/moderation_domain_impl.go classify err:", "err", err)
+		return
+	}
+	if flagged {
+		m.createReview(ctx, item, consts.ModerationSourceClassifier, label)
+	}
+}
+
+func (m *moderationDomainImpl) createReview(ctx context.Context, item *dto.ModerationItem, source, reason string) error {
+	if utf8.RuneCountInString(reason) > 255 {
+		reason = string([]rune(reason)[:255])
+	}
+	return m.moderationRepo.CreateReview(ctx, &dto.ModerationReview{
+		Scene:    item.Scene,
+		UserId:   item.UserId,
+		TargetId: item.TargetId,
+		Content:  item.Content,
+		Source:   source,
+		Reason:   reason,
+		Status:   consts.ModerationReviewPending,
+	})
+}
+
+func (m *moderationDomainImpl) GetReviewList(ctx context.Context, status, offset, limit int) (*dto.ModerationReviewList, error) {
+	list, total, err := m.moderationRepo.GetReviewList(ctx, status, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ModerationReviewList{Total: total, List: list}, nil
+}
+
+// GetPendingReview 获取待审核记录，记录不存在或已处理时返回 ErrModerationReviewNotExist
+func (m *moderationDomainImpl) GetPendingReview(ctx context.Context, id uint) (*dto.ModerationReview, error) {
+	review, err := m.moderationRepo.GetReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil || review.Status != consts.ModerationReviewPending {
+		return nil, consts.ErrModerationReviewNotExist
+	}
+	return review, nil
+}
+
+// ResolveReview 处理待审核记录，返回处理后的记录；调用方应先处置内容再调用
+func (m *moderationDomainImpl) ResolveReview(ctx context.Context, id uint, status int) (*dto.ModerationReview, error) {
+	review, err := m.GetPendingReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := m.moderationRepo.ResolveReview(ctx, id, status)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, consts.ErrModerationReviewNotExist
+	}
+	review.Status = status
+	return review, nil
+}
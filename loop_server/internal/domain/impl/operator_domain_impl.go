@@ -0,0 +1,151 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"loop_server/infra/consts"
+	"loop_server/infra/redis"
+	"loop_server/infra/vars"
+	"loop_server/internal/model/dto"
+	"loop_server/internal/repository"
+	"loop_server/pkg/bcrypt"
+	"sync"
+	"time"
+)
+
+// operatorDummyHash 登录名不存在时用于比对的密码哈希，与真实哈希使用相同的 cost
+var operatorDummyHash = sync.OnceValue(func() string {
+	hash, _ := bcrypt.GenerateFromPassword("loop-operator-dummy-password")
+	return hash
+})
+
+type operatorDomainImpl struct {
+	operatorRepo repository.OperatorRepo
+}
+
+func NewOperatorDomainImpl(operatorRepo repository.OperatorRepo) *operatorDomainImpl {
+	return &operatorDomainImpl{operatorRepo: operatorRepo}
+}
+
+// Login 操作员登录，会话保存在 redis，角色随会话保存，修改角色后需重新登录
+func (o *operatorDomainImpl) Login(ctx context.Context, username, password string) (*dto.OperatorLogin, error) {
+	operator, err := o.operatorRepo.GetOperatorByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	// 登录名不存在时也校验一次密码，避免通过响应时间判断登录名是否存在
+	hash := operator.Password
+	if operator.ID == 0 {
+		hash = operatorDummyHash()
+	}
+	matched := bcrypt.ComparePassword(hash, password)
+	if operator.ID == 0 || operator.Disabled || !matched {
+		return nil, consts.ErrOperatorLoginFailed
+	}
+
+	token, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	sessionKey, sessionsKey := redis.GetOperatorSessionKey(token), redis.GetOperatorSessionsKey(operator.ID)
+	pipe := vars.Redis.TxPipeline()
+	pipe.HSet(ctx, sessionKey, "operator_id", operator.ID, "role", operator.Role)
+	pipe.Expire(ctx, sessionKey, consts.OperatorSessionExpiration)
+	pipe.SAdd(ctx, sessionsKey, sessionKey)
+	pipe.Expire(ctx, sessionsKey, consts.OperatorSessionExpiration)
+	if _, err = pipe.Exec(ctx); err != nil {
+		slog.Error("internal/domain/impl/operator_domain_impl.go Login redis err:", "err", err)
+		return nil, err
+	}
+	if err = o.operatorRepo.UpdateLastLoginAt(ctx, operator.ID); err != nil {
+		return nil, err
+	}
+	return &dto.OperatorLogin{
+		Token:     token,
+		ExpiresAt: time.Now().Add(consts.OperatorSessionExpiration).Unix(),
+		Operator:  operator,
+	}, nil
+}
+
+func (o *operatorDomainImpl) Logout(ctx context.Context, operatorId uint, token string) error {
+	sessionKey := redis.GetOperatorSessionKey(token)
+	pipe := vars.Redis.TxPipeline()
+	pipe.Del(ctx, sessionKey)
+	pipe.SRem(ctx, redis.GetOperatorSessionsKey(operatorId), sessionKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Error("internal/domain/impl/operator_domain_impl.go Logout redis err:", "err", err)
+		return err
+	}
+	return nil
+}
+
+// revokeSessions 注销操作员的全部会话，角色、状态或密码变更后立即生效
+func (o *operatorDomainImpl) revokeSessions(ctx context.Context, operatorId uint) error {
+	sessionsKey := redis.GetOperatorSessionsKey(operatorId)
+	sessionKeys, err := vars.Redis.SMembers(ctx, sessionsKey).Result()
+	if err != nil {
+		slog.Error("internal/domain/impl/operator_domain_impl.go revokeSessions redis smembers err:", "err", err)
+		return err
+	}
+	if err = vars.Redis.Del(ctx, append(sessionKeys, sessionsKey)...).Err(); err != nil {
+		slog.Error("internal/domain/impl/operator_domain_impl.go revokeSessions redis del err:", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (o *operatorDomainImpl) CreateOperator(ctx context.Context, operator *dto.Operator) error {
+	exist, err := o.operatorRepo.GetOperatorByUsername(ctx, operator.Username)
+	if err != nil {
+		return err
+	}
+	if exist.ID != 0 {
+		return consts.ErrOperatorExist
+	}
+	if operator.Password, err = bcrypt.GenerateFromPassword(operator.Password); err != nil {
+		return err
+	}
+	return o.operatorRepo.CreateOperator(ctx, operator)
+}
+
+// UpdateOperator 修改操作员资料，密码为空时不修改
+func (o *operatorDomainImpl) UpdateOperator(ctx context.Context, operator *dto.Operator) error {
+	exist, err := o.operatorRepo.GetOperatorById(ctx, operator.ID)
+	if err != nil {
+		return err
+	}
+	if exist.ID == 0 {
+		return consts.ErrOperatorNotExist
+	}
+	if operator.Password != "" {
+		if operator.Password, err = bcrypt.GenerateFromPassword(operator.Password); err != nil {
+			return err
+		}
+	}
+	if err = o.operatorRepo.UpdateOperator(ctx, operator); err != nil {
+		return err
+	}
+	if operator.Role != exist.Role || operator.Disabled || operator.Password != "" {
+		return o.revokeSessions(ctx, operator.ID)
+	}
+	return nil
+}
+
+func (o *operatorDomainImpl) GetOperatorList(ctx context.Context) ([]*dto.Operator, error) {
+	return o.operatorRepo.GetOperatorList(ctx)
+}
+
+// InitSuperadmin 配置的超级管理员不存在时创建，已存在时不修改
+func (o *operatorDomainImpl) InitSuperadmin(ctx context.Context, username, password string) error {
+	err := o.CreateOperator(ctx, &dto.Operator{
+		Username: username,
+		Password: password,
+		Nickname: username,
+		Role:     consts.OperatorRoleSuperadmin,
+	})
+	if errors.Is(err, consts.ErrOperatorExist) {
+		return nil
+	}
+	return err
+}
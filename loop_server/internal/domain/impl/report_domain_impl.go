@@ -0,0 +1,69 @@
+package impl
+
+import (
+	"context"
+	"loop_server/infra/consts"
+	"loop_server/internal/model/dto"
+	"loop_server/internal/repository"
+)
+
+type reportDomainImpl struct {
+	reportRepo repository.ReportRepo
+}
+
+func NewReportDomainImpl(reportRepo repository.ReportRepo) *reportDomainImpl {
+	return &reportDomainImpl{reportRepo: reportRepo}
+}
+
+// CreateReport 同一用户对同一对象只保留一条待处理的举报
+func (r *reportDomainImpl) CreateReport(ctx context.Context, report *dto.Report) error {
+	pending, err := r.reportRepo.GetPendingReport(ctx, report.ReporterId, report.TargetType, report.TargetId)
+	if err != nil {
+		return err
+	}
+	if pending.ID != 0 {
+		return consts.ErrReportPending
+	}
+	report.Status = consts.ReportStatusPending
+	return r.reportRepo.CreateReport(ctx, report)
+}
+
+func (r *reportDomainImpl) GetReport(ctx context.Context, id uint) (*dto.Report, error) {
+	report, err := r.reportRepo.GetReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if report.ID == 0 {
+		return nil, consts.ErrReportNotExist
+	}
+	return report, nil
+}
+
+func (r *reportDomainImpl) GetReportList(ctx context.Context, status int, targetType string, offset, limit int) (*dto.ReportList, error) {
+	list, total, err := r.reportRepo.GetReportList(ctx, status, targetType, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ReportList{Total: total, List: list}, nil
+}
+
+// GetReportInfo 举报详情及处理记录
+func (r *reportDomainImpl) GetReportInfo(ctx context.Context, id uint) (*dto.ReportInfo, error) {
+	report, err := r.GetReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	actions, err := r.reportRepo.GetReportActions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ReportInfo{Report: report, Actions: actions}, nil
+}
+
+// HandleReport 记录处理动作并更新举报状态，处理记录只追加不修改
+func (r *reportDomainImpl) HandleReport(ctx context.Context, action *dto.ReportAction, status int) error {
+	if err := r.reportRepo.CreateReportAction(ctx, action); err != nil {
+		return err
+	}
+	return r.reportRepo.UpdateReportStatus(ctx, action.ReportId, status)
+}
@@ -2,14 +2,29 @@ package impl
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	redis2 "github.com/go-redis/redis/v8"
 	"log/slog"
 
+	"loop_server/infra/consts"
 	"loop_server/infra/redis"
 	"loop_server/infra/vars"
 	"loop_server/internal/model/dto"
 	"loop_server/internal/repository"
 	"loop_server/pkg/bcrypt"
 	"loop_server/pkg/jwt"
+	"loop_server/pkg/request"
+	"loop_server/pkg/totp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type userDomainImpl struct {
@@ -26,20 +41,85 @@ func (u *userDomainImpl) Login(ctx context.Context, phone, password string) (*dt
 	if err != nil || user.ID == 0 || !bcrypt.ComparePassword(user.Password, password) {
 		return nil, err
 	}
+	return u.login(ctx, user)
+}
+
+// LoginByPhone 验证码登录，调用方需先完成验证码校验，手机号未注册时返回 nil
+func (u *userDomainImpl) LoginByPhone(ctx context.Context, phone string) (*dto.UserLogin, error) {
+	user, err := u.userRepo.QueryByPhone(ctx, phone)
+	if err != nil || user.ID == 0 {
+		return nil, err
+	}
+	return u.login(ctx, user)
+}
+
+// login 第一步校验通过后登录，开启两步验证的用户返回挑战 token
+func (u *userDomainImpl) login(ctx context.Context, user *dto.User) (*dto.UserLogin, error) {
+	if !user.TotpEnabled {
+		return u.issueTokens(ctx, user)
+	}
+	token, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	key := redis.GetLoginChallengeKey(token)
+	pipe := vars.Redis.TxPipeline()
+	pipe.HSet(ctx, key, "user_id", user.ID, "attempts", 0)
+	pipe.Expire(ctx, key, consts.LoginChallengeTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Error("internal/domain/impl/user_domain_impl.go login redis pipe exec err:", "err", err)
+		return nil, err
+	}
+	return &dto.UserLogin{TwoFactorRequired: true, ChallengeToken: token}, nil
+}
+
+// issueTokens 为已通过校验的用户签发 token
+func (u *userDomainImpl) issueTokens(ctx context.Context, user *dto.User) (*dto.UserLogin, error) {
+	if user.BannedUntil != nil && time.Now().Before(*user.BannedUntil) {
+		return nil, consts.ErrAccountBanned
+	}
+
+	// 冷静期内重新登录，取消注销
+	if user.DeleteScheduledAt != nil {
+		if err := u.userRepo.ScheduleDeletion(ctx, user.ID, nil); err != nil {
+			return nil, err
+		}
+		user.DeleteScheduledAt = nil
+	}
 
-	// 生成双 token
-	accessToken, refreshToken, err := jwt.GenerateTokens(user.ID)
+	// 每次登录创建一个会话，生成双 token
+	sessionId, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, refreshToken, err := jwt.GenerateTokens(user.ID, sessionId)
 	if err != nil {
 		return nil, err
 	}
 
-	accessKey := redis.GetAccessTokenKey(user.ID)
-	refreshKey := redis.GetRefreshTokenKey(user.ID)
+	ip, userAgent, device := request.GetClientInfo(ctx)
+	if device == "" {
+		device = consts.SessionDeviceUnknown
+	}
+	now := time.Now().Unix()
+	sessionKey := redis.GetSessionKey(sessionId)
+	userSessionsKey := redis.GetUserSessionsKey(user.ID)
 
 	// 使用 pipeline 批量操作
 	pipe := vars.Redis.Pipeline()
-	pipe.Set(ctx, accessKey, accessToken, jwt.AccessTokenExpire)
-	pipe.Set(ctx, refreshKey, refreshToken, jwt.RefreshTokenExpire)
+	pipe.HSet(ctx, sessionKey, map[string]interface{}{
+		"user_id":       user.ID,
+		"device":        device,
+		"ip":            ip,
+		"user_agent":    userAgent,
+		"created_at":    now,
+		"last_used_at":  now,
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+	})
+	pipe.Expire(ctx, sessionKey, jwt.RefreshTokenExpire)
+	pipe.SAdd(ctx, userSessionsKey, sessionId)
+	pipe.Expire(ctx, userSessionsKey, jwt.RefreshTokenExpire)
 	if _, err := pipe.Exec(ctx); err != nil {
 		slog.Error("redis pipe exec err:", err)
 		return nil, err
@@ -49,6 +129,7 @@ func (u *userDomainImpl) Login(ctx context.Context, phone, password string) (*dt
 	user.CreatedAt = nil
 	user.UpdatedAt = nil
 	user.Password = ""
+	user.TotpSecret = ""
 
 	return &dto.UserLogin{
 		AccessToken:  accessToken,
@@ -91,3 +172,385 @@ func (u *userDomainImpl) GetUserListByUserIds(ctx context.Context, userIds []uin
 	}
 	return u.userRepo.GetUserListByUserIds(ctx, userIds)
 }
+
+func (u *userDomainImpl) UpdateUserPrivacy(ctx context.Context, userId uint, privacy *dto.UserPrivacy) error {
+	return u.userRepo.UpdateUserPrivacy(ctx, userId, privacy)
+}
+
+// ScheduleDeletion 申请注销，冷静期结束后由后台任务执行注销，返回计划注销时间
+func (u *userDomainImpl) ScheduleDeletion(ctx context.Context, userId uint) (time.Time, error) {
+	graceDays := consts.AccountDeletionGraceDays
+	if vars.App.AccountConfig != nil && vars.App.DeletionGraceDays > 0 {
+		graceDays = vars.App.DeletionGraceDays
+	}
+	at := time.Now().AddDate(0, 0, graceDays)
+	return at, u.userRepo.ScheduleDeletion(ctx, userId, &at)
+}
+
+func (u *userDomainImpl) GetUserIdsDueForDeletion(ctx context.Context, limit int) ([]uint, error) {
+	return u.userRepo.GetUserIdsDueForDeletion(ctx, time.Now(), limit)
+}
+
+func (u *userDomainImpl) AnonymizeUser(ctx context.Context, userId uint) error {
+	return u.userRepo.AnonymizeUser(ctx, userId)
+}
+
+// RefreshToken 轮换 refresh token，每个会话是一个 token 家族，
+// 已被轮换的旧 token 再次使用说明 token 泄露，注销整个会话
+func (u *userDomainImpl) RefreshToken(ctx context.Context, refreshToken string) (*dto.TokenPair, error) {
+	claims, err := jwt.ParseToken(refreshToken, jwt.RefreshToken)
+	if err != nil {
+		return nil, consts.ErrRefreshTokenInvalid
+	}
+	userId, sessionId := claims.UserClaims.ID, claims.UserClaims.SessionId
+	accessToken, newRefreshToken, err := jwt.GenerateTokens(userId, sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lua脚本实现原子的比较并替换，避免并发刷新时同一个 token 被轮换两次
+	script := `
+        local current = redis.call('HGET', KEYS[1], 'refresh_token')
+        if current == ARGV[1] then
+            redis.call('HSET', KEYS[1], 'refresh_token', ARGV[2], 'access_token', ARGV[3], 'last_used_at', ARGV[4])
+            redis.call('EXPIRE', KEYS[1], ARGV[5])
+            redis.call('SADD', KEYS[2], ARGV[6])
+            redis.call('EXPIRE', KEYS[2], ARGV[5])
+            return 1
+        end
+        if redis.call('SISMEMBER', KEYS[2], ARGV[6]) == 1 then
+            return -1
+        end
+        return 0
+    `
+	keys := []string{redis.GetSessionKey(sessionId), redis.GetSessionRotatedKey(sessionId)}
+	result, err := vars.Redis.Eval(ctx, script, keys, refreshToken, newRefreshToken, accessToken,
+		time.Now().Unix(), int64(jwt.RefreshTokenExpire.Seconds()), hashToken(refreshToken)).Int()
+	if err != nil {
+		slog.Error("internal/domain/impl/user_domain_impl.go RefreshToken redis eval err:", "err", err)
+		return nil, err
+	}
+	switch result {
+	case 1:
+		return &dto.TokenPair{AccessToken: accessToken, RefreshToken: newRefreshToken}, nil
+	case -1:
+		slog.Warn("refresh token reuse detected, revoke session", "user_id", userId, "session_id", sessionId)
+		if err := u.RevokeSession(ctx, userId, sessionId); err != nil && !errors.Is(err, consts.ErrSessionNotExist) {
+			return nil, err
+		}
+		return nil, consts.ErrRefreshTokenReused
+	default:
+		return nil, consts.ErrRefreshTokenInvalid
+	}
+}
+
+// ListSessions 获取用户的有效会话，按最近使用时间倒序
+func (u *userDomainImpl) ListSessions(ctx context.Context, userId uint) ([]*dto.Session, error) {
+	userSessionsKey := redis.GetUserSessionsKey(userId)
+	sessionIds, err := vars.Redis.SMembers(ctx, userSessionsKey).Result()
+	if err != nil {
+		slog.Error("internal/domain/impl/user_domain_impl.go ListSessions redis smembers err:", "err", err)
+		return nil, err
+	}
+	sessions := make([]*dto.Session, 0, len(sessionIds))
+	for _, sessionId := range sessionIds {
+		values, err := vars.Redis.HGetAll(ctx, redis.GetSessionKey(sessionId)).Result()
+		if err != nil {
+			slog.Error("internal/domain/impl/user_domain_impl.go ListSessions redis hgetall err:", "err", err)
+			return nil, err
+		}
+		// 会话已过期，顺便清理索引
+		if len(values) == 0 {
+			vars.Redis.SRem(ctx, userSessionsKey, sessionId)
+			continue
+		}
+		createdAt, _ := strconv.ParseInt(values["created_at"], 10, 64)
+		lastUsedAt, _ := strconv.ParseInt(values["last_used_at"], 10, 64)
+		sessions = append(sessions, &dto.Session{
+			ID:         sessionId,
+			Device:     values["device"],
+			IP:         values["ip"],
+			UserAgent:  values["user_agent"],
+			CreatedAt:  createdAt,
+			LastUsedAt: lastUsedAt,
+		})
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt > sessions[j].LastUsedAt
+	})
+	return sessions, nil
+}
+
+// RevokeSession 注销用户的指定会话
+func (u *userDomainImpl) RevokeSession(ctx context.Context, userId uint, sessionId string) error {
+	userSessionsKey := redis.GetUserSessionsKey(userId)
+	ok, err := vars.Redis.SIsMember(ctx, userSessionsKey, sessionId).Result()
+	if err != nil {
+		slog.Error("internal/domain/impl/user_domain_impl.go RevokeSession redis sismember err:", "err", err)
+		return err
+	}
+	if !ok {
+		return consts.ErrSessionNotExist
+	}
+	pipe := vars.Redis.TxPipeline()
+	pipe.Del(ctx, redis.GetSessionKey(sessionId), redis.GetSessionRotatedKey(sessionId))
+	pipe.SRem(ctx, userSessionsKey, sessionId)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Error("internal/domain/impl/user_domain_impl.go RevokeSession redis pipe exec err:", "err", err)
+		return err
+	}
+	return nil
+}
+
+// RevokeSessions 注销用户除 exceptSessionId 外的全部会话，返回被注销的会话ID
+func (u *userDomainImpl) RevokeSessions(ctx context.Context, userId uint, exceptSessionId string) ([]string, error) {
+	userSessionsKey := redis.GetUserSessionsKey(userId)
+	sessionIds, err := vars.Redis.SMembers(ctx, userSessionsKey).Result()
+	if err != nil {
+		slog.Error("internal/domain/impl/user_domain_impl.go RevokeSessions redis smembers err:", "err", err)
+		return nil, err
+	}
+	revoked := make([]string, 0, len(sessionIds))
+	pipe := vars.Redis.TxPipeline()
+	for _, sessionId := range sessionIds {
+		if sessionId == exceptSessionId {
+			continue
+		}
+		pipe.Del(ctx, redis.GetSessionKey(sessionId), redis.GetSessionRotatedKey(sessionId))
+		pipe.SRem(ctx, userSessionsKey, sessionId)
+		revoked = append(revoked, sessionId)
+	}
+	if len(revoked) == 0 {
+		return revoked, nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Error("internal/domain/impl/user_domain_impl.go RevokeSessions redis pipe exec err:", "err", err)
+		return nil, err
+	}
+	return revoked, nil
+}
+
+// EnrollTotp 生成两步验证密钥，需在有效期内用动态码确认后才会开启
+func (u *userDomainImpl) EnrollTotp(ctx context.Context, userId uint) (*dto.TotpEnroll, error) {
+	user, err := u.userRepo.QueryById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.TotpEnabled {
+		return nil, consts.ErrTwoFactorEnabled
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := vars.Redis.Set(ctx, redis.GetTotpEnrollKey(userId), secret, consts.TotpEnrollTTL).Err(); err != nil {
+		slog.Error("internal/domain/impl/user_domain_impl.go EnrollTotp redis set err:", "err", err)
+		return nil, err
+	}
+	return &dto.TotpEnroll{
+		Secret: secret,
+		URI:    totp.URI(consts.TotpIssuer, user.Phone, secret),
+	}, nil
+}
+
+// ConfirmTotp 用第一个动态码确认绑定，开启两步验证并返回恢复码明文，恢复码只在此时返回一次
+func (u *userDomainImpl) ConfirmTotp(ctx context.Context, userId uint, code string) ([]string, error) {
+	secret, err := vars.Redis.Get(ctx, redis.GetTotpEnrollKey(userId)).Result()
+	if errors.Is(err, redis2.Nil) {
+		return nil, consts.ErrTwoFactorEnrollExpired
+	}
+	if err != nil {
+		slog.Error("internal/domain/impl/user_domain_impl.go ConfirmTotp redis get err:", "err", err)
+		return nil, err
+	}
+	if !u.checkTotp(ctx, userId, secret, code) {
+		return nil, consts.ErrTwoFactorCodeInvalid
+	}
+
+	codes := make([]string, 0, consts.RecoveryCodeCount)
+	hashes := make([]string, 0, consts.RecoveryCodeCount)
+	for i := 0; i < consts.RecoveryCodeCount; i++ {
+		raw, err := randomRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, raw[:4]+"-"+raw[4:8]+"-"+raw[8:12]+"-"+raw[12:])
+		hashes = append(hashes, hashRecoveryCode(userId, secret, raw))
+	}
+	if err := u.userRepo.EnableTotp(ctx, userId, secret, hashes); err != nil {
+		return nil, err
+	}
+	vars.Redis.Del(ctx, redis.GetTotpEnrollKey(userId))
+	return codes, nil
+}
+
+// DisableTotp 校验动态码或恢复码后关闭两步验证
+func (u *userDomainImpl) DisableTotp(ctx context.Context, userId uint, code string) error {
+	user, err := u.userRepo.QueryById(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if !user.TotpEnabled {
+		return consts.ErrTwoFactorNotEnabled
+	}
+	ok, err := u.checkSecondFactor(ctx, user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return consts.ErrTwoFactorCodeInvalid
+	}
+	return u.userRepo.DisableTotp(ctx, userId)
+}
+
+func (u *userDomainImpl) GetTotpStatus(ctx context.Context, userId uint) (*dto.TotpStatus, error) {
+	user, err := u.userRepo.QueryById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	status := &dto.TotpStatus{Enabled: user.TotpEnabled}
+	if user.TotpEnabled {
+		if status.RecoveryCodesCount, err = u.userRepo.CountRecoveryCode(ctx, userId); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// GetLoginChallengeUser 获取两步验证挑战对应的用户，用于第二步校验前的登录保护检查
+func (u *userDomainImpl) GetLoginChallengeUser(ctx context.Context, challengeToken string) (*dto.User, error) {
+	val, err := vars.Redis.HGet(ctx, redis.GetLoginChallengeKey(challengeToken), "user_id").Result()
+	if errors.Is(err, redis2.Nil) {
+		return nil, consts.ErrLoginChallengeInvalid
+	}
+	if err != nil {
+		slog.Error("internal/domain/impl/user_domain_impl.go GetLoginChallengeUser redis hget err:", "err", err)
+		return nil, err
+	}
+	userId, _ := strconv.ParseUint(val, 10, 64)
+	user, err := u.userRepo.QueryById(ctx, uint(userId))
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == 0 || !user.TotpEnabled {
+		return nil, consts.ErrLoginChallengeInvalid
+	}
+	return user, nil
+}
+
+// CompleteLogin 两步验证登录第二步，凭挑战 token 和动态码或恢复码签发 token
+func (u *userDomainImpl) CompleteLogin(ctx context.Context, challengeToken, code string) (*dto.UserLogin, error) {
+	// Lua脚本在校验前原子地占用一次尝试次数，并发猜测也不会超过上限；用完最后一次后挑战作废
+	script := `
+        local userId = redis.call('HGET', KEYS[1], 'user_id')
+        if not userId then
+            return false
+        end
+        local attempts = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+        if attempts >= tonumber(ARGV[1]) then
+            redis.call('DEL', KEYS[1])
+        end
+        if attempts > tonumber(ARGV[1]) then
+            return false
+        end
+        return userId
+    `
+	key := redis.GetLoginChallengeKey(challengeToken)
+	val, err := vars.Redis.Eval(ctx, script, []string{key}, consts.LoginChallengeMaxAttempts).Text()
+	if errors.Is(err, redis2.Nil) {
+		return nil, consts.ErrLoginChallengeInvalid
+	}
+	if err != nil {
+		slog.Error("internal/domain/impl/user_domain_impl.go CompleteLogin redis eval err:", "err", err)
+		return nil, err
+	}
+	userId, _ := strconv.ParseUint(val, 10, 64)
+	user, err := u.userRepo.QueryById(ctx, uint(userId))
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == 0 || !user.TotpEnabled {
+		vars.Redis.Del(ctx, key)
+		return nil, consts.ErrLoginChallengeInvalid
+	}
+
+	ok, err := u.checkSecondFactor(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, consts.ErrTwoFactorCodeInvalid
+	}
+	vars.Redis.Del(ctx, key)
+	return u.issueTokens(ctx, user)
+}
+
+// checkSecondFactor 校验动态码，不是动态码格式时按恢复码校验
+func (u *userDomainImpl) checkSecondFactor(ctx context.Context, user *dto.User, code string) (bool, error) {
+	code = strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+	if len(code) == totp.Digits {
+		return u.checkTotp(ctx, user.ID, user.TotpSecret, code), nil
+	}
+	return u.userRepo.UseRecoveryCode(ctx, user.ID, hashRecoveryCode(user.ID, user.TotpSecret, code))
+}
+
+// checkTotp 校验动态码，同一时间步的动态码只能使用一次
+func (u *userDomainImpl) checkTotp(ctx context.Context, userId uint, secret, code string) bool {
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok {
+		return false
+	}
+	ttl := time.Duration(totp.Period*(2*totp.Skew+1)) * time.Second
+	fresh, err := vars.Redis.SetNX(ctx, redis.GetTotpUsedKey(userId, step), 1, ttl).Result()
+	if err != nil {
+		slog.Error("internal/domain/impl/user_domain_impl.go checkTotp redis setnx err:", "err", err)
+		return false
+	}
+	return fresh
+}
+
+// hashRecoveryCode 以用户的两步验证密钥为 key 计算 hmac，不同用户相同的恢复码哈希不同，只泄露恢复码表无法离线破解
+func hashRecoveryCode(userId uint, secret, code string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d:%s", userId, code)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// randomRecoveryCode 生成小写 base32 编码的恢复码
+func randomRecoveryCode() (string, error) {
+	buf := make([]byte, consts.RecoveryCodeBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf)), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// MuteUser 禁言用户，until 为空表示解除禁言
+func (u *userDomainImpl) MuteUser(ctx context.Context, userId uint, until *time.Time) error {
+	return u.userRepo.UpdateMutedUntil(ctx, userId, until)
+}
+
+// BanUser 封禁用户并注销其全部会话，until 为空表示解除封禁
+func (u *userDomainImpl) BanUser(ctx context.Context, userId uint, until *time.Time) error {
+	if err := u.userRepo.UpdateBannedUntil(ctx, userId, until); err != nil {
+		return err
+	}
+	if until == nil {
+		return nil
+	}
+	_, err := u.RevokeSessions(ctx, userId, "")
+	return err
+}
@@ -0,0 +1,116 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"log/slog"
+	"loop_server/infra/consts"
+	"loop_server/infra/redis"
+	"loop_server/infra/sms"
+	"loop_server/infra/vars"
+	"math/big"
+	"strings"
+	"time"
+)
+
+type verifyDomainImpl struct {
+	sender sms.Sender
+}
+
+func NewVerifyDomainImpl(sender sms.Sender) *verifyDomainImpl {
+	return &verifyDomainImpl{sender: sender}
+}
+
+// SendCode 生成并发送验证码，同一手机号限制重发间隔和每日次数；
+// deliver 为 false 时只计入频率限制不发送，使场景不适用的手机号与正常发送的返回一致
+func (v *verifyDomainImpl) SendCode(ctx context.Context, scene, phone string, deliver bool) error {
+	ok, err := vars.Redis.SetNX(ctx, redis.GetVerifyResendKey(scene, phone), 1, consts.VerifyCodeResendInterval).Result()
+	if err != nil {
+		slog.Error("internal/domain/impl/verify_domain_impl.go SendCode redis setnx err:", "err", err)
+		return err
+	}
+	if !ok {
+		return consts.ErrVerifyCodeTooFrequent
+	}
+
+	dailyKey := redis.GetVerifyDailyKey(phone)
+	count, err := vars.Redis.Incr(ctx, dailyKey).Result()
+	if err != nil {
+		slog.Error("internal/domain/impl/verify_domain_impl.go SendCode redis incr err:", "err", err)
+		return err
+	}
+	if count == 1 {
+		vars.Redis.Expire(ctx, dailyKey, 24*time.Hour)
+	}
+	if count > consts.VerifyCodeDailyLimit {
+		return consts.ErrVerifyCodeLimit
+	}
+	if !deliver {
+		return nil
+	}
+
+	code, err := generateCode(consts.VerifyCodeLength)
+	if err != nil {
+		return err
+	}
+	codeKey := redis.GetVerifyCodeKey(scene, phone)
+	pipe := vars.Redis.TxPipeline()
+	pipe.Del(ctx, codeKey)
+	pipe.HSet(ctx, codeKey, "code", code, "attempts", 0)
+	pipe.Expire(ctx, codeKey, consts.VerifyCodeTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Error("internal/domain/impl/verify_domain_impl.go SendCode redis pipe exec err:", "err", err)
+		return err
+	}
+
+	if err := v.sender.Send(ctx, phone, scene, code); err != nil {
+		slog.Error("internal/domain/impl/verify_domain_impl.go SendCode send err:", "err", err)
+		vars.Redis.Del(ctx, codeKey)
+		return err
+	}
+	return nil
+}
+
+// CheckCode 校验验证码，校验成功后验证码失效，错误次数超限后验证码作废
+func (v *verifyDomainImpl) CheckCode(ctx context.Context, scene, phone, code string) error {
+	// Lua脚本原子地比较、计数和作废，并发猜测也不会超过次数上限；
+	// 只在验证码存在时计数，不会留下没有过期时间的 key
+	script := `
+        local stored = redis.call('HGET', KEYS[1], 'code')
+        if not stored then
+            return -1
+        end
+        if stored == ARGV[1] then
+            redis.call('DEL', KEYS[1])
+            return 1
+        end
+        local attempts = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+        if attempts >= tonumber(ARGV[2]) then
+            redis.call('DEL', KEYS[1])
+        end
+        return 0
+    `
+	result, err := vars.Redis.Eval(ctx, script, []string{redis.GetVerifyCodeKey(scene, phone)},
+		strings.TrimSpace(code), consts.VerifyCodeMaxAttempts).Int()
+	if err != nil {
+		slog.Error("internal/domain/impl/verify_domain_impl.go CheckCode redis eval err:", "err", err)
+		return err
+	}
+	if result != 1 {
+		return consts.ErrVerifyCodeInvalid
+	}
+	return nil
+}
+
+// generateCode 生成指定位数的数字验证码
+func generateCode(length int) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(byte('0' + n.Int64()))
+	}
+	return sb.String(), nil
+}
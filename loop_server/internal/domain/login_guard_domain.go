@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+type LoginGuardDomain interface {
+	Check(ctx context.Context, phone, ip, captchaToken string) error
+	Fail(ctx context.Context, phone, ip string) error
+	Success(ctx context.Context, phone, ip string) error
+	Unlock(ctx context.Context, phone string) error
+}
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+	"loop_server/internal/model/dto"
+)
+
+type ModerationDomain interface {
+	Check(ctx context.Context, item *dto.ModerationItem) (*dto.ModerationResult, error)
+	GetReviewList(ctx context.Context, status, offset, limit int) (*dto.ModerationReviewList, error)
+	GetPendingReview(ctx context.Context, id uint) (*dto.ModerationReview, error)
+	ResolveReview(ctx context.Context, id uint, status int) (*dto.ModerationReview, error)
+	Run(ctx context.Context)
+}
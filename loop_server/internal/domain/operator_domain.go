@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+	"loop_server/internal/model/dto"
+)
+
+type OperatorDomain interface {
+	Login(ctx context.Context, username, password string) (*dto.OperatorLogin, error)
+	Logout(ctx context.Context, operatorId uint, token string) error
+	CreateOperator(ctx context.Context, operator *dto.Operator) error
+	UpdateOperator(ctx context.Context, operator *dto.Operator) error
+	GetOperatorList(ctx context.Context) ([]*dto.Operator, error)
+	InitSuperadmin(ctx context.Context, username, password string) error
+}
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+	"loop_server/internal/model/dto"
+)
+
+type ReportDomain interface {
+	CreateReport(ctx context.Context, report *dto.Report) error
+	GetReport(ctx context.Context, id uint) (*dto.Report, error)
+	GetReportList(ctx context.Context, status int, targetType string, offset, limit int) (*dto.ReportList, error)
+	GetReportInfo(ctx context.Context, id uint) (*dto.ReportInfo, error)
+	HandleReport(ctx context.Context, action *dto.ReportAction, status int) error
+}
@@ -3,13 +3,31 @@ package domain
 import (
 	"context"
 	"loop_server/internal/model/dto"
+	"time"
 )
 
 type UserDomain interface {
 	Login(ctx context.Context, phone, password string) (*dto.UserLogin, error)
+	LoginByPhone(ctx context.Context, phone string) (*dto.UserLogin, error)
 	Register(ctx context.Context, user *dto.User) error
 	QueryUser(ctx context.Context, param *dto.QueryUserRequest) (*dto.User, error)
 	UpdateUser(ctx context.Context, user *dto.User) error
 	UpdateUserPassword(ctx context.Context, userId uint, password string) error
 	GetUserListByUserIds(ctx context.Context, userIds []uint) ([]*dto.User, error)
+	UpdateUserPrivacy(ctx context.Context, userId uint, privacy *dto.UserPrivacy) error
+	ScheduleDeletion(ctx context.Context, userId uint) (time.Time, error)
+	GetUserIdsDueForDeletion(ctx context.Context, limit int) ([]uint, error)
+	AnonymizeUser(ctx context.Context, userId uint) error
+	RefreshToken(ctx context.Context, refreshToken string) (*dto.TokenPair, error)
+	ListSessions(ctx context.Context, userId uint) ([]*dto.Session, error)
+	RevokeSession(ctx context.Context, userId uint, sessionId string) error
+	RevokeSessions(ctx context.Context, userId uint, exceptSessionId string) ([]string, error)
+	EnrollTotp(ctx context.Context, userId uint) (*dto.TotpEnroll, error)
+	ConfirmTotp(ctx context.Context, userId uint, code string) ([]string, error)
+	DisableTotp(ctx context.Context, userId uint, code string) error
+	GetTotpStatus(ctx context.Context, userId uint) (*dto.TotpStatus, error)
+	GetLoginChallengeUser(ctx context.Context, challengeToken string) (*dto.User, error)
+	CompleteLogin(ctx context.Context, challengeToken, code string) (*dto.UserLogin, error)
+	MuteUser(ctx context.Context, userId uint, until *time.Time) error
+	BanUser(ctx context.Context, userId uint, until *time.Time) error
 }
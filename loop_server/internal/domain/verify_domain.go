@@ -0,0 +1,8 @@
+package domain
+
+import "context"
+
+type VerifyDomain interface {
+	SendCode(ctx context.Context, scene, phone string, deliver bool) error
+	CheckCode(ctx context.Context, scene, phone, code string) error
+}
@@ -0,0 +1,76 @@
+package dto
+
+import "time"
+
+type Operator struct {
+	ID          uint       `json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Username    string     `json:"username"`
+	Password    string     `json:"-"`
+	Nickname    string     `json:"nickname"`
+	Role        string     `json:"role"` // viewer、moderator、superadmin
+	Disabled    bool       `json:"disabled"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+type OperatorLogin struct {
+	Token     string    `json:"token"`
+	ExpiresAt int64     `json:"expires_at"` // 会话过期时间戳
+	Operator  *Operator `json:"operator"`
+}
+
+type AuditLog struct {
+	ID         uint      `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	ActorType  string    `json:"actor_type"`
+	ActorId    uint      `json:"actor_id"`
+	Action     string    `json:"action"`
+	TargetType string    `json:"target_type"`
+	TargetId   string    `json:"target_id"`
+	Ip         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	Detail     string    `json:"detail"`
+	Before     string    `json:"before"` // 变更前的状态 json
+	After      string    `json:"after"`  // 变更后的状态 json
+}
+
+type AuditLogList struct {
+	Total int64       `json:"total"`
+	List  []*AuditLog `json:"list"`
+}
+
+// AuditLogQuery 审计日志查询条件，零值不过滤
+type AuditLogQuery struct {
+	ActorType  string
+	ActorId    uint
+	TargetType string
+	TargetId   string
+	UserId     uint // 该用户本人发起的日志，不包含管理员的处理记录，避免泄露管理员信息
+	StartTime  time.Time
+	EndTime    time.Time
+}
+
+// AdminUserInfo 管理后台查看的用户信息，包含用户不可见的状态
+type AdminUserInfo struct {
+	User              *User      `json:"user"`
+	Online            bool       `json:"online"`
+	MutedUntil        *time.Time `json:"muted_until,omitempty"`
+	BannedUntil       *time.Time `json:"banned_until,omitempty"`
+	DeleteScheduledAt *time.Time `json:"delete_scheduled_at,omitempty"`
+	TotpEnabled       bool       `json:"totp_enabled"`
+	Sessions          []*Session `json:"sessions"`
+}
+
+type AdminGroupInfo struct {
+	Group   *Group       `json:"group"`
+	Members []*GroupShip `json:"members"`
+}
+
+type OnlineStat struct {
+	Count int64 `json:"count"`
+}
+
+type SfuRoom struct {
+	GroupId        uint   `json:"group_id"`
+	ParticipantIds []uint `json:"participant_ids"`
+}
@@ -3,11 +3,29 @@ package dto
 import "time"
 
 type FriendShip struct {
-	ID        uint      `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	UserId    uint
-	FriendId  uint
+	ID         uint      `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	UserId     uint
+	FriendId   uint
+	Remark     string   // 好友备注
+	Tags       []string // 标签
+	CategoryId uint     // 好友分组id
+}
+
+type Friend struct {
+	*User
+	Remark     string   `json:"remark"`      // 好友备注
+	Tags       []string `json:"tags"`        // 标签
+	CategoryId uint     `json:"category_id"` // 好友分组id，0-默认分组
+	Category   string   `json:"category"`    // 好友分组名称
+}
+
+type FriendCategory struct {
+	ID     uint   `json:"id"`
+	UserId uint   `json:"-"`
+	Name   string `json:"name"` // 分组名称
+	Sort   int    `json:"sort"` // 排序，越小越靠前
 }
 
 type FriendRequest struct {
@@ -16,19 +34,39 @@ type FriendRequest struct {
 	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
 	RequesterId uint       `json:"requester_id"` // 请求者ID
 	RecipientId uint       `json:"recipient_id"` // 接收者ID
-	Status      int        `json:"status"`       // 0: 未处理 1: 已同意 2: 已拒绝
+	Status      int        `json:"status"`       // 0: 未处理 1: 已同意 2: 已拒绝 3: 已撤回 4: 已过期
 	Message     string     `json:"message"`      // 请求消息
 }
 
 type FriendRequestListNode struct {
-	RequesterId uint   `json:"requester_id"` // 请求者ID
-	RecipientId uint   `json:"recipient_id"` // 接收者ID
-	Status      int    `json:"status"`       // 0: 未处理 1: 已同意 2: 已拒绝
-	Message     string `json:"message"`      // 请求消息
-	Nickname    string `json:"name"`         // 昵称
-	Avatar      string `json:"avatar"`       // 头像
+	ID          uint       `json:"id"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	RequesterId uint       `json:"requester_id"` // 请求者ID
+	RecipientId uint       `json:"recipient_id"` // 接收者ID
+	Status      int        `json:"status"`       // 0: 未处理 1: 已同意 2: 已拒绝 3: 已撤回 4: 已过期
+	Message     string     `json:"message"`      // 请求消息
+	Nickname    string     `json:"name"`         // 昵称
+	Avatar      string     `json:"avatar"`       // 头像
 }
 
 type FriendListStatistics struct {
 	UntreatedCount int `json:"untreated_count"`
 }
+
+type FriendEvent struct {
+	UserId   uint   `json:"user_id"`           // 触发事件的用户id
+	Nickname string `json:"nickname"`          // 昵称
+	Avatar   string `json:"avatar"`            // 头像
+	Status   int    `json:"status,omitempty"`  // 好友请求处理结果:1-已同意，2-已拒绝，3-已撤回
+	Message  string `json:"message,omitempty"` // 验证消息
+	SendTime int64  `json:"send_time"`         // 发送时间戳
+}
+
+type FriendRecommend struct {
+	UserId            uint   `json:"user_id"`
+	Nickname          string `json:"nickname"`
+	Avatar            string `json:"avatar"`
+	Signature         string `json:"signature"`
+	MutualFriendCount int    `json:"mutual_friend_count"` // 共同好友数
+	SharedGroupCount  int    `json:"shared_group_count"`  // 共同群数
+}
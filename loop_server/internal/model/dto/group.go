@@ -5,14 +5,24 @@ import (
 )
 
 type Group struct {
-	ID        uint       `json:"id"`        // id
-	Name      string     `json:"name"`      // 群名称
-	Avatar    string     `json:"avatar"`    // 群头像
-	Describe  string     `json:"describe"`  // 群简介
-	OwnerId   uint       `json:"owner_id"`  // 群主id
-	AdminIds  []uint     `json:"admin_ids"` // 管理员id
-	CreatedAt *time.Time `json:"created_at,omitempty"`
-	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	ID          uint            `json:"id"`                     // id
+	Name        string          `json:"name"`                   // 群名称
+	Avatar      string          `json:"avatar"`                 // 群头像
+	Describe    string          `json:"describe"`               // 群简介
+	OwnerId     uint            `json:"owner_id"`               // 群主id
+	AdminIds    []uint          `json:"admin_ids"`              // 管理员id
+	Type        int             `json:"type"`                   // 群类型:0-普通群，1-大群
+	MaxMember   int             `json:"max_member"`             // 成员上限
+	Permission  map[string]uint `json:"permission,omitempty"`   // 群权限配置:操作-最低角色
+	Remark      string          `json:"remark,omitempty"`       // 我的群备注，仅自己可见
+	MyNickname  string          `json:"my_nickname,omitempty"`  // 我的群昵称
+	IsPublic    bool            `json:"is_public"`              // 是否公开
+	Tags        []string        `json:"tags,omitempty"`         // 标签
+	Category    string          `json:"category,omitempty"`     // 分类
+	JoinPolicy  int             `json:"join_policy"`            // 加群方式:0-直接加入，1-需审批，2-仅邀请
+	MemberCount int             `json:"member_count,omitempty"` // 成员数
+	CreatedAt   *time.Time      `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time      `json:"updated_at,omitempty"`
 }
 
 type GroupShip struct {
@@ -22,8 +32,38 @@ type GroupShip struct {
 	GroupId     uint      `json:"group_id"`
 	UserId      uint      `json:"user_id"`
 	Role        uint      `json:"role"`
-	Remark      string    `json:"remark"`
-	GroupRemark string    `json:"group_remark"`
+	Remark      string    `json:"remark"`       // 群昵称
+	GroupRemark string    `json:"group_remark"` // 群备注
+}
+
+type GroupPermission struct {
+	GroupId uint   `json:"group_id"`
+	Action  string `json:"action"`   // 群操作
+	MinRole uint   `json:"min_role"` // 最低角色
+}
+
+type GroupSearchRequest struct {
+	Keyword  string // 匹配群名称、标签
+	Category string
+	Offset   int
+	Limit    int
+}
+
+type GroupSearchResult struct {
+	List  []*Group `json:"list"`
+	Total int64    `json:"total"`
+}
+
+type GroupJoinRequest struct {
+	ID        uint       `json:"id"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	GroupId   uint       `json:"group_id"`
+	UserId    uint       `json:"user_id"`
+	Status    int        `json:"status"`  // 0: 未处理 1: 已同意 2: 已拒绝
+	Message   string     `json:"message"` // 验证消息
+	Nickname  string     `json:"nickname,omitempty"`
+	Avatar    string     `json:"avatar,omitempty"`
 }
 
 type CreateGroupRequest struct {
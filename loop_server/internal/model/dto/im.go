@@ -21,13 +21,14 @@ type GroupMessage struct {
 	SenderId       uint   `json:"sender_id"`   // 发送者id
 	ReceiverId     uint   `json:"receiver_id"` // 接收者id
 	ReceiverIds    []uint `json:"receiver_ids"`
-	Content        string `json:"content"`         // 消息内容
-	Type           int    `json:"type"`            // 消息类型:0-文字，1-图片，2-文件，3-语音，4-视频
-	SendTime       int64  `json:"send_time"`       // 发送时间戳
-	SenderNickname string `json:"sender_nickname"` // 发送者昵称
-	SenderAvatar   string `json:"sender_avatar"`   // 发送者头像
-	GroupName      string `json:"group_name"`      // 群名称
-	GroupAvatar    string `json:"group_avatar"`    // 群头像
+	Content        string `json:"content"`          // 消息内容
+	Type           int    `json:"type"`             // 消息类型:0-文字，1-图片，2-文件，3-语音，4-视频
+	SendTime       int64  `json:"send_time"`        // 发送时间戳
+	SenderNickname string `json:"sender_nickname"`  // 发送者昵称
+	SenderAvatar   string `json:"sender_avatar"`    // 发送者头像
+	GroupName      string `json:"group_name"`       // 群名称
+	GroupAvatar    string `json:"group_avatar"`     // 群头像
+	AtAll          bool   `json:"at_all,omitempty"` // 是否@所有人
 }
 
 type GroupOfflineMessage struct {
@@ -35,10 +36,11 @@ type GroupOfflineMessage struct {
 }
 
 type Ack struct {
-	SeqId      string `json:"seq_id"`      // 唯一标识
-	SenderId   uint   `json:"sender_id"`   // 发送者Id
-	ReceiverId uint   `json:"receiver_id"` // 接收者Id
-	IsGroup    bool   `json:"is_group"`    // 是否是群消息
+	SeqId      string `json:"seq_id"`         // 唯一标识
+	SenderId   uint   `json:"sender_id"`      // 发送者Id
+	ReceiverId uint   `json:"receiver_id"`    // 接收者Id
+	IsGroup    bool   `json:"is_group"`       // 是否是群消息
+	Code       int    `json:"code,omitempty"` // 失败原因，0 表示成功
 }
 
 type WebRTCMessage struct {
@@ -65,3 +67,16 @@ type Message struct {
 	Token string          `json:"token,omitempty"`
 	Data  json.RawMessage `json:"data"`
 }
+
+// WsAuthResult ws 认证成功的回复
+type WsAuthResult struct {
+	UserId    uint  `json:"user_id"`
+	ExpiresAt int64 `json:"expires_at"` // access token 过期时间，需在此之前重新认证
+}
+
+// WsError 客户端命令处理失败时下发的错误帧
+type WsError struct {
+	Cmd        int    `json:"cmd"`                   // 出错的命令
+	Reason     string `json:"reason"`                // 错误原因
+	RetryAfter int64  `json:"retry_after,omitempty"` // 建议的重试等待毫秒数
+}
@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+// ModerationItem 待审核的内容
+type ModerationItem struct {
+	Scene    string // 场景
+	UserId   uint   // 发布内容的用户
+	TargetId string // 内容标识，消息为 seq_id，资料为用户或群 id
+	Content  string
+}
+
+// ModerationResult 内容审核结果
+type ModerationResult struct {
+	Action  string   // 处理方式:pass、mask、flag、reject
+	Content string   // 处理后的内容
+	Words   []string // 命中的敏感词
+}
+
+type ModerationReview struct {
+	ID         uint       `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Scene      string     `json:"scene"`
+	UserId     uint       `json:"user_id"`
+	TargetId   string     `json:"target_id"`
+	Content    string     `json:"content"`
+	Source     string     `json:"source"` // word-敏感词，classifier-分类器
+	Reason     string     `json:"reason"`
+	Status     int        `json:"status"` // 0-待审核，1-通过，2-确认违规
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+}
+
+type ModerationReviewList struct {
+	Total int64               `json:"total"`
+	List  []*ModerationReview `json:"list"`
+}
@@ -0,0 +1,66 @@
+package dto
+
+import "time"
+
+type Report struct {
+	ID             uint       `json:"id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ReporterId     uint       `json:"reporter_id"`
+	TargetType     string     `json:"target_type"`    // user、group、private_message、group_message
+	TargetId       string     `json:"target_id"`      // 用户或群为 id，消息为 seq_id
+	TargetUserId   uint       `json:"target_user_id"` // 被举报的责任用户，群举报为群主
+	GroupId        uint       `json:"group_id,omitempty"`
+	Reason         string     `json:"reason"`
+	Description    string     `json:"description"`
+	Snapshot       string     `json:"snapshot"`        // 举报对象及上下文快照 json
+	SnapshotSource string     `json:"snapshot_source"` // server-服务端生成，client-客户端提交
+	Status         int        `json:"status"`          // 0-待处理，1-已处理，2-已驳回
+	HandledAt      *time.Time `json:"handled_at,omitempty"`
+}
+
+type ReportAction struct {
+	ID           uint       `json:"id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ReportId     uint       `json:"report_id"`
+	Action       string     `json:"action"`
+	OperatorId   uint       `json:"operator_id"`
+	TargetUserId uint       `json:"target_user_id,omitempty"`
+	GroupId      uint       `json:"group_id,omitempty"`
+	TargetId     string     `json:"target_id,omitempty"`
+	Until        *time.Time `json:"until,omitempty"`
+	Note         string     `json:"note"`
+}
+
+type ReportList struct {
+	Total int64     `json:"total"`
+	List  []*Report `json:"list"`
+}
+
+type ReportInfo struct {
+	Report  *Report         `json:"report"`
+	Actions []*ReportAction `json:"actions"`
+}
+
+// ReportSnapshot 举报时保存的对象和上下文
+type ReportSnapshot struct {
+	User     *User            `json:"user,omitempty"`
+	Group    *Group           `json:"group,omitempty"`
+	Messages []*ReportMessage `json:"messages,omitempty"`
+}
+
+type ReportMessage struct {
+	SeqId      string `json:"seq_id"`
+	SenderId   uint   `json:"sender_id"`
+	ReceiverId uint   `json:"receiver_id,omitempty"`
+	Content    string `json:"content"`
+	Type       int    `json:"type"`
+	SendTime   int64  `json:"send_time"`
+	Reported   bool   `json:"reported,omitempty"` // 是否为被举报的消息
+}
+
+// SystemNotice 系统通知
+type SystemNotice struct {
+	Type     string `json:"type"`
+	Content  string `json:"content"`
+	SendTime int64  `json:"send_time"`
+}
@@ -5,22 +5,84 @@ import (
 )
 
 type User struct {
-	ID        uint       `json:"id,omitempty"`
-	CreatedAt *time.Time `json:"created_at,omitempty"`
-	UpdatedAt *time.Time `json:"updated_at,omitempty"`
-	Nickname  string     `json:"nickname"`
-	Password  string     `json:"password,omitempty"`
-	Phone     string     `json:"phone,omitempty"`
-	Avatar    string     `json:"avatar,omitempty"`
-	Signature string     `json:"signature"`
-	Gender    int        `json:"gender,omitempty"`
-	Age       int        `json:"age"`
+	ID        uint         `json:"id,omitempty"`
+	CreatedAt *time.Time   `json:"created_at,omitempty"`
+	UpdatedAt *time.Time   `json:"updated_at,omitempty"`
+	Nickname  string       `json:"nickname"`
+	Password  string       `json:"password,omitempty"`
+	Phone     string       `json:"phone,omitempty"`
+	Avatar    string       `json:"avatar,omitempty"`
+	Signature string       `json:"signature"`
+	Gender    int          `json:"gender,omitempty"`
+	Age       int          `json:"age"`
+	Privacy   *UserPrivacy `json:"-"`
+
+	DeleteScheduledAt *time.Time `json:"-"` // 计划注销时间
+	TotpSecret        string     `json:"-"` // 两步验证密钥
+	TotpEnabled       bool       `json:"-"` // 是否开启两步验证
+	MutedUntil        *time.Time `json:"-"` // 禁言截止时间
+	BannedUntil       *time.Time `json:"-"` // 封禁截止时间
+}
+
+type AccountExportStatus struct {
+	Status    string `json:"status"`              // pending-导出中，done-已完成，failed-导出失败
+	CreatedAt int64  `json:"created_at"`          // 申请时间戳
+	ExpireAt  int64  `json:"expire_at,omitempty"` // 文件过期时间戳
+}
+
+type AccountDeletion struct {
+	DeleteAt int64 `json:"delete_at"` // 计划注销时间戳，此前重新登录可取消注销
+}
+
+type AccountExport struct {
+	ExportedAt       int64                   `json:"exported_at"`
+	Profile          *User                   `json:"profile"`
+	Privacy          *UserPrivacy            `json:"privacy"`
+	Friends          []*Friend               `json:"friends"`
+	FriendCategories []*FriendCategory       `json:"friend_categories"`
+	BlockList        []*User                 `json:"block_list"`
+	Groups           []*Group                `json:"groups"`
+	Messages         []*AccountExportMessage `json:"messages"`
+}
+
+type AccountExportMessage struct {
+	GroupId  uint   `json:"group_id"`
+	SeqId    string `json:"seq_id"`
+	Content  string `json:"content"`
+	Type     int    `json:"type"`
+	SendTime int64  `json:"send_time"`
+}
+
+type UserPrivacy struct {
+	SearchByPhone      bool `json:"search_by_phone"`      // 是否允许通过手机号搜索到我
+	FriendRequestScope int  `json:"friend_request_scope"` // 谁可以加我为好友:0-所有人，1-共同好友或同群成员，2-任何人都不可以
+	StrangerMessage    bool `json:"stranger_message"`     // 是否允许陌生人私聊
+	ProfileScope       int  `json:"profile_scope"`        // 谁可以看我的签名、年龄、性别:0-所有人，1-仅好友，2-仅自己
+	CallScope          int  `json:"call_scope"`           // 谁可以给我打电话:0-所有人，1-仅好友，2-任何人都不可以
 }
 
 type UserLogin struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 	User         *User  `json:"user"`
+
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"` // 需要两步验证，凭 challenge_token 调用 /login/2fa 完成登录
+	ChallengeToken    string `json:"challenge_token,omitempty"`     // 两步验证挑战 token
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type TotpEnroll struct {
+	Secret string `json:"secret"` // base32 密钥，无法扫码时手动输入
+	URI    string `json:"uri"`    // otpauth 链接
+}
+
+type TotpStatus struct {
+	Enabled            bool  `json:"enabled"`
+	RecoveryCodesCount int64 `json:"recovery_codes_count"` // 剩余可用恢复码数量
 }
 
 type QueryUserRequest struct {
@@ -37,3 +99,13 @@ type UserInfo struct {
 	Age       int    `json:"age"`
 	IsFriend  bool   `json:"is_friend"`
 }
+
+type Session struct {
+	ID         string `json:"id"`
+	Device     string `json:"device"`       // 设备名
+	IP         string `json:"ip"`           // 登录 IP
+	UserAgent  string `json:"user_agent"`   // 登录时的 User-Agent
+	CreatedAt  int64  `json:"created_at"`   // 登录时间戳
+	LastUsedAt int64  `json:"last_used_at"` // 最近使用时间戳
+	Current    bool   `json:"current"`      // 是否为当前会话
+}
@@ -0,0 +1,70 @@
+package param
+
+type AdminUnlockUserRequest struct {
+	Phone string `json:"phone" binding:"required"`
+}
+
+type ModerationReviewListRequest struct {
+	Page
+	Status int `form:"status" binding:"min=0,max=2"` // 0-待审核，1-通过，2-确认违规
+}
+
+type ResolveModerationReviewRequest struct {
+	Id     uint `json:"id" binding:"required"`
+	Status int  `json:"status" binding:"oneof=1 2"` // 1-通过，2-确认违规
+}
+
+type OperatorLoginRequest struct {
+	Username     string `json:"username" binding:"required"`
+	Password     string `json:"password" binding:"required"`
+	CaptchaToken string `json:"captcha_token"`
+}
+
+type CreateOperatorRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=32"`
+	Password string `json:"password" binding:"required,min=8,max=64"`
+	Nickname string `json:"nickname" binding:"max=32"`
+	Role     string `json:"role" binding:"required,oneof=viewer moderator superadmin"`
+}
+
+type UpdateOperatorRequest struct {
+	Id       uint   `json:"id" binding:"required"`
+	Nickname string `json:"nickname" binding:"max=32"`
+	Role     string `json:"role" binding:"required,oneof=viewer moderator superadmin"`
+	Disabled bool   `json:"disabled"`
+	Password string `json:"password" binding:"omitempty,min=8,max=64"` // 为空时不修改
+}
+
+type AdminQueryUserRequest struct {
+	UserId uint   `form:"user_id"`
+	Phone  string `form:"phone"`
+}
+
+type AdminBanUserRequest struct {
+	UserId          uint   `json:"user_id" binding:"required"`
+	DurationMinutes int    `json:"duration_minutes" binding:"min=0"` // 0 为永久
+	Note            string `json:"note" binding:"max=255"`
+}
+
+type AdminUserRequest struct {
+	UserId uint   `json:"user_id" binding:"required"`
+	Note   string `json:"note" binding:"max=255"`
+}
+
+type AdminGroupRequest struct {
+	GroupId uint `form:"group_id" binding:"required"`
+}
+
+type AdminGroupMessageRequest struct {
+	SeqId string `form:"seq_id" binding:"required"`
+}
+
+type AdminAuditLogRequest struct {
+	Page
+	ActorType  string `form:"actor_type" binding:"omitempty,oneof=operator user"`
+	ActorId    uint   `form:"actor_id"`
+	TargetType string `form:"target_type"`
+	TargetId   string `form:"target_id"`
+	StartTime  int64  `form:"start_time"` // 秒级时间戳，为 0 时不限制
+	EndTime    int64  `form:"end_time"`
+}
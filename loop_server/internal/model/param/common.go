@@ -2,10 +2,14 @@ package param
 
 type Page struct {
 	PageNum  int `form:"page_num"`  // 默认为 1
-	PageSize int `form:"page_size"` // 默认为 10
+	PageSize int `form:"page_size"` // 默认为 10，最大为 100
 }
 
 func (p *Page) Init() {
-	p.PageSize = max(10, p.PageSize)
+	p.PageSize = min(max(10, p.PageSize), 100)
 	p.PageNum = max(1, p.PageNum)
 }
+
+func (p *Page) Offset() int {
+	return (p.PageNum - 1) * p.PageSize
+}
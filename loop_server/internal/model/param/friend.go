@@ -2,7 +2,11 @@ package param
 
 type DisposeFriendRequest struct {
 	RequesterId uint `json:"requester_id" binding:"required"`
-	Status      int  `json:"status" binding:"required"` // 1-同意，2-拒绝
+	Status      int  `json:"status" binding:"required,oneof=1 2"` // 1-同意，2-拒绝
+}
+
+type WithdrawFriendRequest struct {
+	RecipientId uint `json:"recipient_id" binding:"required"`
 }
 
 type AddFriendRequest struct {
@@ -20,3 +24,43 @@ type InviteFriendAddGroupList struct {
 	UserNickname string `json:"user_nickname"`
 	IsGroup      bool   `json:"is_group"`
 }
+
+type BlockUserRequest struct {
+	UserId uint `json:"user_id" binding:"required"`
+}
+
+type UpdateFriendRemarkRequest struct {
+	FriendId uint   `json:"friend_id" binding:"required"`
+	Remark   string `json:"remark" binding:"max=32"`
+}
+
+type UpdateFriendTagsRequest struct {
+	FriendId uint     `json:"friend_id" binding:"required"`
+	Tags     []string `json:"tags" binding:"max=10,dive,required,max=16,excludesall=0x2C"` // 最多 10 个标签，不能包含逗号
+}
+
+type MoveFriendCategoryRequest struct {
+	FriendIds  []uint `json:"friend_ids" binding:"required,min=1"`
+	CategoryId uint   `json:"category_id"` // 0-默认分组
+}
+
+type CreateFriendCategoryRequest struct {
+	Name string `json:"name" binding:"required,max=16"`
+}
+
+type UpdateFriendCategoryRequest struct {
+	CategoryId uint   `json:"category_id" binding:"required"`
+	Name       string `json:"name" binding:"required,max=16"`
+}
+
+type DeleteFriendCategoryRequest struct {
+	CategoryId uint `json:"category_id" binding:"required"`
+}
+
+type SortFriendCategoryRequest struct {
+	CategoryIds []uint `json:"category_ids" binding:"required,min=1"` // 按顺序排列的分组id
+}
+
+type FriendRecommendRequest struct {
+	Limit int `form:"limit"` // 默认 20，最大 50
+}
@@ -21,7 +21,8 @@ type DeleteAdmin struct {
 }
 
 type DeleteGroup struct {
-	GroupId uint `json:"group_id"`
+	GroupId uint   `json:"group_id"`
+	Confirm string `json:"confirm" binding:"required"` // 需与群名称一致
 }
 
 type GroupId struct {
@@ -29,16 +30,62 @@ type GroupId struct {
 }
 
 type Member struct {
-	UserID    uint   `json:"user_id,omitempty"`
-	Nickname  string `json:"nickname"`
-	Avatar    string `json:"avatar,omitempty"`
-	Signature string `json:"signature"`
-	Gender    int    `json:"gender,omitempty"`
-	Age       int    `json:"age"`
-	Role      uint   `json:"role"`
+	UserID        uint   `json:"user_id,omitempty"`
+	Nickname      string `json:"nickname"`                 // 展示昵称，设置了群昵称时为群昵称
+	GroupNickname string `json:"group_nickname,omitempty"` // 群昵称
+	Avatar        string `json:"avatar,omitempty"`
+	Signature     string `json:"signature"`
+	Gender        int    `json:"gender,omitempty"`
+	Age           int    `json:"age"`
+	Role          uint   `json:"role"`
 }
 
 type TransferGroupOwnerRequest struct {
 	GroupId uint `json:"group_id"`
 	UserId  uint `json:"user_id"`
 }
+
+type UpdateGroupPermissionRequest struct {
+	GroupId    uint            `json:"group_id" binding:"required"`
+	Permission map[string]uint `json:"permission" binding:"required"` // 操作-最低角色
+}
+
+type UpdateGroupNicknameRequest struct {
+	GroupId  uint   `json:"group_id" binding:"required"`
+	Nickname string `json:"nickname" binding:"max=16"` // 为空时清除群昵称
+}
+
+type UpdateGroupRemarkRequest struct {
+	GroupId uint   `json:"group_id" binding:"required"`
+	Remark  string `json:"remark" binding:"max=16"` // 为空时清除群备注
+}
+
+type UpgradeGroupRequest struct {
+	GroupId uint `json:"group_id" binding:"required"`
+	Type    int  `json:"type" binding:"required"` // 目标群类型
+}
+
+type SearchGroupRequest struct {
+	Page
+	Keyword  string `form:"keyword"`
+	Category string `form:"category"`
+}
+
+type UpdateGroupDiscoveryRequest struct {
+	GroupId    uint     `json:"group_id" binding:"required"`
+	IsPublic   bool     `json:"is_public"`
+	Tags       []string `json:"tags" binding:"max=5,dive,required,max=16,excludesall=0x2C"` // 最多 5 个标签，不能包含逗号
+	Category   string   `json:"category" binding:"max=16"`
+	JoinPolicy int      `json:"join_policy" binding:"min=0,max=2"` // 0-直接加入，1-需审批，2-仅邀请
+}
+
+type JoinGroupRequest struct {
+	GroupId uint   `json:"group_id" binding:"required"`
+	Message string `json:"message" binding:"max=128"`
+}
+
+type DisposeGroupJoinRequest struct {
+	GroupId uint `json:"group_id" binding:"required"`
+	UserId  uint `json:"user_id" binding:"required"`
+	Status  int  `json:"status" binding:"required,oneof=1 2"` // 1-同意，2-拒绝
+}
@@ -0,0 +1,7 @@
+package param
+
+type SyncGroupMessage struct {
+	GroupId uint   `form:"group_id" binding:"required"`
+	SeqId   string `form:"seq_id"` // 已同步的最后一条消息，为空时从头拉取
+	Limit   int    `form:"limit"`  // 默认 100，最大 500
+}
@@ -0,0 +1,35 @@
+package param
+
+type CreateReportRequest struct {
+	TargetType  string           `json:"target_type" binding:"required,oneof=user group private_message group_message"`
+	TargetId    string           `json:"target_id" binding:"required,max=64"` // 用户或群为 id，消息为 seq_id
+	Reason      string           `json:"reason" binding:"required,oneof=spam harassment porn fraud illegal other"`
+	Description string           `json:"description" binding:"max=500"`
+	Messages    []*ReportMessage `json:"messages" binding:"max=50,dive"` // 私聊消息的上下文，服务端不保存私聊消息，由客户端提交
+}
+
+type ReportMessage struct {
+	SeqId      string `json:"seq_id" binding:"required"`
+	SenderId   uint   `json:"sender_id" binding:"required"`
+	ReceiverId uint   `json:"receiver_id" binding:"required"`
+	Content    string `json:"content"`
+	Type       int    `json:"type"`
+	SendTime   int64  `json:"send_time"`
+}
+
+type ReportListRequest struct {
+	Page
+	Status     int    `form:"status" binding:"min=0,max=2"` // 0-待处理，1-已处理，2-已驳回
+	TargetType string `form:"target_type" binding:"omitempty,oneof=user group private_message group_message"`
+}
+
+type ReportInfoRequest struct {
+	Id uint `form:"id" binding:"required"`
+}
+
+type HandleReportRequest struct {
+	Id              uint   `json:"id" binding:"required"`
+	Action          string `json:"action" binding:"required,oneof=warn mute ban dissolve_group delete_message dismiss"`
+	DurationMinutes int    `json:"duration_minutes" binding:"min=0"` // 禁言、封禁时长，0 为永久
+	Note            string `json:"note" binding:"max=255"`
+}
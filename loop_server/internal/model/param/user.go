@@ -4,11 +4,29 @@ type RegisterRequest struct {
 	Nickname string `json:"nickname" binding:"required"`
 	Password string `json:"password" binding:"required"`
 	Phone    string `json:"phone" binding:"required"`
+	Code     string `json:"code" binding:"required"` // 手机验证码
+}
+
+type SendVerifyCodeRequest struct {
+	Phone string `json:"phone" binding:"required,len=11,numeric"`
+	Scene string `json:"scene" binding:"required,oneof=register login reset_password"` // 场景:register-注册，login-登录，reset_password-重置密码
+}
+
+type LoginByCodeRequest struct {
+	Phone string `json:"phone" binding:"required"`
+	Code  string `json:"code" binding:"required"`
+}
+
+type ResetPasswordRequest struct {
+	Phone    string `json:"phone" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+	Password string `json:"password" binding:"required"`
 }
 
 type LoginRequest struct {
-	Password string `json:"password"`
-	Phone    string `json:"phone"`
+	Password     string `json:"password"`
+	Phone        string `json:"phone"`
+	CaptchaToken string `json:"captcha_token"`
 }
 
 type LoginResponse struct {
@@ -34,3 +52,40 @@ type UpdateUserPasswordRequest struct {
 	OldPassword string `json:"old_password" binding:"required"`
 	NewPassword string `json:"new_password" binding:"required"`
 }
+
+type UpdateUserPrivacyRequest struct {
+	SearchByPhone      *bool `json:"search_by_phone" binding:"required"`
+	FriendRequestScope int   `json:"friend_request_scope" binding:"min=0,max=2"`
+	StrangerMessage    *bool `json:"stranger_message" binding:"required"`
+	ProfileScope       int   `json:"profile_scope" binding:"min=0,max=2"`
+	CallScope          int   `json:"call_scope" binding:"min=0,max=2"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type CompleteLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // 动态码或恢复码
+	CaptchaToken   string `json:"captcha_token"`
+}
+
+type ConfirmTotpRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableTotpRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"` // 动态码或恢复码
+}
+
+type RevokeSessionRequest struct {
+	SessionId string `json:"session_id" binding:"required"`
+}
+
+type AuditLogRequest struct {
+	Page
+	StartTime int64 `form:"start_time"` // 秒级时间戳，为 0 时不限制
+	EndTime   int64 `form:"end_time"`
+}
@@ -0,0 +1,76 @@
+package po
+
+import (
+	"loop_server/internal/model/dto"
+	"time"
+)
+
+// AuditLog 审计日志，只追加不修改
+type AuditLog struct {
+	ID         uint      `gorm:"primarykey"`
+	CreatedAt  time.Time `gorm:"index"`
+	ActorType  string    `gorm:"comment:操作者类型:operator-操作员，user-用户;type:varchar(16);not null;index:idx_actor"`
+	ActorId    uint      `gorm:"comment:操作者id;type:bigint;not null;index:idx_actor"`
+	Action     string    `gorm:"comment:操作;type:varchar(64);not null"`
+	TargetType string    `gorm:"comment:操作对象类型;type:varchar(32);not null;index:idx_target"`
+	TargetId   string    `gorm:"comment:操作对象标识;type:varchar(64);not null;index:idx_target"`
+	Ip         string    `gorm:"comment:操作者ip;type:varchar(64);not null"`
+	UserAgent  string    `gorm:"comment:操作者User-Agent;type:varchar(255);not null"`
+	Detail     string    `gorm:"comment:操作参数json;type:text;not null"`
+	Before     string    `gorm:"comment:变更前的状态json;type:text;not null"`
+	After      string    `gorm:"comment:变更后的状态json;type:text;not null"`
+}
+
+func (*AuditLog) TableName() string {
+	return "audit_log"
+}
+
+func (a *AuditLog) ConvertToDto() *dto.AuditLog {
+	return &dto.AuditLog{
+		ID:         a.ID,
+		CreatedAt:  a.CreatedAt,
+		ActorType:  a.ActorType,
+		ActorId:    a.ActorId,
+		Action:     a.Action,
+		TargetType: a.TargetType,
+		TargetId:   a.TargetId,
+		Ip:         a.Ip,
+		UserAgent:  a.UserAgent,
+		Detail:     a.Detail,
+		Before:     a.Before,
+		After:      a.After,
+	}
+}
+
+func BatchConvertAuditLogPoToDto(data []*AuditLog) []*dto.AuditLog {
+	list := make([]*dto.AuditLog, len(data))
+	for i, datum := range data {
+		list[i] = datum.ConvertToDto()
+	}
+	return list
+}
+
+func BatchConvertAuditLogDtoToPo(data []*dto.AuditLog) []*AuditLog {
+	list := make([]*AuditLog, len(data))
+	for i, datum := range data {
+		list[i] = ConvertAuditLogDtoToPo(datum)
+	}
+	return list
+}
+
+func ConvertAuditLogDtoToPo(log *dto.AuditLog) *AuditLog {
+	return &AuditLog{
+		ID:         log.ID,
+		CreatedAt:  log.CreatedAt,
+		ActorType:  log.ActorType,
+		ActorId:    log.ActorId,
+		Action:     log.Action,
+		TargetType: log.TargetType,
+		TargetId:   log.TargetId,
+		Ip:         log.Ip,
+		UserAgent:  log.UserAgent,
+		Detail:     log.Detail,
+		Before:     log.Before,
+		After:      log.After,
+	}
+}
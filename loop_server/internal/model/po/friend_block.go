@@ -0,0 +1,15 @@
+package po
+
+import (
+	"gorm.io/gorm"
+)
+
+type FriendBlock struct {
+	gorm.Model
+	UserId    uint `gorm:"comment:用户id;type:bigint;not null;uniqueIndex:idx_user_id_blocked_id"`
+	BlockedId uint `gorm:"comment:被拉黑用户id;type:bigint;not null;uniqueIndex:idx_user_id_blocked_id;index"`
+}
+
+func (*FriendBlock) TableName() string {
+	return "friend_block"
+}
@@ -0,0 +1,33 @@
+package po
+
+import (
+	"gorm.io/gorm"
+	"loop_server/internal/model/dto"
+)
+
+type FriendCategory struct {
+	gorm.Model
+	UserId uint   `gorm:"comment:用户id;type:bigint;not null;index"`
+	Name   string `gorm:"comment:分组名称;type:varchar(16);not null"`
+	Sort   int    `gorm:"comment:排序，越小越靠前;not null;default:0"`
+}
+
+func (*FriendCategory) TableName() string {
+	return "friend_category"
+}
+
+func (f *FriendCategory) ConvertToDto() *dto.FriendCategory {
+	return &dto.FriendCategory{
+		ID:   f.ID,
+		Name: f.Name,
+		Sort: f.Sort,
+	}
+}
+
+func BatchConvertFriendCategoryPoToDto(data []*FriendCategory) []*dto.FriendCategory {
+	list := make([]*dto.FriendCategory, len(data))
+	for i, datum := range data {
+		list[i] = datum.ConvertToDto()
+	}
+	return list
+}
@@ -8,9 +8,9 @@ import (
 
 type FriendRequest struct {
 	gorm.Model
-	RequesterId uint   `gorm:"comment:请求者ID;not null;uniqueIndex:idx_requester_id_recipient_id"`
-	RecipientId uint   `gorm:"comment:接收者ID;not null;uniqueIndex:idx_requester_id_recipient_id"`
-	Status      int    `gorm:"comment:状态:0-未处理，1-已同意，2-已拒绝;type:tinyint;not null"`
+	RequesterId uint   `gorm:"comment:请求者ID;not null;index:idx_requester_recipient"`
+	RecipientId uint   `gorm:"comment:接收者ID;not null;index:idx_requester_recipient;index"`
+	Status      int    `gorm:"comment:状态:0-未处理，1-已同意，2-已拒绝，3-已撤回，4-已过期;type:tinyint;not null"`
 	Message     string `gorm:"comment:验证消息;type:varchar(128);not null"`
 }
 
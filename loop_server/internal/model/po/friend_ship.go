@@ -7,8 +7,11 @@ import (
 
 type FriendShip struct {
 	gorm.Model
-	UserId   uint `gorm:"comment:用户id;type:bigint;not null;uniqueIndex:idx_user_id_friend_id"`
-	FriendId uint `gorm:"comment:好友id;type:bigint;not null;uniqueIndex:idx_user_id_friend_id"`
+	UserId     uint   `gorm:"comment:用户id;type:bigint;not null;uniqueIndex:idx_user_id_friend_id"`
+	FriendId   uint   `gorm:"comment:好友id;type:bigint;not null;uniqueIndex:idx_user_id_friend_id"`
+	Remark     string `gorm:"comment:好友备注;type:varchar(32);not null;default:''"`
+	Tags       string `gorm:"comment:标签，逗号分隔，最多 10 个 16 字的标签;type:varchar(255);not null;default:''"`
+	CategoryId uint   `gorm:"comment:好友分组id，0-默认分组;type:bigint;not null;default:0"`
 }
 
 func (f *FriendShip) TableName() string {
@@ -25,10 +28,13 @@ func ConvertFriendShipDtoToPo(f *dto.FriendShip) *FriendShip {
 
 func (f *FriendShip) ConvertToDto() *dto.FriendShip {
 	return &dto.FriendShip{
-		UserId:    f.UserId,
-		FriendId:  f.FriendId,
-		ID:        f.ID,
-		CreatedAt: f.CreatedAt,
-		UpdatedAt: f.UpdatedAt,
+		UserId:     f.UserId,
+		FriendId:   f.FriendId,
+		Remark:     f.Remark,
+		Tags:       SplitTags(f.Tags),
+		CategoryId: f.CategoryId,
+		ID:         f.ID,
+		CreatedAt:  f.CreatedAt,
+		UpdatedAt:  f.UpdatedAt,
 	}
 }
@@ -2,16 +2,24 @@ package po
 
 import (
 	"gorm.io/gorm"
+	"loop_server/infra/consts"
 	"loop_server/internal/model/dto"
+	"strings"
 	"time"
 )
 
 type Group struct {
 	gorm.Model
-	Name     string `gorm:"comment:群名称;type:varchar(16);not null"`  // 群名称
-	Avatar   string `gorm:"comment:群头像;type:varchar(256);not null"` // 群头像
-	Describe string `gorm:"comment:群简介;type:varchar(128);not null"` // 群简介
-	OwnerId  uint   `gorm:"comment:群主id;type:bigint;not null"`      // 群主id
+	Name       string `gorm:"comment:群名称;type:varchar(16);not null"`                           // 群名称
+	Avatar     string `gorm:"comment:群头像;type:varchar(256);not null"`                          // 群头像
+	Describe   string `gorm:"comment:群简介;type:varchar(128);not null"`                          // 群简介
+	OwnerId    uint   `gorm:"comment:群主id;type:bigint;not null"`                               // 群主id
+	Type       int    `gorm:"comment:群类型:0-普通群，1-大群;type:tinyint;not null;default:0"`          // 群类型
+	IsPublic   bool   `gorm:"comment:是否公开可搜索;not null;default:false;index"`                    // 是否公开
+	Tags       string `gorm:"comment:标签，逗号分隔;type:varchar(128);not null;default:''"`           // 标签
+	Category   string `gorm:"comment:分类;type:varchar(16);not null;default:'';index"`           // 分类
+	Archived   bool   `gorm:"comment:解散后消息是否归档只读;not null;default:false"`                      // 是否归档
+	JoinPolicy int    `gorm:"comment:加群方式:0-直接加入，1-需审批，2-仅邀请;type:tinyint;not null;default:0"` // 加群方式
 }
 
 func (*Group) TableName() string {
@@ -29,13 +37,19 @@ func (g *Group) ConvertDto() *dto.Group {
 	}
 
 	return &dto.Group{
-		ID:        g.ID,
-		Name:      g.Name,
-		Avatar:    g.Avatar,
-		Describe:  g.Describe,
-		OwnerId:   g.OwnerId,
-		CreatedAt: created,
-		UpdatedAt: updated,
+		ID:         g.ID,
+		Name:       g.Name,
+		Avatar:     g.Avatar,
+		Describe:   g.Describe,
+		OwnerId:    g.OwnerId,
+		Type:       g.Type,
+		MaxMember:  consts.GroupTypeMaxMember[g.Type],
+		IsPublic:   g.IsPublic,
+		Tags:       SplitTags(g.Tags),
+		Category:   g.Category,
+		JoinPolicy: g.JoinPolicy,
+		CreatedAt:  created,
+		UpdatedAt:  updated,
 	}
 }
 
@@ -59,10 +73,22 @@ func ConvertGroupDtoToPo(d *dto.Group) *Group {
 		updated = *d.UpdatedAt
 	}
 	return &Group{
-		Model:    gorm.Model{ID: d.ID, CreatedAt: created, UpdatedAt: updated},
-		Name:     d.Name,
-		Avatar:   d.Avatar,
-		Describe: d.Describe,
-		OwnerId:  d.OwnerId,
+		Model:      gorm.Model{ID: d.ID, CreatedAt: created, UpdatedAt: updated},
+		Name:       d.Name,
+		Avatar:     d.Avatar,
+		Describe:   d.Describe,
+		OwnerId:    d.OwnerId,
+		Type:       d.Type,
+		IsPublic:   d.IsPublic,
+		Tags:       strings.Join(d.Tags, ","),
+		Category:   d.Category,
+		JoinPolicy: d.JoinPolicy,
 	}
 }
+
+func SplitTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+	return strings.Split(tags, ",")
+}
@@ -0,0 +1,40 @@
+package po
+
+import (
+	"gorm.io/gorm"
+	"loop_server/internal/model/dto"
+)
+
+type GroupJoinRequest struct {
+	gorm.Model
+	GroupId uint   `gorm:"comment:群id;type:bigint;not null;uniqueIndex:idx_group_id_user_id"`
+	UserId  uint   `gorm:"comment:申请者id;type:bigint;not null;uniqueIndex:idx_group_id_user_id"`
+	Status  int    `gorm:"comment:状态:0-未处理，1-已同意，2-已拒绝;type:tinyint;not null"`
+	Message string `gorm:"comment:验证消息;type:varchar(128);not null"`
+}
+
+func (*GroupJoinRequest) TableName() string {
+	return "group_join_request"
+}
+
+func (g *GroupJoinRequest) ConvertToDto() *dto.GroupJoinRequest {
+	return &dto.GroupJoinRequest{
+		ID:        g.ID,
+		CreatedAt: &g.CreatedAt,
+		UpdatedAt: &g.UpdatedAt,
+		GroupId:   g.GroupId,
+		UserId:    g.UserId,
+		Status:    g.Status,
+		Message:   g.Message,
+	}
+}
+
+func ConvertGroupJoinRequestDtoToPo(d *dto.GroupJoinRequest) *GroupJoinRequest {
+	return &GroupJoinRequest{
+		Model:   gorm.Model{ID: d.ID},
+		GroupId: d.GroupId,
+		UserId:  d.UserId,
+		Status:  d.Status,
+		Message: d.Message,
+	}
+}
@@ -0,0 +1,33 @@
+package po
+
+import (
+	"gorm.io/gorm"
+	"loop_server/internal/model/dto"
+)
+
+type GroupPermission struct {
+	gorm.Model
+	GroupId uint   `gorm:"type:bigint;not null;comment:群组id;uniqueIndex:idx_group_id_action"`
+	Action  string `gorm:"type:varchar(32);not null;comment:群操作;uniqueIndex:idx_group_id_action"`
+	MinRole uint   `gorm:"type:tinyint;not null;comment:最低角色:1-普通成员，2-管理员，3-群主"`
+}
+
+func (*GroupPermission) TableName() string {
+	return "group_permission"
+}
+
+func (g *GroupPermission) ConvertToDto() *dto.GroupPermission {
+	return &dto.GroupPermission{
+		GroupId: g.GroupId,
+		Action:  g.Action,
+		MinRole: g.MinRole,
+	}
+}
+
+func ConvertGroupPermissionDtoToPo(d *dto.GroupPermission) *GroupPermission {
+	return &GroupPermission{
+		GroupId: d.GroupId,
+		Action:  d.Action,
+		MinRole: d.MinRole,
+	}
+}
@@ -8,10 +8,10 @@ import (
 type GroupShip struct {
 	gorm.Model
 	GroupId      uint   `gorm:"type:bigint;not null;comment:群组id;uniqueIndex:idx_group_id_user_id"`
-	UserId       uint   `gorm:"type:bigint;not null;comment:用户id;uniqueIndex:idx_group_id_user_id"`
+	UserId       uint   `gorm:"type:bigint;not null;comment:用户id;uniqueIndex:idx_group_id_user_id;index"`
 	Role         uint   `gorm:"type:tinyint;not null;comment:1-普通成员，2-管理员，3-群主"`
-	Remark       string `gorm:"type:varchar(16);not null;comment:备注"`
-	GroupRemark  string `gorm:"type:varchar(16);not null;comment:群备注"`
+	Remark       string `gorm:"type:varchar(16);not null;comment:群昵称，群内成员可见"`
+	GroupRemark  string `gorm:"type:varchar(16);not null;comment:群备注，仅自己可见"`
 	LastAckSeqId string `gorm:"type:varchar(64);not null;comment:最后确认的消息id"`
 }
 
@@ -36,12 +36,13 @@ func ConvertGroupShipDtoToPo(dto *dto.GroupShip) *GroupShip {
 
 func (g *GroupShip) ConvertToDto() *dto.GroupShip {
 	return &dto.GroupShip{
-		ID:        g.ID,
-		CreatedAt: g.CreatedAt,
-		UpdatedAt: g.UpdatedAt,
-		GroupId:   g.GroupId,
-		UserId:    g.UserId,
-		Role:      g.Role,
-		Remark:    g.Remark,
+		ID:          g.ID,
+		CreatedAt:   g.CreatedAt,
+		UpdatedAt:   g.UpdatedAt,
+		GroupId:     g.GroupId,
+		UserId:      g.UserId,
+		Role:        g.Role,
+		Remark:      g.Remark,
+		GroupRemark: g.GroupRemark,
 	}
 }
@@ -0,0 +1,60 @@
+package po
+
+import (
+	"gorm.io/gorm"
+	"loop_server/internal/model/dto"
+	"time"
+)
+
+type ModerationReview struct {
+	gorm.Model
+	Scene      string     `gorm:"comment:场景:private_message、group_message、nickname、signature、group_name、group_describe;type:varchar(32);not null;index"`
+	UserId     uint       `gorm:"comment:发布内容的用户id;type:bigint;not null;index"`
+	TargetId   string     `gorm:"comment:内容标识，消息为seq_id，资料为用户或群id;type:varchar(64);not null"`
+	Content    string     `gorm:"comment:原始内容;type:text;not null"`
+	Source     string     `gorm:"comment:来源:word-敏感词，classifier-分类器;type:varchar(16);not null"`
+	Reason     string     `gorm:"comment:命中的敏感词或分类标签;type:varchar(255);not null"`
+	Status     int        `gorm:"comment:状态:0-待审核，1-通过，2-确认违规;type:tinyint;not null;index"`
+	ReviewedAt *time.Time `gorm:"comment:审核时间"`
+}
+
+func (*ModerationReview) TableName() string {
+	return "moderation_review"
+}
+
+func (m *ModerationReview) ConvertToDto() *dto.ModerationReview {
+	return &dto.ModerationReview{
+		ID:         m.ID,
+		CreatedAt:  m.CreatedAt,
+		Scene:      m.Scene,
+		UserId:     m.UserId,
+		TargetId:   m.TargetId,
+		Content:    m.Content,
+		Source:     m.Source,
+		Reason:     m.Reason,
+		Status:     m.Status,
+		ReviewedAt: m.ReviewedAt,
+	}
+}
+
+func BatchConvertModerationReviewPoToDto(data []*ModerationReview) []*dto.ModerationReview {
+	list := make([]*dto.ModerationReview, len(data))
+	for i, datum := range data {
+		list[i] = datum.ConvertToDto()
+	}
+	return list
+}
+
+func ConvertModerationReviewDtoToPo(review *dto.ModerationReview) *ModerationReview {
+	return &ModerationReview{
+		Model:      gorm.Model{ID: review.ID, CreatedAt: review.CreatedAt},
+		Scene:      review.Scene,
+		UserId:     review.UserId,
+		TargetId:   review.TargetId,
+		Content:    review.Content,
+		Source:     review.Source,
+		Reason:     review.Reason,
+		Status:     review.Status,
+		ReviewedAt: review.ReviewedAt,
+	}
+}
@@ -0,0 +1,55 @@
+package po
+
+import (
+	"gorm.io/gorm"
+	"loop_server/internal/model/dto"
+	"time"
+)
+
+// Operator 管理后台的操作员账号，与用户账号相互独立
+type Operator struct {
+	gorm.Model
+	Username    string     `gorm:"comment:登录名;type:varchar(32);not null;unique"`
+	Password    string     `gorm:"comment:密码;type:varchar(255);not null"`
+	Nickname    string     `gorm:"comment:昵称;type:varchar(32);not null"`
+	Role        string     `gorm:"comment:角色:viewer-只读，moderator-审核，superadmin-超级管理员;type:varchar(16);not null"`
+	Disabled    bool       `gorm:"comment:是否停用;not null;default:false"`
+	LastLoginAt *time.Time `gorm:"comment:最近登录时间"`
+}
+
+func (*Operator) TableName() string {
+	return "operator"
+}
+
+func (o *Operator) ConvertToDto() *dto.Operator {
+	return &dto.Operator{
+		ID:          o.ID,
+		CreatedAt:   o.CreatedAt,
+		Username:    o.Username,
+		Password:    o.Password,
+		Nickname:    o.Nickname,
+		Role:        o.Role,
+		Disabled:    o.Disabled,
+		LastLoginAt: o.LastLoginAt,
+	}
+}
+
+func BatchConvertOperatorPoToDto(data []*Operator) []*dto.Operator {
+	list := make([]*dto.Operator, len(data))
+	for i, datum := range data {
+		list[i] = datum.ConvertToDto()
+	}
+	return list
+}
+
+func ConvertOperatorDtoToPo(operator *dto.Operator) *Operator {
+	return &Operator{
+		Model:       gorm.Model{ID: operator.ID, CreatedAt: operator.CreatedAt},
+		Username:    operator.Username,
+		Password:    operator.Password,
+		Nickname:    operator.Nickname,
+		Role:        operator.Role,
+		Disabled:    operator.Disabled,
+		LastLoginAt: operator.LastLoginAt,
+	}
+}
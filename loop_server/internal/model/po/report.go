@@ -0,0 +1,125 @@
+package po
+
+import (
+	"gorm.io/gorm"
+	"loop_server/internal/model/dto"
+	"time"
+)
+
+type Report struct {
+	gorm.Model
+	ReporterId     uint       `gorm:"comment:举报人id;type:bigint;not null;index"`
+	TargetType     string     `gorm:"comment:举报对象类型:user、group、private_message、group_message;type:varchar(32);not null;index:idx_target"`
+	TargetId       string     `gorm:"comment:举报对象标识，用户或群为id，消息为seq_id;type:varchar(64);not null;index:idx_target"`
+	TargetUserId   uint       `gorm:"comment:被举报的责任用户，群举报为群主;type:bigint;not null;index"`
+	GroupId        uint       `gorm:"comment:相关群id;type:bigint;not null;default:0"`
+	Reason         string     `gorm:"comment:举报原因;type:varchar(32);not null"`
+	Description    string     `gorm:"comment:补充说明;type:varchar(512);not null"`
+	Snapshot       string     `gorm:"comment:举报对象及上下文快照json;type:mediumtext;not null"`
+	SnapshotSource string     `gorm:"comment:快照来源:server、client;type:varchar(16);not null"`
+	Status         int        `gorm:"comment:状态:0-待处理，1-已处理，2-已驳回;type:tinyint;not null;index"`
+	HandledAt      *time.Time `gorm:"comment:处理时间"`
+}
+
+func (*Report) TableName() string {
+	return "report"
+}
+
+func (r *Report) ConvertToDto() *dto.Report {
+	return &dto.Report{
+		ID:             r.ID,
+		CreatedAt:      r.CreatedAt,
+		ReporterId:     r.ReporterId,
+		TargetType:     r.TargetType,
+		TargetId:       r.TargetId,
+		TargetUserId:   r.TargetUserId,
+		GroupId:        r.GroupId,
+		Reason:         r.Reason,
+		Description:    r.Description,
+		Snapshot:       r.Snapshot,
+		SnapshotSource: r.SnapshotSource,
+		Status:         r.Status,
+		HandledAt:      r.HandledAt,
+	}
+}
+
+func BatchConvertReportPoToDto(data []*Report) []*dto.Report {
+	list := make([]*dto.Report, len(data))
+	for i, datum := range data {
+		list[i] = datum.ConvertToDto()
+	}
+	return list
+}
+
+func ConvertReportDtoToPo(report *dto.Report) *Report {
+	return &Report{
+		Model:          gorm.Model{ID: report.ID, CreatedAt: report.CreatedAt},
+		ReporterId:     report.ReporterId,
+		TargetType:     report.TargetType,
+		TargetId:       report.TargetId,
+		TargetUserId:   report.TargetUserId,
+		GroupId:        report.GroupId,
+		Reason:         report.Reason,
+		Description:    report.Description,
+		Snapshot:       report.Snapshot,
+		SnapshotSource: report.SnapshotSource,
+		Status:         report.Status,
+		HandledAt:      report.HandledAt,
+	}
+}
+
+// ReportAction 举报处理记录，只追加不修改
+type ReportAction struct {
+	ID           uint       `gorm:"primarykey"`
+	CreatedAt    time.Time  `gorm:"index"`
+	ReportId     uint       `gorm:"comment:举报id;type:bigint;not null;index"`
+	Action       string     `gorm:"comment:处理动作:warn、mute、ban、dissolve_group、delete_message、dismiss;type:varchar(32);not null"`
+	OperatorId   uint       `gorm:"comment:操作人id;type:bigint;not null"`
+	TargetUserId uint       `gorm:"comment:被处理的用户id;type:bigint;not null;default:0"`
+	GroupId      uint       `gorm:"comment:被处理的群id;type:bigint;not null;default:0"`
+	TargetId     string     `gorm:"comment:被处理的对象标识;type:varchar(64);not null;default:''"`
+	Until        *time.Time `gorm:"comment:禁言或封禁截止时间"`
+	Note         string     `gorm:"comment:处理备注;type:varchar(512);not null"`
+}
+
+func (*ReportAction) TableName() string {
+	return "report_action"
+}
+
+func (r *ReportAction) ConvertToDto() *dto.ReportAction {
+	return &dto.ReportAction{
+		ID:           r.ID,
+		CreatedAt:    r.CreatedAt,
+		ReportId:     r.ReportId,
+		Action:       r.Action,
+		OperatorId:   r.OperatorId,
+		TargetUserId: r.TargetUserId,
+		GroupId:      r.GroupId,
+		TargetId:     r.TargetId,
+		Until:        r.Until,
+		Note:         r.Note,
+	}
+}
+
+func BatchConvertReportActionPoToDto(data []*ReportAction) []*dto.ReportAction {
+	list := make([]*dto.ReportAction, len(data))
+	for i, datum := range data {
+		list[i] = datum.ConvertToDto()
+	}
+	return list
+}
+
+func ConvertReportActionDtoToPo(action *dto.ReportAction) *ReportAction {
+	return &ReportAction{
+		ID:           action.ID,
+		CreatedAt:    action.CreatedAt,
+		ReportId:     action.ReportId,
+		Action:       action.Action,
+		OperatorId:   action.OperatorId,
+		TargetUserId: action.TargetUserId,
+		GroupId:      action.GroupId,
+		TargetId:     action.TargetId,
+		Until:        action.Until,
+		Note:         action.Note,
+	}
+}
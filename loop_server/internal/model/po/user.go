@@ -17,6 +17,20 @@ type User struct {
 	Signature string `gorm:"type:varchar(256);comment:个性签名;not null"`
 	Gender    int    `gorm:"type:tinyint;comment:性别：0-未知，1-男，2-女;not null"`
 	Age       int    `gorm:"type:tinyint unsigned;comment:年龄;not null"`
+
+	SearchByPhone      bool `gorm:"comment:是否允许通过手机号搜索;not null;default:true"`
+	FriendRequestScope int  `gorm:"type:tinyint;comment:谁可以加我为好友:0-所有人，1-共同好友或同群成员，2-任何人都不可以;not null;default:0"`
+	StrangerMessage    bool `gorm:"comment:是否允许陌生人私聊;not null;default:true"`
+	ProfileScope       int  `gorm:"type:tinyint;comment:谁可以看我的签名、年龄、性别:0-所有人，1-仅好友，2-仅自己;not null;default:0"`
+	CallScope          int  `gorm:"type:tinyint;comment:谁可以给我打电话:0-所有人，1-仅好友，2-任何人都不可以;not null;default:0"`
+
+	DeleteScheduledAt *time.Time `gorm:"comment:计划注销时间，为空表示未申请注销;index"`
+
+	TotpSecret  string `gorm:"type:varchar(64);comment:两步验证密钥;not null;default:''"`
+	TotpEnabled bool   `gorm:"comment:是否开启两步验证;not null;default:false"`
+
+	MutedUntil  *time.Time `gorm:"comment:禁言截止时间，为空表示未禁言"`
+	BannedUntil *time.Time `gorm:"comment:封禁截止时间，为空表示未封禁"`
 }
 
 func (*User) TableName() string {
@@ -43,16 +57,28 @@ func (u *User) ConvertToDto() *dto.User {
 		updated = &u.UpdatedAt
 	}
 	return &dto.User{
-		ID:        u.ID,
-		CreatedAt: created,
-		UpdatedAt: updated,
-		Nickname:  u.Nickname,
-		Password:  u.Password,
-		Phone:     u.Phone,
-		Avatar:    u.Avatar,
-		Signature: u.Signature,
-		Gender:    u.Gender,
-		Age:       u.Age,
+		ID:                u.ID,
+		CreatedAt:         created,
+		UpdatedAt:         updated,
+		Nickname:          u.Nickname,
+		Password:          u.Password,
+		Phone:             u.Phone,
+		Avatar:            u.Avatar,
+		Signature:         u.Signature,
+		Gender:            u.Gender,
+		Age:               u.Age,
+		DeleteScheduledAt: u.DeleteScheduledAt,
+		TotpSecret:        u.TotpSecret,
+		TotpEnabled:       u.TotpEnabled,
+		MutedUntil:        u.MutedUntil,
+		BannedUntil:       u.BannedUntil,
+		Privacy: &dto.UserPrivacy{
+			SearchByPhone:      u.SearchByPhone,
+			FriendRequestScope: u.FriendRequestScope,
+			StrangerMessage:    u.StrangerMessage,
+			ProfileScope:       u.ProfileScope,
+			CallScope:          u.CallScope,
+		},
 	}
 }
 
@@ -0,0 +1,17 @@
+package po
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type UserRecoveryCode struct {
+	gorm.Model
+	UserId   uint       `gorm:"comment:用户id;type:bigint;not null;uniqueIndex:idx_user_id_code_hash"`
+	CodeHash string     `gorm:"comment:恢复码hmac-sha256，以用户的两步验证密钥为key;type:varchar(64);not null;uniqueIndex:idx_user_id_code_hash"`
+	UsedAt   *time.Time `gorm:"comment:使用时间，为空表示未使用"`
+}
+
+func (*UserRecoveryCode) TableName() string {
+	return "user_recovery_code"
+}
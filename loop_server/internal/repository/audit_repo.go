@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"loop_server/internal/model/dto"
+)
+
+type AuditRepo interface {
+	CreateAuditLogs(ctx context.Context, logs []*dto.AuditLog) error
+	GetAuditLogList(ctx context.Context, query *dto.AuditLogQuery, offset, limit int) ([]*dto.AuditLog, int64, error)
+}
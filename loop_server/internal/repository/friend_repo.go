@@ -3,10 +3,12 @@ package repository
 import (
 	"context"
 	"loop_server/internal/model/dto"
+	"time"
 )
 
 type FriendRepo interface {
-	SaveOrUpdateFriendRequest(ctx context.Context, req *dto.FriendRequest) error
+	CreateFriendRequest(ctx context.Context, req *dto.FriendRequest, cooldown time.Duration, dailyLimit int, since time.Time) error
+	ExpireFriendRequest(ctx context.Context, userId uint, deadline time.Time) error
 	UpdateFriendRequest(ctx context.Context, req *dto.FriendRequest) error
 	QueryFriendShip(ctx context.Context, userId, friendId uint) (*dto.FriendShip, error)
 	GetFriendRequestListByRequesterIdOrRecipientId(ctx context.Context, id uint) ([]*dto.FriendRequest, error)
@@ -14,4 +16,22 @@ type FriendRepo interface {
 	IsFriend(ctx context.Context, userId uint, friendId uint) (bool, error)
 	DeleteFriend(ctx context.Context, userId uint, friendId uint) error
 	FriendRequestStatistics(ctx context.Context, userId uint) (*dto.FriendListStatistics, error)
+	BlockUser(ctx context.Context, userId, blockedId uint) error
+	UnblockUser(ctx context.Context, userId, blockedId uint) error
+	GetBlockList(ctx context.Context, userId uint) ([]*dto.User, error)
+	GetBlockerIds(ctx context.Context, userIds []uint, blockedId uint) ([]uint, error)
+	GetFriendShipList(ctx context.Context, userId uint) ([]*dto.FriendShip, error)
+	UpdateFriendRemark(ctx context.Context, userId, friendId uint, remark string) error
+	UpdateFriendTags(ctx context.Context, userId, friendId uint, tags []string) error
+	MoveFriendCategory(ctx context.Context, userId uint, friendIds []uint, categoryId uint) error
+	CountFriendCategory(ctx context.Context, userId uint) (int64, error)
+	GetFriendCategory(ctx context.Context, userId, categoryId uint) (*dto.FriendCategory, error)
+	GetFriendCategoryList(ctx context.Context, userId uint) ([]*dto.FriendCategory, error)
+	CreateFriendCategory(ctx context.Context, category *dto.FriendCategory) (*dto.FriendCategory, error)
+	UpdateFriendCategory(ctx context.Context, category *dto.FriendCategory) error
+	DeleteFriendCategory(ctx context.Context, userId, categoryId uint) error
+	SortFriendCategory(ctx context.Context, userId uint, categoryIds []uint) error
+	GetFriendRecommend(ctx context.Context, userId uint, limit int) ([]*dto.FriendRecommend, error)
+	HasMutualConnection(ctx context.Context, userId, targetId uint) (bool, error)
+	RemoveUserRelations(ctx context.Context, userId uint) error
 }
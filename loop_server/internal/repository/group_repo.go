@@ -9,7 +9,9 @@ import (
 type GroupRepo interface {
 	CreateGroup(ctx context.Context, dto *dto.Group, userIds []uint) (*dto.Group, *po.GroupMessage, error)
 	UpdateGroup(ctx context.Context, group *dto.Group) (*dto.Group, error)
-	DeleteGroup(ctx context.Context, groupId uint) error
+	DeleteGroup(ctx context.Context, groupId uint, purge bool) error
+	OwnerExitGroup(ctx context.Context, groupId, ownerId uint) (uint, error)
+	GetArchivedGroup(ctx context.Context, groupId, userId uint) (*dto.Group, error)
 	GetGroupList(ctx context.Context, userId uint) ([]*dto.Group, error)
 	GetGroup(ctx context.Context, groupId uint) (*dto.Group, error)
 	AddMember(ctx context.Context, ship []*dto.GroupShip) error
@@ -22,4 +24,17 @@ type GroupRepo interface {
 	GetGroupShip(ctx context.Context, groupId uint) ([]*dto.GroupShip, error)
 	GetGroupShipByLessRole(ctx context.Context, groupId uint, role uint) ([]*dto.GroupShip, error)
 	TransferGroupOwner(ctx context.Context, groupId uint, curOwner, userId uint) error
+	GetGroupShipByUserIds(ctx context.Context, groupId uint, userIds []uint) ([]*dto.GroupShip, error)
+	GetGroupShipListByUserId(ctx context.Context, userId uint) ([]*dto.GroupShip, error)
+	UpdateGroupNickname(ctx context.Context, groupId, userId uint, nickname string) error
+	UpdateGroupRemark(ctx context.Context, groupId, userId uint, remark string) error
+	UpdateGroupType(ctx context.Context, groupId uint, groupType int) error
+	SearchGroup(ctx context.Context, req *dto.GroupSearchRequest) (*dto.GroupSearchResult, error)
+	UpdateGroupDiscovery(ctx context.Context, group *dto.Group) error
+	SaveGroupJoinRequest(ctx context.Context, req *dto.GroupJoinRequest) error
+	GetGroupJoinRequest(ctx context.Context, groupId, userId uint) (*dto.GroupJoinRequest, error)
+	GetGroupJoinRequestList(ctx context.Context, groupId uint) ([]*dto.GroupJoinRequest, error)
+	UpdateGroupJoinRequest(ctx context.Context, req *dto.GroupJoinRequest) error
+	GetGroupPermission(ctx context.Context, groupId uint) ([]*dto.GroupPermission, error)
+	SaveGroupPermission(ctx context.Context, permission []*dto.GroupPermission) error
 }
@@ -10,4 +10,10 @@ type ImRepo interface {
 	GetOfflineGroupMessage(ctx context.Context, groupId uint, userId uint) ([]*po.GroupMessage, error)
 	GetGroupMessageBySeqId(ctx context.Context, seqId string) (*po.GroupMessage, error)
 	UpdateGroupMessageLastSeqId(ctx context.Context, groupId uint, userId uint, seqId string) error
+	AdvanceGroupMessageLastSeqId(ctx context.Context, groupId uint, userId uint, seqId string) error
+	GetGroupMessageAfterSeqId(ctx context.Context, groupId, userId uint, seqId string, limit int) ([]*po.GroupMessage, error)
+	GetGroupMessageBySenderId(ctx context.Context, senderId uint) ([]*po.GroupMessage, error)
+	AnonymizeGroupMessage(ctx context.Context, senderId uint) error
+	GetGroupMessageAround(ctx context.Context, groupId, id uint, limit int) ([]*po.GroupMessage, error)
+	DeleteGroupMessage(ctx context.Context, seqId string) error
 }
@@ -0,0 +1,68 @@
+package impl
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"log/slog"
+	"loop_server/infra/consts"
+	"loop_server/internal/model/dto"
+	"loop_server/internal/model/po"
+)
+
+type auditRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewAuditRepoImpl(db *gorm.DB) *auditRepoImpl {
+	return &auditRepoImpl{db: db}
+}
+
+func (a *auditRepoImpl) CreateAuditLogs(ctx context.Context, logs []*dto.AuditLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	if err := a.db.WithContext(ctx).CreateInBatches(po.BatchConvertAuditLogDtoToPo(logs), consts.AuditBatchSize).Error; err != nil {
+		slog.Error("internal/repository/impl/audit_repo_impl.go CreateAuditLogs error", "err", err)
+		return err
+	}
+	return nil
+}
+
+// GetAuditLogList 按条件分页查询审计日志，按时间倒序
+func (a *auditRepoImpl) GetAuditLogList(ctx context.Context, query *dto.AuditLogQuery, offset, limit int) ([]*dto.AuditLog, int64, error) {
+	var (
+		data  []*po.AuditLog
+		total int64
+	)
+	db := a.db.WithContext(ctx).Model(&po.AuditLog{})
+	if query.ActorType != "" {
+		db = db.Where("actor_type = ?", query.ActorType)
+	}
+	if query.ActorId != 0 {
+		db = db.Where("actor_id = ?", query.ActorId)
+	}
+	if query.TargetType != "" {
+		db = db.Where("target_type = ?", query.TargetType)
+	}
+	if query.TargetId != "" {
+		db = db.Where("target_id = ?", query.TargetId)
+	}
+	if query.UserId != 0 {
+		db = db.Where("actor_type = ? and actor_id = ?", consts.AuditActorUser, query.UserId)
+	}
+	if !query.StartTime.IsZero() {
+		db = db.Where("created_at >= ?", query.StartTime)
+	}
+	if !query.EndTime.IsZero() {
+		db = db.Where("created_at < ?", query.EndTime)
+	}
+	if err := db.Count(&total).Error; err != nil {
+		slog.Error("internal/repository/impl/audit_repo_impl.go GetAuditLogList count error", "err", err)
+		return nil, 0, err
+	}
+	if err := db.Order("id desc").Offset(offset).Limit(limit).Find(&data).Error; err != nil {
+		slog.Error("internal/repository/impl/audit_repo_impl.go GetAuditLogList error", "err", err)
+		return nil, 0, err
+	}
+	return po.BatchConvertAuditLogPoToDto(data), total, nil
+}
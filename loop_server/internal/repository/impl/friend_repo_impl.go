@@ -2,12 +2,15 @@ package impl
 
 import (
 	"context"
+	"database/sql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"log/slog"
 	"loop_server/infra/consts"
 	"loop_server/internal/model/dto"
 	"loop_server/internal/model/po"
+	"strings"
+	"time"
 )
 
 type friendRepoImpl struct {
@@ -18,31 +21,86 @@ func NewFriendRepoImpl(db *gorm.DB) *friendRepoImpl {
 	return &friendRepoImpl{db: db}
 }
 
-func (u *friendRepoImpl) SaveOrUpdateFriendRequest(ctx context.Context, req *dto.FriendRequest) error {
-	friend := po.ConvertFriendRequestDtoToPo(req)
+// CreateFriendRequest 创建好友请求，已有待处理请求、被拒绝后 cooldown 内或 since 之后的请求数达到 dailyLimit 时拒绝
+func (u *friendRepoImpl) CreateFriendRequest(ctx context.Context, req *dto.FriendRequest, cooldown time.Duration, dailyLimit int, since time.Time) error {
+	tx := u.db.WithContext(ctx).Begin()
+	// 锁定请求者记录，保证同一用户并发发送时校验与写入的原子性
+	var requester po.User
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", req.RequesterId).First(&requester).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go CreateFriendRequest error", "err", err)
+		tx.Rollback()
+		return err
+	}
+
+	var latest po.FriendRequest
+	err = tx.Where("requester_id = ? and recipient_id = ?", req.RequesterId, req.RecipientId).Order("id desc").Limit(1).Find(&latest).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go CreateFriendRequest error", "err", err)
+		tx.Rollback()
+		return err
+	}
+	if latest.ID != 0 {
+		switch latest.Status {
+		case consts.FriendRequestStatusUntreated:
+			tx.Rollback()
+			return consts.ErrFriendRequestPending
+		case consts.FriendRequestStatusRefuse:
+			if time.Since(latest.UpdatedAt) < cooldown {
+				tx.Rollback()
+				return consts.ErrFriendRequestCooldown
+			}
+		}
+	}
 
-	err := u.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "requester_id"}, {Name: "recipient_id"}},
-		DoUpdates: clause.AssignmentColumns([]string{"status", "message"}),
-	}).Create(friend).Error
+	var count int64
+	err = tx.Model(&po.FriendRequest{}).Where("requester_id = ? and created_at >= ?", req.RequesterId, since).Count(&count).Error
 	if err != nil {
-		slog.Error("internal/repository/impl/user_repo_impl.go AddFriend error", err)
+		slog.Error("internal/repository/impl/friend_repo_impl.go CreateFriendRequest error", "err", err)
+		tx.Rollback()
+		return err
+	}
+	if count >= int64(dailyLimit) {
+		tx.Rollback()
+		return consts.ErrFriendRequestLimit
+	}
+
+	if err = tx.Create(po.ConvertFriendRequestDtoToPo(req)).Error; err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go CreateFriendRequest error", "err", err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// ExpireFriendRequest 将用户相关的、创建时间早于 deadline 的待处理请求标记为已过期
+func (u *friendRepoImpl) ExpireFriendRequest(ctx context.Context, userId uint, deadline time.Time) error {
+	err := u.db.WithContext(ctx).Model(&po.FriendRequest{}).
+		Where("(requester_id = ? or recipient_id = ?) and status = ? and created_at < ?", userId, userId, consts.FriendRequestStatusUntreated, deadline).
+		Update("status", consts.FriendRequestStatusExpired).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go ExpireFriendRequest error", "err", err)
 	}
 	return err
 }
 
+// UpdateFriendRequest 处理待处理的好友请求，没有待处理请求时返回 ErrFriendRequestNotExist
 func (u *friendRepoImpl) UpdateFriendRequest(ctx context.Context, req *dto.FriendRequest) error {
 	data := po.ConvertFriendRequestDtoToPo(req)
 	if req.Status == consts.FriendRequestStatusAgree {
 		tx := u.db.Begin()
-		err := tx.Where("requester_id = ? and recipient_id = ? and status = 0", req.RequesterId, req.RecipientId).Updates(data).Error
-		if err != nil {
-			slog.Error("internal/repository/impl/user_repo_impl.go UpdateFriendRequest error", err)
+		result := tx.Where("requester_id = ? and recipient_id = ? and status = 0", req.RequesterId, req.RecipientId).Updates(data)
+		if result.Error != nil {
+			slog.Error("internal/repository/impl/friend_repo_impl.go UpdateFriendRequest error", "err", result.Error)
 			tx.Rollback()
-			return err
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			tx.Rollback()
+			return consts.ErrFriendRequestNotExist
 		}
 
-		err = u.creteFriendShip(ctx, tx, &po.FriendShip{UserId: req.RequesterId, FriendId: req.RecipientId})
+		err := u.creteFriendShip(ctx, tx, &po.FriendShip{UserId: req.RequesterId, FriendId: req.RecipientId})
 		if err != nil {
 			tx.Rollback()
 			return err
@@ -56,11 +114,15 @@ func (u *friendRepoImpl) UpdateFriendRequest(ctx context.Context, req *dto.Frien
 		return nil
 	}
 
-	err := u.db.Where("requester_id = ? and recipient_id = ? and status = 0", req.RequesterId, req.RecipientId).Updates(data).Error
-	if err != nil {
-		slog.Error("internal/repository/impl/user_repo_impl.go UpdateFriendRequest error", err)
+	result := u.db.Where("requester_id = ? and recipient_id = ? and status = 0", req.RequesterId, req.RecipientId).Updates(data)
+	if result.Error != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go UpdateFriendRequest error", "err", result.Error)
+		return result.Error
 	}
-	return err
+	if result.RowsAffected == 0 {
+		return consts.ErrFriendRequestNotExist
+	}
+	return nil
 }
 
 func (u *friendRepoImpl) creteFriendShip(ctx context.Context, tx *gorm.DB, ship *po.FriendShip) error {
@@ -99,7 +161,7 @@ func (u *friendRepoImpl) GetFriendRequestListByRequesterIdOrRecipientId(ctx cont
 
 func (u *friendRepoImpl) GetFriendListByUserId(ctx context.Context, userId uint) ([]*dto.User, error) {
 	var data []*po.User
-	err := u.db.Select("user.id", "nickname", "avatar", "signature", "gender", "age").Joins("join friend_ship on user.id = friend_ship.friend_id and friend_ship.deleted_at is null").Where("user_id = ?", userId).
+	err := u.db.Select("user.id", "nickname", "avatar", "signature", "gender", "age", "profile_scope").Joins("join friend_ship on user.id = friend_ship.friend_id and friend_ship.deleted_at is null").Where("user_id = ?", userId).
 		Find(&data).Error
 	if err != nil {
 		slog.Error("internal/repository/impl/user_repo_impl.go GetFriendListByUserId error", err)
@@ -135,3 +197,276 @@ func (u *friendRepoImpl) FriendRequestStatistics(ctx context.Context, userId uin
 	}
 	return data, nil
 }
+
+func (u *friendRepoImpl) BlockUser(ctx context.Context, userId, blockedId uint) error {
+	err := u.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "blocked_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"deleted_at": gorm.Expr("NULL"),
+		}),
+	}).Create(&po.FriendBlock{UserId: userId, BlockedId: blockedId}).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go BlockUser error", "err", err)
+	}
+	return err
+}
+
+func (u *friendRepoImpl) UnblockUser(ctx context.Context, userId, blockedId uint) error {
+	err := u.db.WithContext(ctx).Where("user_id = ? AND blocked_id = ?", userId, blockedId).Delete(&po.FriendBlock{}).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go UnblockUser error", "err", err)
+	}
+	return err
+}
+
+func (u *friendRepoImpl) GetBlockList(ctx context.Context, userId uint) ([]*dto.User, error) {
+	var data []*po.User
+	err := u.db.WithContext(ctx).Select("user.id", "nickname", "avatar", "signature", "gender", "age").
+		Joins("join friend_block on user.id = friend_block.blocked_id and friend_block.deleted_at is null").
+		Where("friend_block.user_id = ?", userId).Order("friend_block.id desc").
+		Find(&data).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go GetBlockList error", "err", err)
+		return nil, err
+	}
+	return po.BatchConvertUserPoToDto(data), nil
+}
+
+// GetBlockerIds 获取 userIds 中拉黑了 blockedId 的用户
+func (u *friendRepoImpl) GetBlockerIds(ctx context.Context, userIds []uint, blockedId uint) ([]uint, error) {
+	var ids []uint
+	if len(userIds) == 0 {
+		return ids, nil
+	}
+	err := u.db.WithContext(ctx).Model(&po.FriendBlock{}).Where("user_id in ? AND blocked_id = ?", userIds, blockedId).Pluck("user_id", &ids).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go GetBlockerIds error", "err", err)
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (u *friendRepoImpl) GetFriendShipList(ctx context.Context, userId uint) ([]*dto.FriendShip, error) {
+	var data []*po.FriendShip
+	err := u.db.WithContext(ctx).Where("user_id = ?", userId).Find(&data).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go GetFriendShipList error", "err", err)
+		return nil, err
+	}
+	list := make([]*dto.FriendShip, len(data))
+	for i, datum := range data {
+		list[i] = datum.ConvertToDto()
+	}
+	return list, nil
+}
+
+func (u *friendRepoImpl) UpdateFriendRemark(ctx context.Context, userId, friendId uint, remark string) error {
+	err := u.db.WithContext(ctx).Model(&po.FriendShip{}).Where("user_id = ? AND friend_id = ?", userId, friendId).Update("remark", remark).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go UpdateFriendRemark error", "err", err)
+	}
+	return err
+}
+
+func (u *friendRepoImpl) UpdateFriendTags(ctx context.Context, userId, friendId uint, tags []string) error {
+	err := u.db.WithContext(ctx).Model(&po.FriendShip{}).Where("user_id = ? AND friend_id = ?", userId, friendId).Update("tags", strings.Join(tags, ",")).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go UpdateFriendTags error", "err", err)
+	}
+	return err
+}
+
+func (u *friendRepoImpl) MoveFriendCategory(ctx context.Context, userId uint, friendIds []uint, categoryId uint) error {
+	err := u.db.WithContext(ctx).Model(&po.FriendShip{}).Where("user_id = ? AND friend_id in ?", userId, friendIds).Update("category_id", categoryId).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go MoveFriendCategory error", "err", err)
+	}
+	return err
+}
+
+func (u *friendRepoImpl) CountFriendCategory(ctx context.Context, userId uint) (int64, error) {
+	var count int64
+	err := u.db.WithContext(ctx).Model(&po.FriendCategory{}).Where("user_id = ?", userId).Count(&count).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go CountFriendCategory error", "err", err)
+	}
+	return count, err
+}
+
+func (u *friendRepoImpl) GetFriendCategory(ctx context.Context, userId, categoryId uint) (*dto.FriendCategory, error) {
+	var data po.FriendCategory
+	err := u.db.WithContext(ctx).Where("id = ? AND user_id = ?", categoryId, userId).Find(&data).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go GetFriendCategory error", "err", err)
+		return nil, err
+	}
+	return data.ConvertToDto(), nil
+}
+
+func (u *friendRepoImpl) GetFriendCategoryList(ctx context.Context, userId uint) ([]*dto.FriendCategory, error) {
+	var data []*po.FriendCategory
+	err := u.db.WithContext(ctx).Where("user_id = ?", userId).Order("sort asc, id asc").Find(&data).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go GetFriendCategoryList error", "err", err)
+		return nil, err
+	}
+	return po.BatchConvertFriendCategoryPoToDto(data), nil
+}
+
+// CreateFriendCategory 创建好友分组，新分组排在最后
+func (u *friendRepoImpl) CreateFriendCategory(ctx context.Context, category *dto.FriendCategory) (*dto.FriendCategory, error) {
+	var sort int
+	err := u.db.WithContext(ctx).Model(&po.FriendCategory{}).Where("user_id = ?", category.UserId).Select("coalesce(max(sort), 0)").Scan(&sort).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go CreateFriendCategory error", "err", err)
+		return nil, err
+	}
+	data := &po.FriendCategory{UserId: category.UserId, Name: category.Name, Sort: sort + 1}
+	if err = u.db.WithContext(ctx).Create(data).Error; err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go CreateFriendCategory error", "err", err)
+		return nil, err
+	}
+	return data.ConvertToDto(), nil
+}
+
+func (u *friendRepoImpl) UpdateFriendCategory(ctx context.Context, category *dto.FriendCategory) error {
+	result := u.db.WithContext(ctx).Model(&po.FriendCategory{}).Where("id = ? AND user_id = ?", category.ID, category.UserId).Update("name", category.Name)
+	if result.Error != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go UpdateFriendCategory error", "err", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return consts.ErrFriendCategoryNotExist
+	}
+	return nil
+}
+
+// DeleteFriendCategory 删除好友分组，分组内的好友移回默认分组
+func (u *friendRepoImpl) DeleteFriendCategory(ctx context.Context, userId, categoryId uint) error {
+	tx := u.db.WithContext(ctx).Begin()
+	result := tx.Where("id = ? AND user_id = ?", categoryId, userId).Delete(&po.FriendCategory{})
+	if result.Error != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go DeleteFriendCategory error", "err", result.Error)
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return consts.ErrFriendCategoryNotExist
+	}
+	err := tx.Model(&po.FriendShip{}).Where("user_id = ? AND category_id = ?", userId, categoryId).Update("category_id", 0).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go DeleteFriendCategory error", "err", err)
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+// SortFriendCategory 按 categoryIds 的顺序重排好友分组
+func (u *friendRepoImpl) SortFriendCategory(ctx context.Context, userId uint, categoryIds []uint) error {
+	tx := u.db.WithContext(ctx).Begin()
+	for i, id := range categoryIds {
+		err := tx.Model(&po.FriendCategory{}).Where("id = ? AND user_id = ?", id, userId).Update("sort", i+1).Error
+		if err != nil {
+			slog.Error("internal/repository/impl/friend_repo_impl.go SortFriendCategory error", "err", err)
+			tx.Rollback()
+			return err
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
+// GetFriendRecommend 按共同好友数与共同群数推荐非好友用户，排除拉黑关系与待处理的好友请求
+// 每种来源最多取 FriendRecommendSourceLimit 条关系，大群成员不参与推荐
+func (u *friendRepoImpl) GetFriendRecommend(ctx context.Context, userId uint, limit int) ([]*dto.FriendRecommend, error) {
+	var data []*dto.FriendRecommend
+	err := u.db.WithContext(ctx).Raw(`
+SELECT c.user_id, SUM(c.mutual) AS mutual_friend_count, SUM(c.shared) AS shared_group_count
+FROM (
+	(SELECT f2.friend_id AS user_id, 1 AS mutual, 0 AS shared
+	FROM friend_ship f1
+	JOIN friend_ship f2 ON f2.user_id = f1.friend_id AND f2.deleted_at IS NULL
+	WHERE f1.user_id = @user AND f1.deleted_at IS NULL
+	LIMIT @source_limit)
+	UNION ALL
+	(SELECT g2.user_id, 0, 1
+	FROM group_ship g1
+	JOIN `+"`group`"+` g ON g.id = g1.group_id AND g.type <> @large AND g.deleted_at IS NULL
+	JOIN group_ship g2 ON g2.group_id = g1.group_id AND g2.deleted_at IS NULL
+	WHERE g1.user_id = @user AND g1.deleted_at IS NULL
+	LIMIT @source_limit)
+) c
+WHERE c.user_id <> @user
+	AND NOT EXISTS (SELECT 1 FROM friend_ship f WHERE f.user_id = @user AND f.friend_id = c.user_id AND f.deleted_at IS NULL)
+	AND NOT EXISTS (SELECT 1 FROM friend_block b WHERE b.deleted_at IS NULL
+		AND ((b.user_id = @user AND b.blocked_id = c.user_id) OR (b.user_id = c.user_id AND b.blocked_id = @user)))
+	AND NOT EXISTS (SELECT 1 FROM friend_request r WHERE r.deleted_at IS NULL AND r.status = @untreated
+		AND ((r.requester_id = @user AND r.recipient_id = c.user_id) OR (r.requester_id = c.user_id AND r.recipient_id = @user)))
+GROUP BY c.user_id
+ORDER BY SUM(c.mutual) * @weight + SUM(c.shared) DESC, c.user_id DESC
+LIMIT @limit`,
+		sql.Named("user", userId),
+		sql.Named("untreated", consts.FriendRequestStatusUntreated),
+		sql.Named("weight", consts.FriendRecommendMutualWeight),
+		sql.Named("large", consts.GroupTypeLarge),
+		sql.Named("source_limit", consts.FriendRecommendSourceLimit),
+		sql.Named("limit", limit),
+	).Scan(&data).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go GetFriendRecommend error", "err", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+// HasMutualConnection 两个用户之间是否有共同好友或同在一个群
+func (u *friendRepoImpl) HasMutualConnection(ctx context.Context, userId, targetId uint) (bool, error) {
+	var exist bool
+	err := u.db.WithContext(ctx).Raw(`
+SELECT EXISTS (
+	SELECT 1 FROM friend_ship f1
+	JOIN friend_ship f2 ON f2.user_id = f1.friend_id AND f2.friend_id = @target AND f2.deleted_at IS NULL
+	WHERE f1.user_id = @user AND f1.friend_id NOT IN (@user, @target) AND f1.deleted_at IS NULL
+) OR EXISTS (
+	SELECT 1 FROM group_ship g1
+	JOIN group_ship g2 ON g2.group_id = g1.group_id AND g2.user_id = @target AND g2.deleted_at IS NULL
+	WHERE g1.user_id = @user AND g1.deleted_at IS NULL
+)`, sql.Named("user", userId), sql.Named("target", targetId)).Scan(&exist).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go HasMutualConnection error", "err", err)
+		return false, err
+	}
+	return exist, nil
+}
+
+// RemoveUserRelations 注销时清除用户的好友关系、分组、黑名单，并将相关的待处理请求标记为过期
+func (u *friendRepoImpl) RemoveUserRelations(ctx context.Context, userId uint) error {
+	tx := u.db.WithContext(ctx).Begin()
+	if err := tx.Where("user_id = ? OR friend_id = ?", userId, userId).Delete(&po.FriendShip{}).Error; err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go RemoveUserRelations error", "err", err)
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("user_id = ?", userId).Delete(&po.FriendCategory{}).Error; err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go RemoveUserRelations error", "err", err)
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("user_id = ? OR blocked_id = ?", userId, userId).Delete(&po.FriendBlock{}).Error; err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go RemoveUserRelations error", "err", err)
+		tx.Rollback()
+		return err
+	}
+	err := tx.Model(&po.FriendRequest{}).
+		Where("(requester_id = ? or recipient_id = ?) and status = ?", userId, userId, consts.FriendRequestStatusUntreated).
+		Update("status", consts.FriendRequestStatusExpired).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/friend_repo_impl.go RemoveUserRelations error", "err", err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
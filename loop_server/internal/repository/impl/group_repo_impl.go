@@ -4,12 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/google/uuid"
+	"github.com/samber/lo"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"log/slog"
 	"loop_server/infra/consts"
 	"loop_server/internal/model/dto"
 	"loop_server/internal/model/po"
+	"strings"
 	"time"
 )
 
@@ -23,6 +25,9 @@ func NewGroupRepoImpl(db *gorm.DB) *groupRepoImpl {
 
 func (g *groupRepoImpl) CreateGroup(ctx context.Context, dto *dto.Group, userIds []uint) (*dto.Group, *po.GroupMessage, error) {
 	group := po.ConvertGroupDtoToPo(dto)
+	if len(lo.Uniq(append(userIds, dto.OwnerId))) > consts.GroupTypeMaxMember[group.Type] {
+		return nil, nil, consts.ErrGroupFull
+	}
 	tx := g.db.WithContext(ctx).Begin()
 	err := tx.Create(group).Error
 	if err != nil {
@@ -91,24 +96,105 @@ func (g *groupRepoImpl) UpdateGroup(ctx context.Context, dt *dto.Group) (*dto.Gr
 	return group.ConvertDto(), nil
 }
 
-func (g *groupRepoImpl) DeleteGroup(ctx context.Context, groupId uint) error {
+// DeleteGroup 解散群，purge 为 true 时同时清除群消息，否则归档为只读
+func (g *groupRepoImpl) DeleteGroup(ctx context.Context, groupId uint, purge bool) error {
+	// 群和解散时的成员关系使用相同的删除时间，用于判断用户是否可以查看归档消息
+	now := time.Now()
 	tx := g.db.WithContext(ctx).Begin()
-	err := tx.Where("id = ?", groupId).Delete(&po.Group{}).Error
+	err := tx.Model(&po.Group{}).Where("id = ?", groupId).Updates(map[string]interface{}{"archived": !purge, "deleted_at": now}).Error
 	if err != nil {
-		slog.Error("internal/repository/impl/group_repo_impl.go DeleteGroup err:", err)
+		slog.Error("internal/repository/impl/group_repo_impl.go DeleteGroup err:", "err", err)
 		tx.Rollback()
 		return err
 	}
-	err = tx.Where("group_id = ?", groupId).Delete(&po.GroupShip{}).Error
+	err = tx.Model(&po.GroupShip{}).Where("group_id = ?", groupId).Update("deleted_at", now).Error
 	if err != nil {
 		slog.Error("internal/repository/impl/group_repo_impl.go DeleteGroup err:", err)
 		tx.Rollback()
 		return err
 	}
+	err = tx.Where("group_id = ?", groupId).Delete(&po.GroupJoinRequest{}).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go DeleteGroup err:", "err", err)
+		tx.Rollback()
+		return err
+	}
+	if purge {
+		err = tx.Unscoped().Where("group_id = ?", groupId).Delete(&po.GroupMessage{}).Error
+		if err != nil {
+			slog.Error("internal/repository/impl/group_repo_impl.go DeleteGroup purge message err:", "err", err)
+			tx.Rollback()
+			return err
+		}
+	}
 	tx.Commit()
 	return nil
 }
 
+// OwnerExitGroup 群主退群，群主身份转让给最早的管理员，没有管理员则转让给最早入群的成员
+// 返回新群主id，群内没有其他成员时返回0且不做任何修改
+func (g *groupRepoImpl) OwnerExitGroup(ctx context.Context, groupId, ownerId uint) (uint, error) {
+	tx := g.db.WithContext(ctx).Begin()
+	var successor po.GroupShip
+	err := tx.Where("group_id = ? and user_id <> ?", groupId, ownerId).Order("role desc, id asc").Limit(1).Find(&successor).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go OwnerExitGroup err:", "err", err)
+		tx.Rollback()
+		return 0, err
+	}
+	if successor.ID == 0 {
+		tx.Rollback()
+		return 0, nil
+	}
+	err = tx.Model(&po.GroupShip{}).Where("id = ?", successor.ID).Update("role", consts.GroupRoleOwner).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go OwnerExitGroup err:", "err", err)
+		tx.Rollback()
+		return 0, err
+	}
+	err = tx.Model(&po.Group{}).Where("id = ?", groupId).Update("owner_id", successor.UserId).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go OwnerExitGroup err:", "err", err)
+		tx.Rollback()
+		return 0, err
+	}
+	err = tx.Where("group_id = ? and user_id = ?", groupId, ownerId).Delete(&po.GroupShip{}).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go OwnerExitGroup err:", "err", err)
+		tx.Rollback()
+		return 0, err
+	}
+	tx.Commit()
+	return successor.UserId, nil
+}
+
+// GetArchivedGroup 获取用户可查看的已归档群，用户需在群解散时仍是群成员
+func (g *groupRepoImpl) GetArchivedGroup(ctx context.Context, groupId, userId uint) (*dto.Group, error) {
+	var group po.Group
+	err := g.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").
+		Where("id = ? and archived = ?", groupId, true).
+		Find(&group).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go GetArchivedGroup err:", "err", err)
+		return nil, err
+	}
+	if group.ID == 0 {
+		return nil, nil
+	}
+	var count int64
+	err = g.db.WithContext(ctx).Unscoped().Model(&po.GroupShip{}).
+		Where("group_id = ? and user_id = ? and deleted_at = ?", groupId, userId, group.DeletedAt.Time).
+		Count(&count).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go GetArchivedGroup count member err:", "err", err)
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	return group.ConvertDto(), nil
+}
+
 func (g *groupRepoImpl) GetGroupList(ctx context.Context, userId uint) ([]*dto.Group, error) {
 	var group []*po.Group
 	err := g.db.WithContext(ctx).Model(&group).Joins("join group_ship on group_ship.group_id = group.id and group_ship.deleted_at is null and group_ship.user_id = ?", userId).Find(&group).Error
@@ -131,21 +217,53 @@ func (g *groupRepoImpl) GetGroup(ctx context.Context, groupId uint) (*dto.Group,
 }
 
 func (g *groupRepoImpl) AddMember(ctx context.Context, ship []*dto.GroupShip) error {
+	if len(ship) == 0 {
+		return nil
+	}
+	groupId := ship[0].GroupId
 	poShip := make([]*po.GroupShip, 0, len(ship))
+	userIds := make([]uint, 0, len(ship))
 	for _, s := range ship {
 		poShip = append(poShip, po.ConvertGroupShipDtoToPo(s))
+		userIds = append(userIds, s.UserId)
 	}
-	err := g.db.WithContext(ctx).Clauses(clause.OnConflict{
+	userIds = lo.Uniq(userIds)
+
+	tx := g.db.WithContext(ctx).Begin()
+	// 锁定群记录，保证成员数校验与写入的原子性
+	var group po.Group
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", groupId).First(&group).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go AddMember err:", "err", err)
+		tx.Rollback()
+		return err
+	}
+	var count int64
+	err = tx.Model(&po.GroupShip{}).Where("group_id = ? and user_id not in ?", groupId, userIds).Count(&count).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go AddMember err:", "err", err)
+		tx.Rollback()
+		return err
+	}
+	if int(count)+len(userIds) > consts.GroupTypeMaxMember[group.Type] {
+		tx.Rollback()
+		return consts.ErrGroupFull
+	}
+
+	err = tx.Clauses(clause.OnConflict{
 		Columns: []clause.Column{{Name: "group_id"}, {Name: "user_id"}},
+		// 重新入群时刷新 created_at，作为拉取群历史消息的起点
 		DoUpdates: clause.Assignments(map[string]interface{}{
 			"deleted_at": gorm.Expr("NULL"),
+			"created_at": time.Now(),
 		}),
 	}).Create(poShip).Error
 	if err != nil {
-		slog.Error("internal/repository/impl/group_repo_impl.go AddMember err:", err)
+		slog.Error("internal/repository/impl/group_repo_impl.go AddMember err:", "err", err)
+		tx.Rollback()
 		return err
 	}
-	return nil
+	return tx.Commit().Error
 }
 
 func (g *groupRepoImpl) DeleteMember(ctx context.Context, groupId uint, userIds []uint, role uint) error {
@@ -260,3 +378,205 @@ func (g *groupRepoImpl) TransferGroupOwner(ctx context.Context, groupId uint, cu
 	tx.Commit()
 	return nil
 }
+
+func (g *groupRepoImpl) GetGroupPermission(ctx context.Context, groupId uint) ([]*dto.GroupPermission, error) {
+	var permission []*po.GroupPermission
+	err := g.db.WithContext(ctx).Where("group_id = ?", groupId).Find(&permission).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go GetGroupPermission err:", "err", err)
+		return nil, err
+	}
+	data := make([]*dto.GroupPermission, 0, len(permission))
+	for _, p := range permission {
+		data = append(data, p.ConvertToDto())
+	}
+	return data, nil
+}
+
+func (g *groupRepoImpl) SaveGroupPermission(ctx context.Context, permission []*dto.GroupPermission) error {
+	if len(permission) == 0 {
+		return nil
+	}
+	data := make([]*po.GroupPermission, 0, len(permission))
+	for _, p := range permission {
+		data = append(data, po.ConvertGroupPermissionDtoToPo(p))
+	}
+	err := g.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "group_id"}, {Name: "action"}},
+		DoUpdates: clause.AssignmentColumns([]string{"min_role", "updated_at"}),
+	}).Create(data).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go SaveGroupPermission err:", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (g *groupRepoImpl) GetGroupShipByUserIds(ctx context.Context, groupId uint, userIds []uint) ([]*dto.GroupShip, error) {
+	var ship []*po.GroupShip
+	err := g.db.WithContext(ctx).Where("group_id = ? and user_id in ?", groupId, userIds).Find(&ship).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go GetGroupShipByUserIds err:", "err", err)
+		return nil, err
+	}
+	data := make([]*dto.GroupShip, 0, len(ship))
+	for _, groupShip := range ship {
+		data = append(data, groupShip.ConvertToDto())
+	}
+	return data, nil
+}
+
+func (g *groupRepoImpl) GetGroupShipListByUserId(ctx context.Context, userId uint) ([]*dto.GroupShip, error) {
+	var ship []*po.GroupShip
+	err := g.db.WithContext(ctx).Where("user_id = ?", userId).Find(&ship).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go GetGroupShipListByUserId err:", "err", err)
+		return nil, err
+	}
+	data := make([]*dto.GroupShip, 0, len(ship))
+	for _, groupShip := range ship {
+		data = append(data, groupShip.ConvertToDto())
+	}
+	return data, nil
+}
+
+func (g *groupRepoImpl) UpdateGroupNickname(ctx context.Context, groupId, userId uint, nickname string) error {
+	err := g.db.WithContext(ctx).Model(&po.GroupShip{}).Where("group_id = ? and user_id = ?", groupId, userId).Update("remark", nickname).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go UpdateGroupNickname err:", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (g *groupRepoImpl) UpdateGroupRemark(ctx context.Context, groupId, userId uint, remark string) error {
+	err := g.db.WithContext(ctx).Model(&po.GroupShip{}).Where("group_id = ? and user_id = ?", groupId, userId).Update("group_remark", remark).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go UpdateGroupRemark err:", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (g *groupRepoImpl) UpdateGroupType(ctx context.Context, groupId uint, groupType int) error {
+	err := g.db.WithContext(ctx).Model(&po.Group{}).Where("id = ?", groupId).Update("type", groupType).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go UpdateGroupType err:", "err", err)
+		return err
+	}
+	return nil
+}
+
+// likeEscaper 转义 LIKE 通配符
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+type groupSearchRow struct {
+	po.Group
+	MemberCount int
+}
+
+// SearchGroup 搜索公开群，按名称、标签匹配度和成员数排序
+func (g *groupRepoImpl) SearchGroup(ctx context.Context, req *dto.GroupSearchRequest) (*dto.GroupSearchResult, error) {
+	like := "%" + likeEscaper.Replace(req.Keyword) + "%"
+	query := func() *gorm.DB {
+		db := g.db.WithContext(ctx).Model(&po.Group{}).Where("is_public = ?", true)
+		if req.Keyword != "" {
+			db = db.Where("(name LIKE ? OR tags LIKE ?)", like, like)
+		}
+		if req.Category != "" {
+			db = db.Where("category = ?", req.Category)
+		}
+		return db
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go SearchGroup err:", "err", err)
+		return nil, err
+	}
+
+	var rows []*groupSearchRow
+	err := query().
+		Select("`group`.*, "+
+			"(SELECT COUNT(*) FROM group_ship gs WHERE gs.group_id = `group`.id AND gs.deleted_at IS NULL) AS member_count, "+
+			"(CASE WHEN name = ? THEN 4 WHEN name LIKE ? THEN 2 ELSE 0 END) + (CASE WHEN tags LIKE ? THEN 1 ELSE 0 END) AS score",
+			req.Keyword, like, like).
+		Order("score DESC, member_count DESC, `group`.id DESC").
+		Offset(req.Offset).Limit(req.Limit).
+		Scan(&rows).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go SearchGroup err:", "err", err)
+		return nil, err
+	}
+
+	list := make([]*dto.Group, 0, len(rows))
+	for _, row := range rows {
+		group := row.Group.ConvertDto()
+		group.MemberCount = row.MemberCount
+		list = append(list, group)
+	}
+	return &dto.GroupSearchResult{List: list, Total: total}, nil
+}
+
+func (g *groupRepoImpl) UpdateGroupDiscovery(ctx context.Context, dt *dto.Group) error {
+	group := po.ConvertGroupDtoToPo(dt)
+	updates := map[string]interface{}{
+		"is_public":   group.IsPublic,
+		"tags":        group.Tags,
+		"category":    group.Category,
+		"join_policy": group.JoinPolicy,
+	}
+	err := g.db.WithContext(ctx).Model(&po.Group{}).Where("id = ?", group.ID).Updates(updates).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go UpdateGroupDiscovery err:", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (g *groupRepoImpl) SaveGroupJoinRequest(ctx context.Context, req *dto.GroupJoinRequest) error {
+	err := g.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "group_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "message", "updated_at"}),
+	}).Create(po.ConvertGroupJoinRequestDtoToPo(req)).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go SaveGroupJoinRequest err:", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (g *groupRepoImpl) GetGroupJoinRequest(ctx context.Context, groupId, userId uint) (*dto.GroupJoinRequest, error) {
+	var req po.GroupJoinRequest
+	err := g.db.WithContext(ctx).Where("group_id = ? and user_id = ?", groupId, userId).Find(&req).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go GetGroupJoinRequest err:", "err", err)
+		return nil, err
+	}
+	return req.ConvertToDto(), nil
+}
+
+func (g *groupRepoImpl) GetGroupJoinRequestList(ctx context.Context, groupId uint) ([]*dto.GroupJoinRequest, error) {
+	var list []*po.GroupJoinRequest
+	err := g.db.WithContext(ctx).Where("group_id = ?", groupId).Order("updated_at desc").Find(&list).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go GetGroupJoinRequestList err:", "err", err)
+		return nil, err
+	}
+	data := make([]*dto.GroupJoinRequest, 0, len(list))
+	for _, req := range list {
+		data = append(data, req.ConvertToDto())
+	}
+	return data, nil
+}
+
+func (g *groupRepoImpl) UpdateGroupJoinRequest(ctx context.Context, req *dto.GroupJoinRequest) error {
+	err := g.db.WithContext(ctx).Model(&po.GroupJoinRequest{}).
+		Where("group_id = ? and user_id = ? and status = ?", req.GroupId, req.UserId, consts.GroupJoinRequestStatusUntreated).
+		Update("status", req.Status).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/group_repo_impl.go UpdateGroupJoinRequest err:", "err", err)
+		return err
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"gorm.io/gorm"
 	"log/slog"
+	"loop_server/infra/consts"
 	"loop_server/internal/model/po"
 )
 
@@ -64,3 +65,98 @@ func (g *imRepoImpl) UpdateGroupMessageLastSeqId(ctx context.Context, groupId ui
 	}
 	return nil
 }
+
+// AdvanceGroupMessageLastSeqId 仅当 seqId 比当前已确认的消息更新时才更新，避免乱序确认导致游标回退
+func (g *imRepoImpl) AdvanceGroupMessageLastSeqId(ctx context.Context, groupId uint, userId uint, seqId string) error {
+	err := g.db.WithContext(ctx).Exec(`UPDATE group_ship gs
+		JOIN group_message nm ON nm.seq_id = ? AND nm.group_id = gs.group_id
+		LEFT JOIN group_message om ON om.seq_id = gs.last_ack_seq_id
+		SET gs.last_ack_seq_id = nm.seq_id
+		WHERE gs.group_id = ? AND gs.user_id = ? AND (om.id IS NULL OR om.id < nm.id)`, seqId, groupId, userId).Error
+	if err != nil {
+		slog.Error("imRepoImpl.AdvanceGroupMessageLastSeqId err:", "err", err)
+		return err
+	}
+	return nil
+}
+
+// GetGroupMessageAfterSeqId 获取 seqId 之后的群消息，只返回用户最近一次入群之后的消息；
+// seqId 为空时从入群时开始，seqId 不属于该群时返回 ErrMessageNotExist
+func (g *imRepoImpl) GetGroupMessageAfterSeqId(ctx context.Context, groupId, userId uint, seqId string, limit int) ([]*po.GroupMessage, error) {
+	var id uint
+	if seqId != "" {
+		err := g.db.WithContext(ctx).Model(&po.GroupMessage{}).Select("id").
+			Where("group_id = ? AND seq_id = ?", groupId, seqId).Scan(&id).Error
+		if err != nil {
+			slog.Error("imRepoImpl.GetGroupMessageAfterSeqId err:", "err", err)
+			return nil, err
+		}
+		if id == 0 {
+			return nil, consts.ErrMessageNotExist
+		}
+	}
+
+	// 成员关系包含群解散时软删除的记录，created_at 为最近一次入群时间
+	joinedAt := g.db.Unscoped().Model(&po.GroupShip{}).Select("created_at").
+		Where("group_id = ? AND user_id = ?", groupId, userId)
+	var data []*po.GroupMessage
+	err := g.db.WithContext(ctx).
+		Where("group_id = ? AND id > ? AND created_at >= (?)", groupId, id, joinedAt).
+		Order("id").Limit(limit).
+		Find(&data).Error
+	if err != nil {
+		slog.Error("imRepoImpl.GetGroupMessageAfterSeqId err:", "err", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+// GetGroupMessageBySenderId 获取用户发送过的全部群消息，用于数据导出
+func (g *imRepoImpl) GetGroupMessageBySenderId(ctx context.Context, senderId uint) ([]*po.GroupMessage, error) {
+	var data []*po.GroupMessage
+	err := g.db.WithContext(ctx).Where("sender_id = ?", senderId).Order("id").Find(&data).Error
+	if err != nil {
+		slog.Error("imRepoImpl.GetGroupMessageBySenderId err:", "err", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+// AnonymizeGroupMessage 将用户发送的群消息匿名化，发送者置为 0
+func (g *imRepoImpl) AnonymizeGroupMessage(ctx context.Context, senderId uint) error {
+	err := g.db.WithContext(ctx).Model(&po.GroupMessage{}).Where("sender_id = ?", senderId).Update("sender_id", 0).Error
+	if err != nil {
+		slog.Error("imRepoImpl.AnonymizeGroupMessage err:", "err", err)
+		return err
+	}
+	return nil
+}
+
+// GetGroupMessageAround 获取 id 前后各 limit 条群消息，按 id 升序
+func (g *imRepoImpl) GetGroupMessageAround(ctx context.Context, groupId, id uint, limit int) ([]*po.GroupMessage, error) {
+	var before, after []*po.GroupMessage
+	err := g.db.WithContext(ctx).Where("group_id = ? AND id < ?", groupId, id).Order("id desc").Limit(limit).Find(&before).Error
+	if err != nil {
+		slog.Error("imRepoImpl.GetGroupMessageAround err:", "err", err)
+		return nil, err
+	}
+	err = g.db.WithContext(ctx).Where("group_id = ? AND id >= ?", groupId, id).Order("id").Limit(limit + 1).Find(&after).Error
+	if err != nil {
+		slog.Error("imRepoImpl.GetGroupMessageAround err:", "err", err)
+		return nil, err
+	}
+	data := make([]*po.GroupMessage, 0, len(before)+len(after))
+	for i := len(before) - 1; i >= 0; i-- {
+		data = append(data, before[i])
+	}
+	return append(data, after...), nil
+}
+
+// DeleteGroupMessage 删除群消息，删除后不再同步给成员
+func (g *imRepoImpl) DeleteGroupMessage(ctx context.Context, seqId string) error {
+	err := g.db.WithContext(ctx).Where("seq_id = ?", seqId).Delete(&po.GroupMessage{}).Error
+	if err != nil {
+		slog.Error("imRepoImpl.DeleteGroupMessage err:", "err", err)
+	}
+	return err
+}
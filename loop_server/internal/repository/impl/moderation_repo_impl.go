@@ -0,0 +1,72 @@
+package impl
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"log/slog"
+	"loop_server/infra/consts"
+	"loop_server/internal/model/dto"
+	"loop_server/internal/model/po"
+	"time"
+)
+
+type moderationRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewModerationRepoImpl(db *gorm.DB) *moderationRepoImpl {
+	return &moderationRepoImpl{db: db}
+}
+
+func (m *moderationRepoImpl) CreateReview(ctx context.Context, review *dto.ModerationReview) error {
+	data := po.ConvertModerationReviewDtoToPo(review)
+	if err := m.db.WithContext(ctx).Create(data).Error; err != nil {
+		slog.Error("internal/repository/impl/moderation_repo_impl.go CreateReview error", "err", err)
+		return err
+	}
+	review.ID = data.ID
+	return nil
+}
+
+// GetReviewList 按状态分页查询审核记录，先进先审
+func (m *moderationRepoImpl) GetReviewList(ctx context.Context, status, offset, limit int) ([]*dto.ModerationReview, int64, error) {
+	var (
+		data  []*po.ModerationReview
+		total int64
+	)
+	query := m.db.WithContext(ctx).Model(&po.ModerationReview{}).Where("status = ?", status)
+	if err := query.Count(&total).Error; err != nil {
+		slog.Error("internal/repository/impl/moderation_repo_impl.go GetReviewList count error", "err", err)
+		return nil, 0, err
+	}
+	if err := query.Order("id asc").Offset(offset).Limit(limit).Find(&data).Error; err != nil {
+		slog.Error("internal/repository/impl/moderation_repo_impl.go GetReviewList error", "err", err)
+		return nil, 0, err
+	}
+	return po.BatchConvertModerationReviewPoToDto(data), total, nil
+}
+
+// GetReview 获取审核记录，不存在时返回 nil
+func (m *moderationRepoImpl) GetReview(ctx context.Context, id uint) (*dto.ModerationReview, error) {
+	var data po.ModerationReview
+	if err := m.db.WithContext(ctx).Where("id = ?", id).Find(&data).Error; err != nil {
+		slog.Error("internal/repository/impl/moderation_repo_impl.go GetReview error", "err", err)
+		return nil, err
+	}
+	if data.ID == 0 {
+		return nil, nil
+	}
+	return data.ConvertToDto(), nil
+}
+
+// ResolveReview 处理待审核记录，记录不存在或已处理时返回 false
+func (m *moderationRepoImpl) ResolveReview(ctx context.Context, id uint, status int) (bool, error) {
+	res := m.db.WithContext(ctx).Model(&po.ModerationReview{}).
+		Where("id = ? and status = ?", id, consts.ModerationReviewPending).
+		Updates(map[string]any{"status": status, "reviewed_at": time.Now()})
+	if res.Error != nil {
+		slog.Error("internal/repository/impl/moderation_repo_impl.go ResolveReview error", "err", res.Error)
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
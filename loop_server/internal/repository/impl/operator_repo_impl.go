@@ -0,0 +1,80 @@
+package impl
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"log/slog"
+	"loop_server/internal/model/dto"
+	"loop_server/internal/model/po"
+	"time"
+)
+
+type operatorRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewOperatorRepoImpl(db *gorm.DB) *operatorRepoImpl {
+	return &operatorRepoImpl{db: db}
+}
+
+func (o *operatorRepoImpl) CreateOperator(ctx context.Context, operator *dto.Operator) error {
+	data := po.ConvertOperatorDtoToPo(operator)
+	if err := o.db.WithContext(ctx).Create(data).Error; err != nil {
+		slog.Error("internal/repository/impl/operator_repo_impl.go CreateOperator error", "err", err)
+		return err
+	}
+	operator.ID, operator.CreatedAt = data.ID, data.CreatedAt
+	return nil
+}
+
+func (o *operatorRepoImpl) GetOperatorById(ctx context.Context, id uint) (*dto.Operator, error) {
+	var data po.Operator
+	if err := o.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&data).Error; err != nil {
+		slog.Error("internal/repository/impl/operator_repo_impl.go GetOperatorById error", "err", err)
+		return nil, err
+	}
+	return data.ConvertToDto(), nil
+}
+
+func (o *operatorRepoImpl) GetOperatorByUsername(ctx context.Context, username string) (*dto.Operator, error) {
+	var data po.Operator
+	if err := o.db.WithContext(ctx).Where("username = ?", username).Limit(1).Find(&data).Error; err != nil {
+		slog.Error("internal/repository/impl/operator_repo_impl.go GetOperatorByUsername error", "err", err)
+		return nil, err
+	}
+	return data.ConvertToDto(), nil
+}
+
+func (o *operatorRepoImpl) GetOperatorList(ctx context.Context) ([]*dto.Operator, error) {
+	var data []*po.Operator
+	if err := o.db.WithContext(ctx).Order("id").Find(&data).Error; err != nil {
+		slog.Error("internal/repository/impl/operator_repo_impl.go GetOperatorList error", "err", err)
+		return nil, err
+	}
+	return po.BatchConvertOperatorPoToDto(data), nil
+}
+
+// UpdateOperator 更新昵称、角色和停用状态，密码为空时不修改
+func (o *operatorRepoImpl) UpdateOperator(ctx context.Context, operator *dto.Operator) error {
+	updates := map[string]any{
+		"nickname": operator.Nickname,
+		"role":     operator.Role,
+		"disabled": operator.Disabled,
+	}
+	if operator.Password != "" {
+		updates["password"] = operator.Password
+	}
+	if err := o.db.WithContext(ctx).Model(&po.Operator{}).Where("id = ?", operator.ID).Updates(updates).Error; err != nil {
+		slog.Error("internal/repository/impl/operator_repo_impl.go UpdateOperator error", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (o *operatorRepoImpl) UpdateLastLoginAt(ctx context.Context, id uint) error {
+	err := o.db.WithContext(ctx).Model(&po.Operator{}).Where("id = ?", id).Update("last_login_at", time.Now()).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/operator_repo_impl.go UpdateLastLoginAt error", "err", err)
+	}
+	return err
+}
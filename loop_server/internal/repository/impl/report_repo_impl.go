@@ -0,0 +1,99 @@
+package impl
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"log/slog"
+	"loop_server/infra/consts"
+	"loop_server/internal/model/dto"
+	"loop_server/internal/model/po"
+	"time"
+)
+
+type reportRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewReportRepoImpl(db *gorm.DB) *reportRepoImpl {
+	return &reportRepoImpl{db: db}
+}
+
+func (r *reportRepoImpl) CreateReport(ctx context.Context, report *dto.Report) error {
+	data := po.ConvertReportDtoToPo(report)
+	if err := r.db.WithContext(ctx).Create(data).Error; err != nil {
+		slog.Error("internal/repository/impl/report_repo_impl.go CreateReport error", "err", err)
+		return err
+	}
+	report.ID, report.CreatedAt = data.ID, data.CreatedAt
+	return nil
+}
+
+func (r *reportRepoImpl) GetPendingReport(ctx context.Context, reporterId uint, targetType, targetId string) (*dto.Report, error) {
+	var data po.Report
+	err := r.db.WithContext(ctx).
+		Where("reporter_id = ? and target_type = ? and target_id = ? and status = ?", reporterId, targetType, targetId, consts.ReportStatusPending).
+		Limit(1).Find(&data).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/report_repo_impl.go GetPendingReport error", "err", err)
+		return nil, err
+	}
+	return data.ConvertToDto(), nil
+}
+
+func (r *reportRepoImpl) GetReport(ctx context.Context, id uint) (*dto.Report, error) {
+	var data po.Report
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&data).Error; err != nil {
+		slog.Error("internal/repository/impl/report_repo_impl.go GetReport error", "err", err)
+		return nil, err
+	}
+	return data.ConvertToDto(), nil
+}
+
+// GetReportList 按状态分页查询举报，targetType 为空时不过滤类型
+func (r *reportRepoImpl) GetReportList(ctx context.Context, status int, targetType string, offset, limit int) ([]*dto.Report, int64, error) {
+	var (
+		data  []*po.Report
+		total int64
+	)
+	query := r.db.WithContext(ctx).Model(&po.Report{}).Where("status = ?", status)
+	if targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		slog.Error("internal/repository/impl/report_repo_impl.go GetReportList count error", "err", err)
+		return nil, 0, err
+	}
+	if err := query.Order("id asc").Offset(offset).Limit(limit).Find(&data).Error; err != nil {
+		slog.Error("internal/repository/impl/report_repo_impl.go GetReportList error", "err", err)
+		return nil, 0, err
+	}
+	return po.BatchConvertReportPoToDto(data), total, nil
+}
+
+func (r *reportRepoImpl) UpdateReportStatus(ctx context.Context, id uint, status int) error {
+	err := r.db.WithContext(ctx).Model(&po.Report{}).Where("id = ?", id).
+		Updates(map[string]any{"status": status, "handled_at": time.Now()}).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/report_repo_impl.go UpdateReportStatus error", "err", err)
+	}
+	return err
+}
+
+func (r *reportRepoImpl) CreateReportAction(ctx context.Context, action *dto.ReportAction) error {
+	data := po.ConvertReportActionDtoToPo(action)
+	if err := r.db.WithContext(ctx).Create(data).Error; err != nil {
+		slog.Error("internal/repository/impl/report_repo_impl.go CreateReportAction error", "err", err)
+		return err
+	}
+	action.ID, action.CreatedAt = data.ID, data.CreatedAt
+	return nil
+}
+
+func (r *reportRepoImpl) GetReportActions(ctx context.Context, reportId uint) ([]*dto.ReportAction, error) {
+	var data []*po.ReportAction
+	if err := r.db.WithContext(ctx).Where("report_id = ?", reportId).Order("id").Find(&data).Error; err != nil {
+		slog.Error("internal/repository/impl/report_repo_impl.go GetReportActions error", "err", err)
+		return nil, err
+	}
+	return po.BatchConvertReportActionPoToDto(data), nil
+}
@@ -2,11 +2,14 @@ package impl
 
 import (
 	"context"
+	"fmt"
 	"gorm.io/gorm"
 	"log/slog"
+	"loop_server/infra/consts"
 	"loop_server/internal/model/dto"
 	"loop_server/internal/model/po"
 	"loop_server/pkg/bcrypt"
+	"time"
 )
 
 type userRepoImpl struct {
@@ -99,3 +102,145 @@ func (u *userRepoImpl) GetUserListByUserIds(ctx context.Context, userIds []uint)
 	}
 	return po.BatchConvertUserPoToDto(data), nil
 }
+
+func (u *userRepoImpl) UpdateUserPrivacy(ctx context.Context, userId uint, privacy *dto.UserPrivacy) error {
+	updates := map[string]interface{}{
+		"search_by_phone":      privacy.SearchByPhone,
+		"friend_request_scope": privacy.FriendRequestScope,
+		"stranger_message":     privacy.StrangerMessage,
+		"profile_scope":        privacy.ProfileScope,
+		"call_scope":           privacy.CallScope,
+	}
+	if err := u.db.WithContext(ctx).Model(&po.User{}).Where("id = ?", userId).Updates(updates).Error; err != nil {
+		slog.Error("internal/repository/impl/user_repo_impl.go UpdateUserPrivacy error", "err", err)
+		return err
+	}
+	return nil
+}
+
+// ScheduleDeletion 设置计划注销时间，at 为空表示取消注销
+func (u *userRepoImpl) ScheduleDeletion(ctx context.Context, userId uint, at *time.Time) error {
+	if err := u.db.WithContext(ctx).Model(&po.User{}).Where("id = ?", userId).Update("delete_scheduled_at", at).Error; err != nil {
+		slog.Error("internal/repository/impl/user_repo_impl.go ScheduleDeletion error", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (u *userRepoImpl) GetUserIdsDueForDeletion(ctx context.Context, deadline time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := u.db.WithContext(ctx).Model(&po.User{}).
+		Where("delete_scheduled_at is not null and delete_scheduled_at <= ?", deadline).
+		Order("delete_scheduled_at").Limit(limit).Pluck("id", &ids).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/user_repo_impl.go GetUserIdsDueForDeletion error", "err", err)
+		return nil, err
+	}
+	return ids, nil
+}
+
+// AnonymizeUser 清除用户个人资料并软删除，手机号替换为占位值以释放原手机号
+func (u *userRepoImpl) AnonymizeUser(ctx context.Context, userId uint) error {
+	updates := map[string]interface{}{
+		"nickname":            consts.DeletedUserNickname,
+		"avatar":              "",
+		"signature":           "",
+		"password":            "",
+		"phone":               fmt.Sprintf("x%010d", userId),
+		"gender":              0,
+		"age":                 0,
+		"delete_scheduled_at": nil,
+		"totp_secret":         "",
+		"totp_enabled":        false,
+		"deleted_at":          time.Now(),
+	}
+	if err := u.db.WithContext(ctx).Model(&po.User{}).Where("id = ?", userId).Updates(updates).Error; err != nil {
+		slog.Error("internal/repository/impl/user_repo_impl.go AnonymizeUser error", "err", err)
+		return err
+	}
+	return nil
+}
+
+// EnableTotp 开启两步验证并替换全部恢复码
+func (u *userRepoImpl) EnableTotp(ctx context.Context, userId uint, secret string, codeHashes []string) error {
+	codes := make([]*po.UserRecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, &po.UserRecoveryCode{UserId: userId, CodeHash: hash})
+	}
+	tx := u.db.WithContext(ctx).Begin()
+	err := tx.Model(&po.User{}).Where("id = ?", userId).
+		Updates(map[string]interface{}{"totp_secret": secret, "totp_enabled": true}).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/user_repo_impl.go EnableTotp error", "err", err)
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Unscoped().Where("user_id = ?", userId).Delete(&po.UserRecoveryCode{}).Error; err != nil {
+		slog.Error("internal/repository/impl/user_repo_impl.go EnableTotp error", "err", err)
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Create(&codes).Error; err != nil {
+		slog.Error("internal/repository/impl/user_repo_impl.go EnableTotp error", "err", err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+func (u *userRepoImpl) DisableTotp(ctx context.Context, userId uint) error {
+	tx := u.db.WithContext(ctx).Begin()
+	err := tx.Model(&po.User{}).Where("id = ?", userId).
+		Updates(map[string]interface{}{"totp_secret": "", "totp_enabled": false}).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/user_repo_impl.go DisableTotp error", "err", err)
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Unscoped().Where("user_id = ?", userId).Delete(&po.UserRecoveryCode{}).Error; err != nil {
+		slog.Error("internal/repository/impl/user_repo_impl.go DisableTotp error", "err", err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// UseRecoveryCode 使用恢复码，恢复码不存在或已使用时返回 false
+func (u *userRepoImpl) UseRecoveryCode(ctx context.Context, userId uint, codeHash string) (bool, error) {
+	result := u.db.WithContext(ctx).Model(&po.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at is null", userId, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		slog.Error("internal/repository/impl/user_repo_impl.go UseRecoveryCode error", "err", result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (u *userRepoImpl) CountRecoveryCode(ctx context.Context, userId uint) (int64, error) {
+	var count int64
+	err := u.db.WithContext(ctx).Model(&po.UserRecoveryCode{}).Where("user_id = ? AND used_at is null", userId).Count(&count).Error
+	if err != nil {
+		slog.Error("internal/repository/impl/user_repo_impl.go CountRecoveryCode error", "err", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// UpdateMutedUntil 设置禁言截止时间，until 为空表示解除禁言
+func (u *userRepoImpl) UpdateMutedUntil(ctx context.Context, userId uint, until *time.Time) error {
+	if err := u.db.WithContext(ctx).Model(&po.User{}).Where("id = ?", userId).Update("muted_until", until).Error; err != nil {
+		slog.Error("internal/repository/impl/user_repo_impl.go UpdateMutedUntil error", "err", err)
+		return err
+	}
+	return nil
+}
+
+// UpdateBannedUntil 设置封禁截止时间，until 为空表示解除封禁
+func (u *userRepoImpl) UpdateBannedUntil(ctx context.Context, userId uint, until *time.Time) error {
+	if err := u.db.WithContext(ctx).Model(&po.User{}).Where("id = ?", userId).Update("banned_until", until).Error; err != nil {
+		slog.Error("internal/repository/impl/user_repo_impl.go UpdateBannedUntil error", "err", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"loop_server/internal/model/dto"
+)
+
+type ModerationRepo interface {
+	CreateReview(ctx context.Context, review *dto.ModerationReview) error
+	GetReviewList(ctx context.Context, status, offset, limit int) ([]*dto.ModerationReview, int64, error)
+	GetReview(ctx context.Context, id uint) (*dto.ModerationReview, error)
+	ResolveReview(ctx context.Context, id uint, status int) (bool, error)
+}
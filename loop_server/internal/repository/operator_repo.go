@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"loop_server/internal/model/dto"
+)
+
+type OperatorRepo interface {
+	CreateOperator(ctx context.Context, operator *dto.Operator) error
+	GetOperatorById(ctx context.Context, id uint) (*dto.Operator, error)
+	GetOperatorByUsername(ctx context.Context, username string) (*dto.Operator, error)
+	GetOperatorList(ctx context.Context) ([]*dto.Operator, error)
+	UpdateOperator(ctx context.Context, operator *dto.Operator) error
+	UpdateLastLoginAt(ctx context.Context, id uint) error
+}
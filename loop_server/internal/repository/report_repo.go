@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"loop_server/internal/model/dto"
+)
+
+type ReportRepo interface {
+	CreateReport(ctx context.Context, report *dto.Report) error
+	GetPendingReport(ctx context.Context, reporterId uint, targetType, targetId string) (*dto.Report, error)
+	GetReport(ctx context.Context, id uint) (*dto.Report, error)
+	GetReportList(ctx context.Context, status int, targetType string, offset, limit int) ([]*dto.Report, int64, error)
+	UpdateReportStatus(ctx context.Context, id uint, status int) error
+	CreateReportAction(ctx context.Context, action *dto.ReportAction) error
+	GetReportActions(ctx context.Context, reportId uint) ([]*dto.ReportAction, error)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"loop_server/internal/model/dto"
+	"time"
 )
 
 type UserRepo interface {
@@ -12,4 +13,14 @@ type UserRepo interface {
 	UpdateUser(ctx context.Context, user *dto.User) error
 	UpdateUserPassword(ctx context.Context, id uint, password string) error
 	GetUserListByUserIds(ctx context.Context, userIds []uint) ([]*dto.User, error)
+	UpdateUserPrivacy(ctx context.Context, userId uint, privacy *dto.UserPrivacy) error
+	ScheduleDeletion(ctx context.Context, userId uint, at *time.Time) error
+	GetUserIdsDueForDeletion(ctx context.Context, deadline time.Time, limit int) ([]uint, error)
+	AnonymizeUser(ctx context.Context, userId uint) error
+	EnableTotp(ctx context.Context, userId uint, secret string, codeHashes []string) error
+	DisableTotp(ctx context.Context, userId uint) error
+	UseRecoveryCode(ctx context.Context, userId uint, codeHash string) (bool, error)
+	CountRecoveryCode(ctx context.Context, userId uint) (int64, error)
+	UpdateMutedUntil(ctx context.Context, userId uint, until *time.Time) error
+	UpdateBannedUntil(ctx context.Context, userId uint, until *time.Time) error
 }
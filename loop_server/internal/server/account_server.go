@@ -0,0 +1,10 @@
+package server
+
+import "github.com/gin-gonic/gin"
+
+type AccountServer interface {
+	DeleteAccount(c *gin.Context)
+	CreateExport(c *gin.Context)
+	GetExport(c *gin.Context)
+	DownloadExport(c *gin.Context)
+}
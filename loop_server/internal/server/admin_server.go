@@ -0,0 +1,26 @@
+package server
+
+import "github.com/gin-gonic/gin"
+
+type AdminServer interface {
+	Login(c *gin.Context)
+	Logout(c *gin.Context)
+	GetOperatorList(c *gin.Context)
+	CreateOperator(c *gin.Context)
+	UpdateOperator(c *gin.Context)
+	QueryUser(c *gin.Context)
+	BanUser(c *gin.Context)
+	UnbanUser(c *gin.Context)
+	ForceLogout(c *gin.Context)
+	GetGroup(c *gin.Context)
+	GetGroupMessage(c *gin.Context)
+	GetOnlineStat(c *gin.Context)
+	GetSfuRooms(c *gin.Context)
+	GetAuditLogList(c *gin.Context)
+	UnlockUser(c *gin.Context)
+	GetModerationReviewList(c *gin.Context)
+	ResolveModerationReview(c *gin.Context)
+	GetReportList(c *gin.Context)
+	GetReportInfo(c *gin.Context)
+	HandleReport(c *gin.Context)
+}
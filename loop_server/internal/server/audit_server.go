@@ -0,0 +1,7 @@
+package server
+
+import "github.com/gin-gonic/gin"
+
+type AuditServer interface {
+	GetAuditLogList(c *gin.Context)
+}
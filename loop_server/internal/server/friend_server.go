@@ -4,10 +4,23 @@ import "github.com/gin-gonic/gin"
 
 type FriendServer interface {
 	DisposeFriendRequest(c *gin.Context)
+	WithdrawFriendRequest(c *gin.Context)
 	GetFriendRequestList(c *gin.Context)
 	GetFriendList(c *gin.Context)
 	AddFriend(c *gin.Context)
 	DeleteFriend(c *gin.Context)
 	FriendListStatistics(c *gin.Context)
 	GetFriendListByGroupId(c *gin.Context)
+	BlockUser(c *gin.Context)
+	UnblockUser(c *gin.Context)
+	GetBlockList(c *gin.Context)
+	UpdateFriendRemark(c *gin.Context)
+	UpdateFriendTags(c *gin.Context)
+	MoveFriendCategory(c *gin.Context)
+	GetFriendCategoryList(c *gin.Context)
+	CreateFriendCategory(c *gin.Context)
+	UpdateFriendCategory(c *gin.Context)
+	DeleteFriendCategory(c *gin.Context)
+	SortFriendCategory(c *gin.Context)
+	GetFriendRecommend(c *gin.Context)
 }
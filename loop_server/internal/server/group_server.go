@@ -16,4 +16,14 @@ type GroupServer interface {
 	GetGroupMemberListByLessRole(c *gin.Context)
 	ExitGroup(c *gin.Context)
 	TransferGroupOwner(c *gin.Context)
+	UpdateGroupNickname(c *gin.Context)
+	UpdateGroupRemark(c *gin.Context)
+	UpgradeGroup(c *gin.Context)
+	SearchGroup(c *gin.Context)
+	UpdateGroupDiscovery(c *gin.Context)
+	JoinGroup(c *gin.Context)
+	GetGroupJoinRequestList(c *gin.Context)
+	DisposeGroupJoinRequest(c *gin.Context)
+	GetGroupPermission(c *gin.Context)
+	UpdateGroupPermission(c *gin.Context)
 }
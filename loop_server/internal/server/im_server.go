@@ -7,4 +7,5 @@ type ImServer interface {
 	GetOfflineMessage(c *gin.Context)
 	SubmitOfflineMessage(c *gin.Context)
 	GetLocalTime(c *gin.Context)
+	SyncGroupMessage(c *gin.Context)
 }
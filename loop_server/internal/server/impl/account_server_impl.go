@@ -0,0 +1,84 @@
+package impl
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"loop_server/infra/consts"
+	"loop_server/internal/application"
+	"loop_server/internal/model/param"
+	"loop_server/pkg/response"
+	"path/filepath"
+)
+
+type accountServerImpl struct {
+	account application.AccountApp
+}
+
+func NewAccountServerImpl(account application.AccountApp) *accountServerImpl {
+	return &accountServerImpl{
+		account: account,
+	}
+}
+
+// DeleteAccount 申请注销账号
+func (a *accountServerImpl) DeleteAccount(c *gin.Context) {
+	var p param.DeleteAccountRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	data, err := a.account.DeleteAccount(c, p.Password)
+	if err != nil {
+		if errors.Is(err, consts.ErrPasswordError) {
+			response.Fail(c, response.CodePasswordError)
+			return
+		}
+		slog.Error("internal/server/impl/account_server_impl.go DeleteAccount err:", "err", err)
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, data)
+}
+
+// CreateExport 申请导出个人数据
+func (a *accountServerImpl) CreateExport(c *gin.Context) {
+	data, err := a.account.CreateExport(c)
+	if err != nil {
+		a.failAccountExport(c, err)
+		return
+	}
+	response.Success(c, data)
+}
+
+// GetExport 查询导出进度
+func (a *accountServerImpl) GetExport(c *gin.Context) {
+	data, err := a.account.GetExport(c)
+	if err != nil {
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, data)
+}
+
+// DownloadExport 下载导出文件
+func (a *accountServerImpl) DownloadExport(c *gin.Context) {
+	path, err := a.account.GetExportFile(c)
+	if err != nil {
+		a.failAccountExport(c, err)
+		return
+	}
+	c.FileAttachment(path, filepath.Base(path))
+}
+
+func (a *accountServerImpl) failAccountExport(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, consts.ErrAccountExportPending):
+		response.Fail(c, response.CodeAccountExportPending)
+	case errors.Is(err, consts.ErrAccountExportNotExist):
+		response.Fail(c, response.CodeAccountExportNotExist)
+	default:
+		slog.Error("internal/server/impl/account_server_impl.go account export err:", "err", err)
+		response.Fail(c, response.CodeServerBusy)
+	}
+}
@@ -0,0 +1,353 @@
+package impl
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"loop_server/infra/consts"
+	"loop_server/internal/application"
+	"loop_server/internal/model/param"
+	"loop_server/pkg/response"
+	"strings"
+)
+
+type adminServerImpl struct {
+	admin application.AdminApp
+}
+
+func NewAdminServerImpl(admin application.AdminApp) *adminServerImpl {
+	return &adminServerImpl{
+		admin: admin,
+	}
+}
+
+// Login 操作员登录
+func (a *adminServerImpl) Login(c *gin.Context) {
+	var p param.OperatorLoginRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	data, err := a.admin.Login(c, p.Username, p.Password, p.CaptchaToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, consts.ErrOperatorLoginFailed):
+			response.Fail(c, response.CodeOperatorLoginFailed)
+		case errors.Is(err, consts.ErrAccountLocked):
+			response.Fail(c, response.CodeAccountLocked)
+		case errors.Is(err, consts.ErrLoginTooFrequent):
+			response.Fail(c, response.CodeLoginTooFrequent)
+		case errors.Is(err, consts.ErrCaptchaRequired):
+			response.Fail(c, response.CodeCaptchaRequired)
+		case errors.Is(err, consts.ErrCaptchaInvalid):
+			response.Fail(c, response.CodeCaptchaInvalid)
+		default:
+			slog.Error("internal/server/impl/admin_server_impl.go Login err:", "err", err)
+			response.Fail(c, response.CodeServerBusy)
+		}
+		return
+	}
+	response.Success(c, data)
+}
+
+// Logout 注销当前操作员会话
+func (a *adminServerImpl) Logout(c *gin.Context) {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if err := a.admin.Logout(c, token); err != nil {
+		slog.Error("internal/server/impl/admin_server_impl.go Logout err:", "err", err)
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// GetOperatorList 操作员列表
+func (a *adminServerImpl) GetOperatorList(c *gin.Context) {
+	data, err := a.admin.GetOperatorList(c)
+	if err != nil {
+		slog.Error("internal/server/impl/admin_server_impl.go GetOperatorList err:", "err", err)
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, data)
+}
+
+// CreateOperator 创建操作员
+func (a *adminServerImpl) CreateOperator(c *gin.Context) {
+	var p param.CreateOperatorRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	data, err := a.admin.CreateOperator(c, &p)
+	if err != nil {
+		a.failAdmin(c, err)
+		return
+	}
+	response.Success(c, data)
+}
+
+// UpdateOperator 修改操作员角色、状态或重置密码
+func (a *adminServerImpl) UpdateOperator(c *gin.Context) {
+	var p param.UpdateOperatorRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := a.admin.UpdateOperator(c, &p); err != nil {
+		a.failAdmin(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// QueryUser 按 id 或手机号查询用户
+func (a *adminServerImpl) QueryUser(c *gin.Context) {
+	var p param.AdminQueryUserRequest
+	if err := c.ShouldBind(&p); err != nil || (p.UserId == 0 && p.Phone == "") {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	data, err := a.admin.QueryUser(c, p.UserId, p.Phone)
+	if err != nil {
+		a.failAdmin(c, err)
+		return
+	}
+	response.Success(c, data)
+}
+
+// BanUser 封禁用户
+func (a *adminServerImpl) BanUser(c *gin.Context) {
+	var p param.AdminBanUserRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := a.admin.BanUser(c, &p); err != nil {
+		a.failAdmin(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// UnbanUser 解除封禁
+func (a *adminServerImpl) UnbanUser(c *gin.Context) {
+	var p param.AdminUserRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := a.admin.UnbanUser(c, &p); err != nil {
+		a.failAdmin(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// ForceLogout 强制用户下线
+func (a *adminServerImpl) ForceLogout(c *gin.Context) {
+	var p param.AdminUserRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := a.admin.ForceLogout(c, &p); err != nil {
+		a.failAdmin(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// GetGroup 群信息及成员
+func (a *adminServerImpl) GetGroup(c *gin.Context) {
+	var p param.AdminGroupRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	data, err := a.admin.GetGroup(c, p.GroupId)
+	if err != nil {
+		a.failAdmin(c, err)
+		return
+	}
+	response.Success(c, data)
+}
+
+// GetGroupMessage 按 seq_id 查询群消息
+func (a *adminServerImpl) GetGroupMessage(c *gin.Context) {
+	var p param.AdminGroupMessageRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	data, err := a.admin.GetGroupMessage(c, p.SeqId)
+	if err != nil {
+		a.failAdmin(c, err)
+		return
+	}
+	response.Success(c, data)
+}
+
+// GetOnlineStat 在线用户数
+func (a *adminServerImpl) GetOnlineStat(c *gin.Context) {
+	data, err := a.admin.GetOnlineStat(c)
+	if err != nil {
+		slog.Error("internal/server/impl/admin_server_impl.go GetOnlineStat err:", "err", err)
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, data)
+}
+
+// GetSfuRooms 进行中的音视频房间
+func (a *adminServerImpl) GetSfuRooms(c *gin.Context) {
+	response.Success(c, a.admin.GetSfuRooms(c))
+}
+
+// GetAuditLogList 审计日志
+func (a *adminServerImpl) GetAuditLogList(c *gin.Context) {
+	var p param.AdminAuditLogRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	p.Init()
+	data, err := a.admin.GetAuditLogList(c, &p)
+	if err != nil {
+		slog.Error("internal/server/impl/admin_server_impl.go GetAuditLogList err:", "err", err)
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, data)
+}
+
+func (a *adminServerImpl) failAdmin(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, consts.ErrOperatorExist):
+		response.Fail(c, response.CodeOperatorExist)
+	case errors.Is(err, consts.ErrOperatorNotExist):
+		response.Fail(c, response.CodeOperatorNotExist)
+	case errors.Is(err, consts.ErrNoPermission):
+		response.Fail(c, response.CodeNoPermission)
+	case errors.Is(err, consts.ErrUserNotExist):
+		response.Fail(c, response.CodeUserNotExist)
+	case errors.Is(err, consts.ErrGroupNotExist):
+		response.Fail(c, response.CodeGroupNotExist)
+	case errors.Is(err, consts.ErrMessageNotExist):
+		response.Fail(c, response.CodeMessageNotExist)
+	default:
+		slog.Error("internal/server/impl/admin_server_impl.go failAdmin err:", "err", err)
+		response.Fail(c, response.CodeServerBusy)
+	}
+}
+
+// UnlockUser 解除登录锁定
+func (a *adminServerImpl) UnlockUser(c *gin.Context) {
+	var p param.AdminUnlockUserRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := a.admin.UnlockUser(c, p.Phone); err != nil {
+		slog.Error("internal/server/impl/admin_server_impl.go UnlockUser err:", "err", err)
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// GetModerationReviewList 审核队列
+func (a *adminServerImpl) GetModerationReviewList(c *gin.Context) {
+	var p param.ModerationReviewListRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	p.Init()
+	data, err := a.admin.GetModerationReviewList(c, p.Status, p.Offset(), p.PageSize)
+	if err != nil {
+		slog.Error("internal/server/impl/admin_server_impl.go GetModerationReviewList err:", "err", err)
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, data)
+}
+
+// ResolveModerationReview 处理审核内容
+func (a *adminServerImpl) ResolveModerationReview(c *gin.Context) {
+	var p param.ResolveModerationReviewRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := a.admin.ResolveModerationReview(c, p.Id, p.Status); err != nil {
+		if errors.Is(err, consts.ErrModerationReviewNotExist) {
+			response.Fail(c, response.CodeModerationReviewNotExist)
+			return
+		}
+		slog.Error("internal/server/impl/admin_server_impl.go ResolveModerationReview err:", "err", err)
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// GetReportList 举报队列
+func (a *adminServerImpl) GetReportList(c *gin.Context) {
+	var p param.ReportListRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	p.Init()
+	data, err := a.admin.GetReportList(c, p.Status, p.TargetType, p.Offset(), p.PageSize)
+	if err != nil {
+		slog.Error("internal/server/impl/admin_server_impl.go GetReportList err:", "err", err)
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, data)
+}
+
+// GetReportInfo 举报详情及处理记录
+func (a *adminServerImpl) GetReportInfo(c *gin.Context) {
+	var p param.ReportInfoRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	data, err := a.admin.GetReportInfo(c, p.Id)
+	if err != nil {
+		a.failReport(c, err)
+		return
+	}
+	response.Success(c, data)
+}
+
+// HandleReport 处理举报
+func (a *adminServerImpl) HandleReport(c *gin.Context) {
+	var p param.HandleReportRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := a.admin.HandleReport(c, &p); err != nil {
+		a.failReport(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (a *adminServerImpl) failReport(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, consts.ErrReportNotExist):
+		response.Fail(c, response.CodeReportNotExist)
+	case errors.Is(err, consts.ErrReportActionInvalid):
+		response.Fail(c, response.CodeReportActionInvalid)
+	case errors.Is(err, consts.ErrReportTargetNotExist):
+		response.Fail(c, response.CodeReportTargetNotExist)
+	default:
+		slog.Error("internal/server/impl/admin_server_impl.go failReport err:", "err", err)
+		response.Fail(c, response.CodeServerBusy)
+	}
+}
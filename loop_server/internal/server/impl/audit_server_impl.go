@@ -0,0 +1,36 @@
+package impl
+
+import (
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"loop_server/internal/application"
+	"loop_server/internal/model/param"
+	"loop_server/pkg/response"
+)
+
+type auditServerImpl struct {
+	audit application.AuditApp
+}
+
+func NewAuditServerImpl(audit application.AuditApp) *auditServerImpl {
+	return &auditServerImpl{
+		audit: audit,
+	}
+}
+
+// GetAuditLogList 当前用户的安全事件
+func (a *auditServerImpl) GetAuditLogList(c *gin.Context) {
+	var p param.AuditLogRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	p.Init()
+	data, err := a.audit.GetAuditLogList(c, &p)
+	if err != nil {
+		slog.Error("internal/server/impl/audit_server_impl.go GetAuditLogList err:", "err", err)
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, data)
+}
@@ -1,7 +1,9 @@
 package impl
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
+	"loop_server/infra/consts"
 	"loop_server/internal/application"
 	"loop_server/internal/model/param"
 	"loop_server/pkg/response"
@@ -25,6 +27,27 @@ func (f *friendServerImpl) DisposeFriendRequest(c *gin.Context) {
 	}
 	err := f.friend.DisposeFriendRequest(c, p.RequesterId, p.Status)
 	if err != nil {
+		if errors.Is(err, consts.ErrFriendRequestNotExist) {
+			response.Fail(c, response.CodeFriendRequestNotExist)
+			return
+		}
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (f *friendServerImpl) WithdrawFriendRequest(c *gin.Context) {
+	var p param.WithdrawFriendRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := f.friend.WithdrawFriendRequest(c, p.RecipientId); err != nil {
+		if errors.Is(err, consts.ErrFriendRequestNotExist) {
+			response.Fail(c, response.CodeFriendRequestNotExist)
+			return
+		}
 		response.Fail(c, response.CodeServerBusy)
 		return
 	}
@@ -58,7 +81,18 @@ func (f *friendServerImpl) AddFriend(c *gin.Context) {
 	}
 
 	if err := f.friend.AddFriend(c, p.FriendId, p.Message); err != nil {
-		response.Fail(c, response.CodeServerBusy)
+		switch {
+		case errors.Is(err, consts.ErrFriendRequestPending):
+			response.Fail(c, response.CodeFriendRequestPending)
+		case errors.Is(err, consts.ErrFriendRequestCooldown):
+			response.Fail(c, response.CodeFriendRequestCooldown)
+		case errors.Is(err, consts.ErrFriendRequestLimit):
+			response.Fail(c, response.CodeFriendRequestLimit)
+		case errors.Is(err, consts.ErrFriendRequestForbidden):
+			response.Fail(c, response.CodeFriendRequestForbidden)
+		default:
+			response.Fail(c, response.CodeServerBusy)
+		}
 		return
 	}
 	response.Success(c, nil)
@@ -100,3 +134,164 @@ func (f *friendServerImpl) GetFriendListByGroupId(c *gin.Context) {
 	}
 	response.Success(c, data)
 }
+
+func (f *friendServerImpl) BlockUser(c *gin.Context) {
+	var p param.BlockUserRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := f.friend.BlockUser(c, p.UserId); err != nil {
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (f *friendServerImpl) UnblockUser(c *gin.Context) {
+	var p param.BlockUserRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := f.friend.UnblockUser(c, p.UserId); err != nil {
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (f *friendServerImpl) GetBlockList(c *gin.Context) {
+	list, err := f.friend.GetBlockList(c)
+	if err != nil {
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, list)
+}
+
+func (f *friendServerImpl) UpdateFriendRemark(c *gin.Context) {
+	var p param.UpdateFriendRemarkRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := f.friend.UpdateFriendRemark(c, p.FriendId, p.Remark); err != nil {
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (f *friendServerImpl) UpdateFriendTags(c *gin.Context) {
+	var p param.UpdateFriendTagsRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := f.friend.UpdateFriendTags(c, p.FriendId, p.Tags); err != nil {
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (f *friendServerImpl) MoveFriendCategory(c *gin.Context) {
+	var p param.MoveFriendCategoryRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := f.friend.MoveFriendCategory(c, p.FriendIds, p.CategoryId); err != nil {
+		f.failFriendCategory(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (f *friendServerImpl) GetFriendCategoryList(c *gin.Context) {
+	list, err := f.friend.GetFriendCategoryList(c)
+	if err != nil {
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, list)
+}
+
+func (f *friendServerImpl) CreateFriendCategory(c *gin.Context) {
+	var p param.CreateFriendCategoryRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	data, err := f.friend.CreateFriendCategory(c, p.Name)
+	if err != nil {
+		f.failFriendCategory(c, err)
+		return
+	}
+	response.Success(c, data)
+}
+
+func (f *friendServerImpl) UpdateFriendCategory(c *gin.Context) {
+	var p param.UpdateFriendCategoryRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := f.friend.UpdateFriendCategory(c, p.CategoryId, p.Name); err != nil {
+		f.failFriendCategory(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (f *friendServerImpl) DeleteFriendCategory(c *gin.Context) {
+	var p param.DeleteFriendCategoryRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := f.friend.DeleteFriendCategory(c, p.CategoryId); err != nil {
+		f.failFriendCategory(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (f *friendServerImpl) SortFriendCategory(c *gin.Context) {
+	var p param.SortFriendCategoryRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := f.friend.SortFriendCategory(c, p.CategoryIds); err != nil {
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (f *friendServerImpl) GetFriendRecommend(c *gin.Context) {
+	var p param.FriendRecommendRequest
+	if err := c.ShouldBindQuery(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	list, err := f.friend.GetFriendRecommend(c, p.Limit)
+	if err != nil {
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, list)
+}
+
+func (f *friendServerImpl) failFriendCategory(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, consts.ErrFriendCategoryNotExist):
+		response.Fail(c, response.CodeFriendCategoryNotExist)
+	case errors.Is(err, consts.ErrFriendCategoryLimit):
+		response.Fail(c, response.CodeFriendCategoryLimit)
+	default:
+		response.Fail(c, response.CodeServerBusy)
+	}
+}
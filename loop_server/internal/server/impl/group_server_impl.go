@@ -33,6 +33,10 @@ func (g *groupServerImpl) CreateGroup(c *gin.Context) {
 			response.Fail(c, response.CodePartUserNotExist)
 			return
 		}
+		if errors.Is(err, consts.ErrGroupFull) {
+			response.Fail(c, response.CodeGroupFull)
+			return
+		}
 		response.Fail(c, response.CodeServerBusy)
 		return
 	}
@@ -53,6 +57,10 @@ func (g *groupServerImpl) UpdateGroup(c *gin.Context) {
 			response.Fail(c, response.CodeNoPermission)
 			return
 		}
+		if errors.Is(err, consts.ErrContentRejected) {
+			response.Fail(c, response.CodeContentRejected)
+			return
+		}
 		response.Fail(c, response.CodeServerBusy)
 		return
 	}
@@ -66,12 +74,16 @@ func (g *groupServerImpl) DeleteGroup(c *gin.Context) {
 		response.Fail(c, response.CodeInvalidParam)
 		return
 	}
-	err := g.group.DeleteGroup(c, p.GroupId)
+	err := g.group.DeleteGroup(c, p.GroupId, p.Confirm)
 	if err != nil {
 		if errors.Is(err, consts.ErrNoPermission) {
 			response.Fail(c, response.CodeNoPermission)
 			return
 		}
+		if errors.Is(err, consts.ErrGroupConfirmMismatch) {
+			response.Fail(c, response.CodeGroupConfirmMismatch)
+			return
+		}
 		response.Fail(c, response.CodeServerBusy)
 		return
 	}
@@ -128,6 +140,10 @@ func (g *groupServerImpl) AddMember(c *gin.Context) {
 			response.Fail(c, response.CodeNoPermission)
 			return
 		}
+		if errors.Is(err, consts.ErrGroupFull) {
+			response.Fail(c, response.CodeGroupFull)
+			return
+		}
 		response.Fail(c, response.CodeServerBusy)
 		return
 	}
@@ -181,6 +197,10 @@ func (g *groupServerImpl) GroupInfo(c *gin.Context) {
 	}
 	group, err := g.group.GetGroup(c, input.GroupId)
 	if err != nil {
+		if errors.Is(err, consts.ErrNoPermission) {
+			response.Fail(c, response.CodeNoPermission)
+			return
+		}
 		response.Fail(c, response.CodeServerBusy)
 		return
 	}
@@ -195,6 +215,10 @@ func (g *groupServerImpl) GetGroupMemberList(c *gin.Context) {
 	}
 	members, err := g.group.GetGroupMemberList(c, input.GroupId)
 	if err != nil {
+		if errors.Is(err, consts.ErrNoPermission) {
+			response.Fail(c, response.CodeNoPermission)
+			return
+		}
 		response.Fail(c, response.CodeServerBusy)
 		return
 	}
@@ -209,6 +233,10 @@ func (g *groupServerImpl) GetGroupMemberListByLessRole(c *gin.Context) {
 	}
 	members, err := g.group.GetGroupMemberListByLessRole(c, input.GroupId)
 	if err != nil {
+		if errors.Is(err, consts.ErrNoPermission) {
+			response.Fail(c, response.CodeNoPermission)
+			return
+		}
 		response.Fail(c, response.CodeServerBusy)
 		return
 	}
@@ -223,6 +251,10 @@ func (g *groupServerImpl) ExitGroup(c *gin.Context) {
 	}
 	err := g.group.ExitGroup(c, input.GroupId)
 	if err != nil {
+		if errors.Is(err, consts.ErrNoPermission) {
+			response.Fail(c, response.CodeNoPermission)
+			return
+		}
 		response.Fail(c, response.CodeServerBusy)
 		return
 	}
@@ -237,6 +269,206 @@ func (g *groupServerImpl) TransferGroupOwner(c *gin.Context) {
 	}
 	err := g.group.TransferGroupOwner(c, input.GroupId, input.UserId)
 	if err != nil {
+		if errors.Is(err, consts.ErrNoPermission) {
+			response.Fail(c, response.CodeNoPermission)
+			return
+		}
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (g *groupServerImpl) GetGroupPermission(c *gin.Context) {
+	input := &param.GroupId{}
+	if err := c.ShouldBind(input); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	data, err := g.group.GetGroupPermission(c, input.GroupId)
+	if err != nil {
+		if errors.Is(err, consts.ErrNoPermission) {
+			response.Fail(c, response.CodeNoPermission)
+			return
+		}
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, data)
+}
+
+func (g *groupServerImpl) UpdateGroupPermission(c *gin.Context) {
+	input := &param.UpdateGroupPermissionRequest{}
+	if err := c.ShouldBind(input); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	err := g.group.UpdateGroupPermission(c, input.GroupId, input.Permission)
+	if err != nil {
+		if errors.Is(err, consts.ErrNoPermission) {
+			response.Fail(c, response.CodeNoPermission)
+			return
+		}
+		if errors.Is(err, consts.ErrInvalidPermission) {
+			response.Fail(c, response.CodeInvalidParam)
+			return
+		}
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (g *groupServerImpl) UpdateGroupNickname(c *gin.Context) {
+	input := &param.UpdateGroupNicknameRequest{}
+	if err := c.ShouldBind(input); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	err := g.group.UpdateGroupNickname(c, input.GroupId, input.Nickname)
+	if err != nil {
+		if errors.Is(err, consts.ErrNoPermission) {
+			response.Fail(c, response.CodeNoPermission)
+			return
+		}
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (g *groupServerImpl) UpdateGroupRemark(c *gin.Context) {
+	input := &param.UpdateGroupRemarkRequest{}
+	if err := c.ShouldBind(input); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	err := g.group.UpdateGroupRemark(c, input.GroupId, input.Remark)
+	if err != nil {
+		if errors.Is(err, consts.ErrNoPermission) {
+			response.Fail(c, response.CodeNoPermission)
+			return
+		}
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (g *groupServerImpl) UpgradeGroup(c *gin.Context) {
+	input := &param.UpgradeGroupRequest{}
+	if err := c.ShouldBind(input); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	err := g.group.UpgradeGroup(c, input.GroupId, input.Type)
+	if err != nil {
+		if errors.Is(err, consts.ErrNoPermission) {
+			response.Fail(c, response.CodeNoPermission)
+			return
+		}
+		if errors.Is(err, consts.ErrInvalidGroupType) {
+			response.Fail(c, response.CodeInvalidParam)
+			return
+		}
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (g *groupServerImpl) SearchGroup(c *gin.Context) {
+	input := &param.SearchGroupRequest{}
+	if err := c.ShouldBind(input); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	data, err := g.group.SearchGroup(c, input)
+	if err != nil {
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, data)
+}
+
+func (g *groupServerImpl) UpdateGroupDiscovery(c *gin.Context) {
+	input := &param.UpdateGroupDiscoveryRequest{}
+	if err := c.ShouldBind(input); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	err := g.group.UpdateGroupDiscovery(c, input)
+	if err != nil {
+		if errors.Is(err, consts.ErrNoPermission) {
+			response.Fail(c, response.CodeNoPermission)
+			return
+		}
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (g *groupServerImpl) JoinGroup(c *gin.Context) {
+	input := &param.JoinGroupRequest{}
+	if err := c.ShouldBind(input); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	joined, err := g.group.JoinGroup(c, input.GroupId, input.Message)
+	if err != nil {
+		if errors.Is(err, consts.ErrGroupJoinForbidden) {
+			response.Fail(c, response.CodeGroupJoinForbidden)
+			return
+		}
+		if errors.Is(err, consts.ErrGroupFull) {
+			response.Fail(c, response.CodeGroupFull)
+			return
+		}
+		if errors.Is(err, consts.ErrGroupJoinRequestCooldown) {
+			response.Fail(c, response.CodeGroupJoinRequestCooldown)
+			return
+		}
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, gin.H{"joined": joined})
+}
+
+func (g *groupServerImpl) GetGroupJoinRequestList(c *gin.Context) {
+	input := &param.GroupId{}
+	if err := c.ShouldBind(input); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	data, err := g.group.GetGroupJoinRequestList(c, input.GroupId)
+	if err != nil {
+		if errors.Is(err, consts.ErrNoPermission) {
+			response.Fail(c, response.CodeNoPermission)
+			return
+		}
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, data)
+}
+
+func (g *groupServerImpl) DisposeGroupJoinRequest(c *gin.Context) {
+	input := &param.DisposeGroupJoinRequest{}
+	if err := c.ShouldBind(input); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	err := g.group.DisposeGroupJoinRequest(c, input.GroupId, input.UserId, input.Status)
+	if err != nil {
+		if errors.Is(err, consts.ErrNoPermission) {
+			response.Fail(c, response.CodeNoPermission)
+			return
+		}
+		if errors.Is(err, consts.ErrGroupFull) {
+			response.Fail(c, response.CodeGroupFull)
+			return
+		}
 		response.Fail(c, response.CodeServerBusy)
 		return
 	}
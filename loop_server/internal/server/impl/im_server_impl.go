@@ -1,12 +1,15 @@
 package impl
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"log/slog"
+	"loop_server/infra/consts"
 	"loop_server/infra/ws"
 	"loop_server/internal/application"
 	"loop_server/internal/model/dto"
+	"loop_server/internal/model/param"
 	"loop_server/pkg/request"
 	"loop_server/pkg/response"
 	"sync"
@@ -23,6 +26,7 @@ func NewImServerImpl(im application.ImApp) *imServerImpl {
 	}
 }
 
+// WsHandler 建立连接后首帧必须是认证帧，超时或认证失败直接断开
 func (i *imServerImpl) WsHandler(c *gin.Context) {
 
 	conn, err := ws.Upgrade.Upgrade(c.Writer, c.Request, nil)
@@ -32,24 +36,72 @@ func (i *imServerImpl) WsHandler(c *gin.Context) {
 	}
 	defer conn.Close()
 
-	if err != nil {
-		slog.Error("parse token err:", err)
-		return
-	}
-	userId := request.GetCurrentUser(c)
 	client := &ws.Client{
-		UserId: userId,
-		Conn:   conn,
-		Mu:     &sync.Mutex{},
+		Conn: conn,
+		Mu:   &sync.Mutex{},
+	}
+	if err := i.authenticate(c, client); err != nil {
+		client.CloseWithReason(consts.WsCloseUnauthorized, consts.WsErrorReasonUnauthorized)
+		return
 	}
+	c.Set(request.CtxUserIDKey, client.UserId)
+	c.Set(request.CtxSessionIDKey, client.SessionId)
 
 	i.im.AddOnlineUser(c, client)
-	defer i.im.RemoveOnlineUser(c, userId)
+	defer i.im.RemoveOnlineUser(c, client.UserId, client)
+
+	done := make(chan struct{})
+	defer close(done)
+	go i.watchExpiry(c, client, done)
 
 	i.messageListener(c, client)
 
 }
 
+// authenticate 等待并校验首个认证帧
+func (i *imServerImpl) authenticate(c *gin.Context, client *ws.Client) error {
+	client.Conn.SetReadDeadline(time.Now().Add(consts.WsAuthTimeout))
+	_, msgByte, err := client.Conn.ReadMessage()
+	if err != nil {
+		return err
+	}
+	if err := i.im.Authenticate(c, client, msgByte); err != nil {
+		return err
+	}
+	return client.Conn.SetReadDeadline(time.Time{})
+}
+
+// watchExpiry access token 过期前要求客户端重新认证，过期后超过认证等待时间仍未重新认证则断开连接
+func (i *imServerImpl) watchExpiry(c *gin.Context, client *ws.Client, done <-chan struct{}) {
+	var requested time.Time
+	for {
+		expiresAt := client.ExpiresAt()
+		next := expiresAt.Add(-consts.WsReauthAhead)
+		if requested.Equal(expiresAt) {
+			next = expiresAt.Add(consts.WsAuthTimeout)
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-done:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !client.ExpiresAt().Equal(expiresAt) {
+			continue
+		}
+		if !requested.Equal(expiresAt) {
+			requested = expiresAt
+			i.im.RequestReauth(c, client)
+			continue
+		}
+		slog.Info("websocket reauth timeout, close connection", "user_id", client.UserId, "session_id", client.SessionId)
+		client.CloseWithReason(consts.WsCloseUnauthorized, consts.WsErrorReasonTokenExpired)
+		return
+	}
+}
+
 func (i *imServerImpl) messageListener(c *gin.Context, client *ws.Client) {
 	for {
 		_, msgByte, err := client.Conn.ReadMessage()
@@ -59,7 +111,7 @@ func (i *imServerImpl) messageListener(c *gin.Context, client *ws.Client) {
 			}
 			break
 		}
-		i.im.HandleMessage(c, client.UserId, msgByte)
+		i.im.HandleMessage(c, client, msgByte)
 	}
 }
 
@@ -95,3 +147,26 @@ func (i *imServerImpl) GetLocalTime(c *gin.Context) {
 	res.Time = time.Now().UnixMilli()
 	response.Success(c, res)
 }
+
+func (i *imServerImpl) SyncGroupMessage(c *gin.Context) {
+	input := &param.SyncGroupMessage{}
+	if err := c.ShouldBind(input); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	messages, err := i.im.SyncGroupMessage(c, request.GetCurrentUser(c), input.GroupId, input.SeqId, input.Limit)
+	if err != nil {
+		if errors.Is(err, consts.ErrNoPermission) {
+			response.Fail(c, response.CodeNoPermission)
+			return
+		}
+		if errors.Is(err, consts.ErrMessageNotExist) {
+			response.Fail(c, response.CodeMessageNotExist)
+			return
+		}
+		slog.Error("sync group message err:", "err", err)
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, messages)
+}
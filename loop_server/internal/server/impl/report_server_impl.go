@@ -0,0 +1,43 @@
+package impl
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"loop_server/infra/consts"
+	"loop_server/internal/application"
+	"loop_server/internal/model/param"
+	"loop_server/pkg/response"
+)
+
+type reportServerImpl struct {
+	report application.ReportApp
+}
+
+func NewReportServerImpl(report application.ReportApp) *reportServerImpl {
+	return &reportServerImpl{
+		report: report,
+	}
+}
+
+// CreateReport 举报用户、群或消息
+func (r *reportServerImpl) CreateReport(c *gin.Context) {
+	var p param.CreateReportRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := r.report.CreateReport(c, &p); err != nil {
+		switch {
+		case errors.Is(err, consts.ErrReportTargetNotExist):
+			response.Fail(c, response.CodeReportTargetNotExist)
+		case errors.Is(err, consts.ErrReportPending):
+			response.Fail(c, response.CodeReportPending)
+		default:
+			slog.Error("internal/server/impl/report_server_impl.go CreateReport err:", "err", err)
+			response.Fail(c, response.CodeServerBusy)
+		}
+		return
+	}
+	response.Success(c, nil)
+}
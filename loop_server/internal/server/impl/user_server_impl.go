@@ -1,14 +1,17 @@
 package impl
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	_ "github.com/gin-gonic/gin"
 	"loop_server/infra/consts"
 	"loop_server/internal/application"
 	"loop_server/internal/model/dto"
 	"loop_server/internal/model/param"
+	"loop_server/pkg/jwt"
 	"loop_server/pkg/request"
 	"loop_server/pkg/response"
+	"net/http"
 	"strings"
 )
 
@@ -30,9 +33,9 @@ func (u *userServerImpl) Login(c *gin.Context) {
 		return
 	}
 
-	user, err := u.user.Login(c, p.Phone, p.Password)
+	user, err := u.user.Login(c, p.Phone, p.Password, p.CaptchaToken)
 	if err != nil {
-		response.Fail(c, response.CodeServerBusy)
+		u.failLogin(c, err)
 		return
 	}
 	if user == nil {
@@ -56,17 +59,233 @@ func (u *userServerImpl) Register(c *gin.Context) {
 		Avatar:   consts.GetDefaultAvatar(),
 	}
 
-	if err := u.user.Register(c, user); err != nil {
+	if err := u.user.Register(c, user, p.Code); err != nil {
 		if strings.Contains(err.Error(), consts.Duplicate) {
 			response.Fail(c, response.CodePhoneExist)
 		} else {
-			response.Fail(c, response.CodeServerBusy)
+			u.failVerify(c, err)
 		}
 		return
 	}
 	response.Success(c, nil)
 }
 
+// SendVerifyCode 发送手机验证码
+func (u *userServerImpl) SendVerifyCode(c *gin.Context) {
+	var p param.SendVerifyCodeRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := u.user.SendVerifyCode(c, p.Scene, p.Phone); err != nil {
+		u.failVerify(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// LoginByCode 验证码登录
+func (u *userServerImpl) LoginByCode(c *gin.Context) {
+	var p param.LoginByCodeRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	user, err := u.user.LoginByCode(c, p.Phone, p.Code)
+	if err != nil {
+		u.failVerify(c, err)
+		return
+	}
+	response.Success(c, user)
+}
+
+// ResetPassword 通过验证码重置密码
+func (u *userServerImpl) ResetPassword(c *gin.Context) {
+	var p param.ResetPasswordRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := u.user.ResetPassword(c, p.Phone, p.Code, p.Password); err != nil {
+		u.failVerify(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// CompleteLogin 两步验证登录
+func (u *userServerImpl) CompleteLogin(c *gin.Context) {
+	var p param.CompleteLoginRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	user, err := u.user.CompleteLogin(c, p.ChallengeToken, p.Code, p.CaptchaToken)
+	if err != nil {
+		u.failTotp(c, err)
+		return
+	}
+	response.Success(c, user)
+}
+
+func (u *userServerImpl) GetTotpStatus(c *gin.Context) {
+	data, err := u.user.GetTotpStatus(c)
+	if err != nil {
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, data)
+}
+
+// EnrollTotp 获取两步验证绑定信息
+func (u *userServerImpl) EnrollTotp(c *gin.Context) {
+	data, err := u.user.EnrollTotp(c)
+	if err != nil {
+		u.failTotp(c, err)
+		return
+	}
+	response.Success(c, data)
+}
+
+// ConfirmTotp 确认绑定并开启两步验证，返回恢复码
+func (u *userServerImpl) ConfirmTotp(c *gin.Context) {
+	var p param.ConfirmTotpRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	codes, err := u.user.ConfirmTotp(c, p.Code)
+	if err != nil {
+		u.failTotp(c, err)
+		return
+	}
+	response.Success(c, gin.H{"recovery_codes": codes})
+}
+
+func (u *userServerImpl) DisableTotp(c *gin.Context) {
+	var p param.DisableTotpRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := u.user.DisableTotp(c, p.Password, p.Code); err != nil {
+		u.failTotp(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// ListSessions 已登录设备列表
+func (u *userServerImpl) ListSessions(c *gin.Context) {
+	data, err := u.user.ListSessions(c)
+	if err != nil {
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, data)
+}
+
+// RevokeSession 下线指定设备
+func (u *userServerImpl) RevokeSession(c *gin.Context) {
+	var p param.RevokeSessionRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	if err := u.user.RevokeSession(c, p.SessionId); err != nil {
+		if errors.Is(err, consts.ErrSessionNotExist) {
+			response.Fail(c, response.CodeSessionNotExist)
+			return
+		}
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// Logout 退出登录
+func (u *userServerImpl) Logout(c *gin.Context) {
+	if err := u.user.Logout(c); err != nil && !errors.Is(err, consts.ErrSessionNotExist) {
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// RevokeOtherSessions 下线其他全部设备
+func (u *userServerImpl) RevokeOtherSessions(c *gin.Context) {
+	if err := u.user.RevokeOtherSessions(c); err != nil {
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (u *userServerImpl) failTotp(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, consts.ErrAccountBanned):
+		response.Fail(c, response.CodeAccountBanned)
+	case errors.Is(err, consts.ErrPasswordError):
+		response.Fail(c, response.CodePasswordError)
+	case errors.Is(err, consts.ErrTwoFactorEnabled):
+		response.Fail(c, response.CodeTwoFactorEnabled)
+	case errors.Is(err, consts.ErrTwoFactorNotEnabled):
+		response.Fail(c, response.CodeTwoFactorNotEnabled)
+	case errors.Is(err, consts.ErrTwoFactorEnrollExpired):
+		response.Fail(c, response.CodeTwoFactorEnrollExpired)
+	case errors.Is(err, consts.ErrTwoFactorCodeInvalid):
+		response.Fail(c, response.CodeTwoFactorCodeInvalid)
+	case errors.Is(err, consts.ErrLoginChallengeInvalid):
+		response.Fail(c, response.CodeLoginChallengeInvalid)
+	case errors.Is(err, consts.ErrAccountLocked):
+		response.Fail(c, response.CodeAccountLocked)
+	case errors.Is(err, consts.ErrLoginTooFrequent):
+		response.Fail(c, response.CodeLoginTooFrequent)
+	case errors.Is(err, consts.ErrCaptchaRequired):
+		response.Fail(c, response.CodeCaptchaRequired)
+	case errors.Is(err, consts.ErrCaptchaInvalid):
+		response.Fail(c, response.CodeCaptchaInvalid)
+	default:
+		response.Fail(c, response.CodeServerBusy)
+	}
+}
+
+func (u *userServerImpl) failLogin(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, consts.ErrAccountBanned):
+		response.Fail(c, response.CodeAccountBanned)
+	case errors.Is(err, consts.ErrAccountLocked):
+		response.Fail(c, response.CodeAccountLocked)
+	case errors.Is(err, consts.ErrLoginTooFrequent):
+		response.Fail(c, response.CodeLoginTooFrequent)
+	case errors.Is(err, consts.ErrCaptchaRequired):
+		response.Fail(c, response.CodeCaptchaRequired)
+	case errors.Is(err, consts.ErrCaptchaInvalid):
+		response.Fail(c, response.CodeCaptchaInvalid)
+	default:
+		response.Fail(c, response.CodeServerBusy)
+	}
+}
+
+func (u *userServerImpl) failVerify(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, consts.ErrAccountBanned):
+		response.Fail(c, response.CodeAccountBanned)
+	case errors.Is(err, consts.ErrVerifyCodeInvalid):
+		response.Fail(c, response.CodeVerifyCodeInvalid)
+	case errors.Is(err, consts.ErrVerifyCodeTooFrequent):
+		response.Fail(c, response.CodeVerifyCodeTooFrequent)
+	case errors.Is(err, consts.ErrVerifyCodeLimit):
+		response.Fail(c, response.CodeVerifyCodeLimit)
+	case errors.Is(err, consts.ErrPhoneExist):
+		response.Fail(c, response.CodePhoneExist)
+	case errors.Is(err, consts.ErrPhoneNotExist):
+		response.Fail(c, response.CodePhoneNotExist)
+	default:
+		response.Fail(c, response.CodeServerBusy)
+	}
+}
+
 func (u *userServerImpl) QueryUser(c *gin.Context) {
 	var p param.QueryUserRequest
 	if err := c.ShouldBind(&p); err != nil {
@@ -99,6 +318,10 @@ func (u *userServerImpl) UpdateUserInfo(c *gin.Context) {
 		Age:       *p.Age,
 	})
 	if err != nil {
+		if errors.Is(err, consts.ErrContentRejected) {
+			response.Fail(c, response.CodeContentRejected)
+			return
+		}
 		response.Fail(c, response.CodeServerBusy)
 		return
 	}
@@ -131,10 +354,52 @@ func (u *userServerImpl) RefreshToken(c *gin.Context) {
 		response.Fail(c, response.CodeInvalidParam)
 		return
 	}
-	acccessToken, err := u.user.RefreshToken(c, p.RefreshToken)
+	data, err := u.user.RefreshToken(c, p.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, consts.ErrRefreshTokenReused):
+			response.Fail(c, response.CodeRefreshTokenReused)
+		case errors.Is(err, consts.ErrRefreshTokenInvalid):
+			response.Fail(c, response.CodeInvalidToken)
+		default:
+			response.Fail(c, response.CodeServerBusy)
+		}
+		return
+	}
+	response.Success(c, data)
+}
+
+// JWKS 公开 token 校验公钥，供其他服务校验 Loop 签发的 token
+func (u *userServerImpl) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, jwt.JWKS())
+}
+
+func (u *userServerImpl) GetUserPrivacy(c *gin.Context) {
+	data, err := u.user.GetUserPrivacy(c)
+	if err != nil {
+		response.Fail(c, response.CodeServerBusy)
+		return
+	}
+	response.Success(c, data)
+}
+
+func (u *userServerImpl) UpdateUserPrivacy(c *gin.Context) {
+	var p param.UpdateUserPrivacyRequest
+	if err := c.ShouldBind(&p); err != nil {
+		response.Fail(c, response.CodeInvalidParam)
+		return
+	}
+	err := u.user.UpdateUserPrivacy(c, &dto.UserPrivacy{
+		SearchByPhone:      *p.SearchByPhone,
+		FriendRequestScope: p.FriendRequestScope,
+		StrangerMessage:    *p.StrangerMessage,
+		ProfileScope:       p.ProfileScope,
+		CallScope:          p.CallScope,
+	})
 	if err != nil {
 		response.Fail(c, response.CodeServerBusy)
 		return
 	}
-	response.Success(c, gin.H{"access_token": acccessToken})
+	response.Success(c, nil)
 }
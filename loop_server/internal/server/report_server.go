@@ -0,0 +1,7 @@
+package server
+
+import "github.com/gin-gonic/gin"
+
+type ReportServer interface {
+	CreateReport(c *gin.Context)
+}
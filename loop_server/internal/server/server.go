@@ -1,47 +1,118 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"log/slog"
+	"loop_server/infra/consts"
 	"loop_server/infra/middleware"
 	"loop_server/infra/vars"
+	"net/http"
 	"strconv"
 )
 
 type server struct {
-	user   UserServer
-	friend FriendServer
-	group  GroupServer
-	im     ImServer
-	llm    LLMServer
+	user    UserServer
+	friend  FriendServer
+	group   GroupServer
+	im      ImServer
+	llm     LLMServer
+	account AccountServer
+	admin   AdminServer
+	report  ReportServer
+	audit   AuditServer
 }
 
-func NewServer(user UserServer, friend FriendServer, group GroupServer, im ImServer, llm LLMServer) *server {
+func NewServer(user UserServer, friend FriendServer, group GroupServer, im ImServer, llm LLMServer, account AccountServer, admin AdminServer, report ReportServer, audit AuditServer) *server {
 	return &server{
-		user:   user,
-		friend: friend,
-		group:  group,
-		im:     im,
-		llm:    llm,
+		user:    user,
+		friend:  friend,
+		group:   group,
+		im:      im,
+		llm:     llm,
+		account: account,
+		admin:   admin,
+		report:  report,
+		audit:   audit,
 	}
 }
 
-func (s *server) InitRouter() {
+// InitRouter 注册路由并启动服务，ctx 结束后停止接收新请求，等待处理中的请求完成后返回
+func (s *server) InitRouter(ctx context.Context) {
 	router := gin.Default()
 	router.Use(middleware.Cors())
+	router.GET("/.well-known/jwks.json", s.user.JWKS)
 
 	r := router.Group("/api/v1")
+	public := r.Group("")
 	{
-		r.POST("/register", s.user.Register)
-		r.POST("/login", s.user.Login)
-		r.POST("/refresh", s.user.RefreshToken)
+		public.Use(middleware.RateLimit(consts.RateLimitScopePublic))
+		public.POST("/register", s.user.Register)
+		public.POST("/login", s.user.Login)
+		public.POST("/login/code", s.user.LoginByCode)
+		public.POST("/login/2fa", s.user.CompleteLogin)
+		public.POST("/verify_code", s.user.SendVerifyCode)
+		public.POST("/reset_password", s.user.ResetPassword)
+		public.POST("/refresh", s.user.RefreshToken)
+	}
+
+	admin := router.Group("/admin/v1")
+	admin.POST("/login", middleware.RateLimit(consts.RateLimitScopePublic), s.admin.Login)
+	viewer := admin.Group("")
+	{
+		viewer.Use(middleware.AdminAuthMiddleware())
+		viewer.POST("/logout", s.admin.Logout)
+		viewer.GET("/user", s.admin.QueryUser)
+		viewer.GET("/group", s.admin.GetGroup)
+		viewer.GET("/message", s.admin.GetGroupMessage)
+		viewer.GET("/online", s.admin.GetOnlineStat)
+		viewer.GET("/sfu/room", s.admin.GetSfuRooms)
+		viewer.GET("/moderation/review", s.admin.GetModerationReviewList)
+		viewer.GET("/report", s.admin.GetReportList)
+		viewer.GET("/report/info", s.admin.GetReportInfo)
+	}
+	moderator := viewer.Group("")
+	{
+		moderator.Use(middleware.AdminRoleMiddleware(consts.OperatorRoleModerator))
+		moderator.POST("/user/unlock", s.admin.UnlockUser)
+		moderator.POST("/user/ban", s.admin.BanUser)
+		moderator.POST("/user/unban", s.admin.UnbanUser)
+		moderator.POST("/user/logout", s.admin.ForceLogout)
+		moderator.POST("/moderation/review/resolve", s.admin.ResolveModerationReview)
+		moderator.POST("/report/handle", s.admin.HandleReport)
+	}
+	superadmin := viewer.Group("")
+	{
+		superadmin.Use(middleware.AdminRoleMiddleware(consts.OperatorRoleSuperadmin))
+		superadmin.GET("/operator/list", s.admin.GetOperatorList)
+		superadmin.POST("/operator/create", s.admin.CreateOperator)
+		superadmin.POST("/operator/update", s.admin.UpdateOperator)
+		superadmin.GET("/audit", s.admin.GetAuditLogList)
 	}
 
 	user := r.Group("/user")
 	{
-		user.Use(middleware.JWTAuthMiddleware())
+		user.Use(middleware.JWTAuthMiddleware(), middleware.RateLimit(consts.RateLimitScopeUser))
 		user.GET("/query", s.user.QueryUser)
 		user.POST("/update_info", s.user.UpdateUserInfo)
 		user.POST("/update_password", s.user.UpdateUserPassword)
+		user.GET("/privacy", s.user.GetUserPrivacy)
+		user.POST("/privacy/update", s.user.UpdateUserPrivacy)
+		user.POST("/logout", s.user.Logout)
+		user.GET("/session/list", s.user.ListSessions)
+		user.POST("/session/revoke", s.user.RevokeSession)
+		user.POST("/session/revoke_others", s.user.RevokeOtherSessions)
+		user.GET("/2fa", s.user.GetTotpStatus)
+		user.POST("/2fa/enroll", s.user.EnrollTotp)
+		user.POST("/2fa/confirm", s.user.ConfirmTotp)
+		user.POST("/2fa/disable", s.user.DisableTotp)
+		user.POST("/account/delete", s.account.DeleteAccount)
+		user.POST("/account/export", s.account.CreateExport)
+		user.GET("/account/export", s.account.GetExport)
+		user.GET("/account/export/download", s.account.DownloadExport)
+		user.POST("/report", s.report.CreateReport)
+		user.GET("/audit", s.audit.GetAuditLogList)
 	}
 
 	friend := user.Group("/friend")
@@ -49,10 +120,23 @@ func (s *server) InitRouter() {
 		friend.POST("/add", s.friend.AddFriend)
 		friend.POST("/delete", s.friend.DeleteFriend)
 		friend.POST("/dispose", s.friend.DisposeFriendRequest)
+		friend.POST("/request/withdraw", s.friend.WithdrawFriendRequest)
 		friend.GET("/list", s.friend.GetFriendList)
 		friend.GET("/request/list", s.friend.GetFriendRequestList)
 		friend.GET("/request/statistics", s.friend.FriendListStatistics)
 		friend.GET("/list/group_id", s.friend.GetFriendListByGroupId)
+		friend.POST("/block", s.friend.BlockUser)
+		friend.POST("/unblock", s.friend.UnblockUser)
+		friend.GET("/block/list", s.friend.GetBlockList)
+		friend.POST("/remark", s.friend.UpdateFriendRemark)
+		friend.POST("/tags", s.friend.UpdateFriendTags)
+		friend.POST("/category/move", s.friend.MoveFriendCategory)
+		friend.GET("/category/list", s.friend.GetFriendCategoryList)
+		friend.POST("/category/create", s.friend.CreateFriendCategory)
+		friend.POST("/category/update", s.friend.UpdateFriendCategory)
+		friend.POST("/category/delete", s.friend.DeleteFriendCategory)
+		friend.POST("/category/sort", s.friend.SortFriendCategory)
+		friend.GET("/recommend", s.friend.GetFriendRecommend)
 	}
 
 	group := user.Group("/group")
@@ -64,27 +148,54 @@ func (s *server) InitRouter() {
 		group.POST("/exit", s.group.ExitGroup)
 		group.POST("/update", s.group.UpdateGroup)
 		group.POST("/transfer", s.group.TransferGroupOwner)
+		group.POST("/remark", s.group.UpdateGroupRemark)
+		group.POST("/upgrade", s.group.UpgradeGroup)
+		group.GET("/search", s.group.SearchGroup)
+		group.POST("/discovery", s.group.UpdateGroupDiscovery)
+
+		group.POST("/join", s.group.JoinGroup)
+		group.GET("/join/list", s.group.GetGroupJoinRequestList)
+		group.POST("/join/dispose", s.group.DisposeGroupJoinRequest)
 
 		group.GET("/member", s.group.GetGroupMemberList)
 		group.GET("/member_less_role", s.group.GetGroupMemberListByLessRole)
 		group.POST("/member/add", s.group.AddMember)
 		group.POST("/member/delete", s.group.DeleteMember)
+		group.POST("/member/nickname", s.group.UpdateGroupNickname)
 
 		group.POST("/admin/add", s.group.AddAdmin)
 		group.POST("/admin/delete", s.group.DeleteAdmin)
+
+		group.GET("/permission", s.group.GetGroupPermission)
+		group.POST("/permission/update", s.group.UpdateGroupPermission)
 	}
 
+	// ws 连接在首帧认证，不经过 JWT 中间件
+	r.GET("/im", middleware.RateLimit(consts.RateLimitScopeIm), s.im.WsHandler)
 	im := r.Group("/im")
 	{
-		im.Use(middleware.JWTAuthMiddleware())
-		im.GET("", s.im.WsHandler)
+		im.Use(middleware.JWTAuthMiddleware(), middleware.RateLimit(consts.RateLimitScopeIm))
 		im.GET("/offline_message", s.im.GetOfflineMessage)
 		im.GET("/local_time", s.im.GetLocalTime)
 		im.POST("/submit_message", s.im.SubmitOfflineMessage)
+		im.GET("/group/sync", s.im.SyncGroupMessage)
 	}
 	llm := user.Group("/llm")
 	{
+		llm.Use(middleware.RateLimit(consts.RateLimitScopeLLM))
 		llm.POST("/single_prompt", s.llm.GenerateFromSinglePrompt)
 	}
-	router.Run(":" + strconv.Itoa(vars.App.Port))
+
+	srv := &http.Server{Addr: ":" + strconv.Itoa(vars.App.Port), Handler: router}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("internal/server/server.go ListenAndServe err:", "err", err)
+		}
+	}()
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), consts.ServerShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("internal/server/server.go Shutdown err:", "err", err)
+	}
 }
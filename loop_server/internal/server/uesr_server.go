@@ -5,8 +5,23 @@ import "github.com/gin-gonic/gin"
 type UserServer interface {
 	Login(c *gin.Context)
 	Register(c *gin.Context)
+	SendVerifyCode(c *gin.Context)
+	LoginByCode(c *gin.Context)
+	ResetPassword(c *gin.Context)
+	CompleteLogin(c *gin.Context)
+	GetTotpStatus(c *gin.Context)
+	EnrollTotp(c *gin.Context)
+	ConfirmTotp(c *gin.Context)
+	DisableTotp(c *gin.Context)
+	ListSessions(c *gin.Context)
+	RevokeSession(c *gin.Context)
+	Logout(c *gin.Context)
+	RevokeOtherSessions(c *gin.Context)
 	QueryUser(c *gin.Context)
 	UpdateUserInfo(c *gin.Context)
 	UpdateUserPassword(c *gin.Context)
 	RefreshToken(c *gin.Context)
+	JWKS(c *gin.Context)
+	GetUserPrivacy(c *gin.Context)
+	UpdateUserPrivacy(c *gin.Context)
 }
@@ -1,18 +1,27 @@
 package main
 
 import (
+	"context"
 	"log/slog"
+	"loop_server/infra/captcha"
 	llm2 "loop_server/infra/llm"
+	"loop_server/infra/moderation"
 	"loop_server/infra/mysql"
+	"loop_server/infra/sms"
 	"loop_server/infra/vars"
 	app_impl "loop_server/internal/application/impl"
 	domain_impl "loop_server/internal/domain/impl"
 	repo_impl "loop_server/internal/repository/impl"
 	server2 "loop_server/internal/server"
 	server_impl "loop_server/internal/server/impl"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	db, err := mysql.InitDB(vars.App.MySQLConfig)
 	if err != nil {
@@ -25,26 +34,65 @@ func main() {
 	friendRepo := repo_impl.NewFriendRepoImpl(db)
 	groupRepo := repo_impl.NewGroupRepoImpl(db)
 	imRepo := repo_impl.NewImRepoImpl(db)
+	moderationRepo := repo_impl.NewModerationRepoImpl(db)
+	reportRepo := repo_impl.NewReportRepoImpl(db)
+	operatorRepo := repo_impl.NewOperatorRepoImpl(db)
+	auditRepo := repo_impl.NewAuditRepoImpl(db)
 
 	userDomain := domain_impl.NewUserDomainImpl(userRepo)
 	friendDomain := domain_impl.NewFriendDomainImpl(friendRepo)
 	groupDomain := domain_impl.NewGroupDomainImpl(groupRepo)
 	imDomain := domain_impl.NewImDomainImpl(imRepo)
 	llmDomain := domain_impl.NewLLMDomainImpl(llm)
+	smsSender, err := sms.InitSender(vars.App.SmsConfig, vars.App.Mode)
+	if err != nil {
+		slog.Error("sms.InitSender err:", "err", err)
+		os.Exit(1)
+	}
+	verifyDomain := domain_impl.NewVerifyDomainImpl(smsSender)
+	moderationDomain := domain_impl.NewModerationDomainImpl(moderationRepo, moderation.InitFilter(vars.App.ModerationConfig),
+		moderation.InitClassifier(vars.App.ModerationConfig, llm))
+	go moderationDomain.Run(ctx)
+	reportDomain := domain_impl.NewReportDomainImpl(reportRepo)
+	operatorDomain := domain_impl.NewOperatorDomainImpl(operatorRepo)
+	auditDomain := domain_impl.NewAuditDomainImpl(auditRepo)
+	// 审计日志在 http 服务停止后才结束，保证处理中请求产生的日志也能写入
+	auditCtx, stopAudit := context.WithCancel(context.Background())
+	auditDone := make(chan struct{})
+	go func() {
+		auditDomain.Run(auditCtx)
+		close(auditDone)
+	}()
+	loginGuardDomain := domain_impl.NewLoginGuardDomainImpl(captcha.InitVerifier(vars.App.CaptchaConfig))
 
-	userApp := app_impl.NewUserAppImpl(userDomain, friendDomain)
-	friendApp := app_impl.NewFriendAppImpl(friendDomain, userDomain, groupDomain)
-	groupApp := app_impl.NewGroupAppImpl(groupDomain, userDomain, imDomain)
+	userApp := app_impl.NewUserAppImpl(userDomain, friendDomain, verifyDomain, imDomain, loginGuardDomain, moderationDomain, auditDomain)
+	friendApp := app_impl.NewFriendAppImpl(friendDomain, userDomain, groupDomain, imDomain)
+	groupApp := app_impl.NewGroupAppImpl(groupDomain, userDomain, imDomain, friendDomain, moderationDomain, auditDomain)
 	sufApp := app_impl.NewSfuAppImpl(imDomain)
-	imApp := app_impl.NewImAppImpl(sufApp, imDomain, groupDomain, userDomain)
+	imApp := app_impl.NewImAppImpl(sufApp, imDomain, groupDomain, userDomain, friendDomain, moderationDomain)
 	llmApp := app_impl.NewLLMAppImpl(llmDomain)
+	accountApp := app_impl.NewAccountAppImpl(userDomain, friendDomain, imDomain, friendApp, groupApp)
+	go accountApp.RunWorker(context.Background())
+	auditApp := app_impl.NewAuditAppImpl(auditDomain)
+	reportApp := app_impl.NewReportAppImpl(reportDomain, userDomain, groupDomain, imDomain)
+	adminApp := app_impl.NewAdminAppImpl(loginGuardDomain, moderationDomain, reportDomain, userDomain, imDomain, groupDomain, operatorDomain, auditDomain, groupApp, sufApp)
+	if err = adminApp.InitSuperadmin(context.Background()); err != nil {
+		slog.Error("adminApp.InitSuperadmin err:", "err", err)
+	}
 
 	userServer := server_impl.NewUserServerImpl(userApp)
 	friendServer := server_impl.NewFriendServerImpl(friendApp)
 	groupServer := server_impl.NewGroupServerImpl(groupApp)
 	llmServer := server_impl.NewLLmServerImpl(llmApp)
 	imServer := server_impl.NewImServerImpl(imApp)
+	accountServer := server_impl.NewAccountServerImpl(accountApp)
+	adminServer := server_impl.NewAdminServerImpl(adminApp)
+	reportServer := server_impl.NewReportServerImpl(reportApp)
+	auditServer := server_impl.NewAuditServerImpl(auditApp)
+
+	server := server2.NewServer(userServer, friendServer, groupServer, imServer, llmServer, accountServer, adminServer, reportServer, auditServer)
+	server.InitRouter(ctx)
 
-	server := server2.NewServer(userServer, friendServer, groupServer, imServer, llmServer)
-	server.InitRouter()
+	stopAudit()
+	<-auditDone
 }
@@ -0,0 +1,87 @@
+package ahocorasick
+
+// Matcher Aho-Corasick 多模式匹配，按 rune 处理以支持中文
+type Matcher struct {
+	nodes   []node
+	lengths []int // 各模式的 rune 长度
+}
+
+type node struct {
+	next    map[rune]int
+	fail    int
+	outputs []int // 在该节点结束的模式下标，包含 fail 链上的模式
+}
+
+// Match 一次命中，Start、End 为 rune 下标，区间左闭右开
+type Match struct {
+	Start int
+	End   int
+	Index int // 命中的模式下标
+}
+
+func New(patterns []string) *Matcher {
+	m := &Matcher{
+		nodes:   []node{{next: map[rune]int{}}},
+		lengths: make([]int, len(patterns)),
+	}
+	for i, p := range patterns {
+		cur := 0
+		for _, r := range p {
+			nxt, ok := m.nodes[cur].next[r]
+			if !ok {
+				m.nodes = append(m.nodes, node{next: map[rune]int{}})
+				nxt = len(m.nodes) - 1
+				m.nodes[cur].next[r] = nxt
+			}
+			cur = nxt
+			m.lengths[i]++
+		}
+		if cur != 0 {
+			m.nodes[cur].outputs = append(m.nodes[cur].outputs, i)
+		}
+	}
+
+	// 广度优先构建 fail 指针，子节点的 fail 一定比自身浅，出队时父节点的 fail 已经就绪
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[cur].next {
+			fail := m.nodes[cur].fail
+			for fail != 0 {
+				if _, ok := m.nodes[fail].next[r]; ok {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			if f, ok := m.nodes[fail].next[r]; ok && f != child {
+				m.nodes[child].fail = f
+			}
+			m.nodes[child].outputs = append(m.nodes[child].outputs, m.nodes[m.nodes[child].fail].outputs...)
+			queue = append(queue, child)
+		}
+	}
+	return m
+}
+
+// Find 返回 text 中所有命中，包含重叠的命中
+func (m *Matcher) Find(text []rune) []Match {
+	var matches []Match
+	cur := 0
+	for i, r := range text {
+		for cur != 0 {
+			if _, ok := m.nodes[cur].next[r]; ok {
+				break
+			}
+			cur = m.nodes[cur].fail
+		}
+		cur = m.nodes[cur].next[r]
+		for _, idx := range m.nodes[cur].outputs {
+			matches = append(matches, Match{Start: i + 1 - m.lengths[idx], End: i + 1, Index: idx})
+		}
+	}
+	return matches
+}
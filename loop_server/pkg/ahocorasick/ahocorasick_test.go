@@ -0,0 +1,110 @@
+package ahocorasick
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestMatcherFind(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		text     string
+		want     []Match
+	}{
+		{
+			name:     "empty text",
+			patterns: []string{"he", "she"},
+			text:     "",
+			want:     nil,
+		},
+		{
+			name:     "no patterns",
+			patterns: nil,
+			text:     "hello",
+			want:     nil,
+		},
+		{
+			name:     "empty pattern is ignored",
+			patterns: []string{"", "ab"},
+			text:     "ab",
+			want:     []Match{{Start: 0, End: 2, Index: 1}},
+		},
+		{
+			name:     "no match",
+			patterns: []string{"xyz"},
+			text:     "hello",
+			want:     nil,
+		},
+		{
+			name:     "overlapping patterns",
+			patterns: []string{"he", "she", "his", "hers"},
+			text:     "ushers",
+			want: []Match{
+				{Start: 1, End: 4, Index: 1},
+				{Start: 2, End: 4, Index: 0},
+				{Start: 2, End: 6, Index: 3},
+			},
+		},
+		{
+			name:     "nested patterns",
+			patterns: []string{"a", "aa"},
+			text:     "aaa",
+			want: []Match{
+				{Start: 0, End: 1, Index: 0},
+				{Start: 1, End: 2, Index: 0},
+				{Start: 0, End: 2, Index: 1},
+				{Start: 2, End: 3, Index: 0},
+				{Start: 1, End: 3, Index: 1},
+			},
+		},
+		{
+			name:     "duplicate patterns",
+			patterns: []string{"ab", "ab"},
+			text:     "ab",
+			want: []Match{
+				{Start: 0, End: 2, Index: 0},
+				{Start: 0, End: 2, Index: 1},
+			},
+		},
+		{
+			name:     "multibyte text uses rune offsets",
+			patterns: []string{"敏感", "感词"},
+			text:     "这是敏感词",
+			want: []Match{
+				{Start: 2, End: 4, Index: 0},
+				{Start: 3, End: 5, Index: 1},
+			},
+		},
+		{
+			name:     "fail transition across multibyte runes",
+			patterns: []string{"中国人", "国民"},
+			text:     "中国民众",
+			want:     []Match{{Start: 1, End: 3, Index: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(tt.patterns).Find([]rune(tt.text))
+			sortMatches(got)
+			sortMatches(tt.want)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func sortMatches(matches []Match) {
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.End != b.End {
+			return a.End < b.End
+		}
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		return a.Index < b.Index
+	})
+}
@@ -3,6 +3,7 @@ package jwt
 import (
 	"errors"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"time"
 )
 
@@ -11,13 +12,10 @@ const (
 	RefreshTokenExpire = time.Hour * 24 * 7 // Refresh Token 有效期 7 天
 )
 
-var (
-	AccessTokenSecret  = []byte("loop-access")
-	RefreshTokenSecret = []byte("loop-refresh")
-)
-
 type UserClaims struct {
-	ID uint `json:"id"` // 用户ID
+	ID        uint      `json:"id"`  // 用户ID
+	SessionId string    `json:"sid"` // 会话ID，同一次登录签发的双 token 共用
+	Type      tokenType `json:"typ"` // token 类型，防止 refresh token 被当作 access token 使用
 }
 
 type tokenType int
@@ -33,62 +31,48 @@ type CustomClaims struct {
 }
 
 // 生成双 Token
-func GenerateTokens(userID uint) (accessToken, refreshToken string, err error) {
+func GenerateTokens(userID uint, sessionId string) (accessToken, refreshToken string, err error) {
 	// 1. 生成 Access Token
-	accessToken, err = GenerateToken(userID, AccessTokenExpire, AccessToken)
+	accessToken, err = GenerateToken(userID, sessionId, AccessTokenExpire, AccessToken)
 	if err != nil {
 		return "", "", err
 	}
 
 	// 2. 生成 Refresh Token（单独用途，不包含用户敏感信息）
-	refreshToken, err = GenerateToken(userID, RefreshTokenExpire, RefreshToken)
+	refreshToken, err = GenerateToken(userID, sessionId, RefreshTokenExpire, RefreshToken)
 	return
 }
 
 // GenerateToken 生成单个 Token tokenType: 0-accessToken,1-refreshToken
-func GenerateToken(userID uint, expireDuration time.Duration, tokenType tokenType) (string, error) {
+func GenerateToken(userID uint, sessionId string, expireDuration time.Duration, tokenType tokenType) (string, error) {
+	key := signingKey()
+	if key == nil {
+		return "", errors.New("jwt signing key not configured")
+	}
 	claims := CustomClaims{
-		UserClaims: UserClaims{ID: userID},
+		UserClaims: UserClaims{ID: userID, SessionId: sessionId, Type: tokenType},
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expireDuration)),
-			Issuer:    "my-im-system",
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        uuid.NewString(),
+			Issuer:    issuer(),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	customSecret := AccessTokenSecret
-	if tokenType == 1 {
-		customSecret = RefreshTokenSecret
-	}
-	return token.SignedString(customSecret)
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.Kid
+	return token.SignedString(key.signKey)
 }
 
 // 解析 Token
 func ParseToken(tokenString string, tokenType tokenType) (*CustomClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		customSecret := AccessTokenSecret
-		if tokenType == 1 {
-			customSecret = RefreshTokenSecret
-		}
-		return customSecret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, verifyKeyFunc)
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*CustomClaims); ok && token.Valid {
+	claims, ok := token.Claims.(*CustomClaims)
+	if ok && token.Valid && claims.Type == tokenType && claims.VerifyIssuer(issuer(), true) {
 		return claims, nil
 	}
 	return nil, errors.New("invalid token")
 }
-
-// 刷新 Access Token（校验 Refresh Token 后生成新 Access Token）
-func RefreshAccessToken(refreshToken string) (newAccessToken string, err error) {
-	// 1. 校验 Refresh Token 有效性
-	claims, err := ParseToken(refreshToken, RefreshToken)
-	if err != nil {
-		return "", errors.New("invalid refresh token")
-	}
-
-	// 2. 生成新 Access Token
-	return GenerateToken(claims.UserClaims.ID, AccessTokenExpire, AccessToken)
-}
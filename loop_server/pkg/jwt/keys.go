@@ -0,0 +1,242 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"log/slog"
+	"loop_server/pkg/settings"
+	"math/big"
+	"os"
+	"sync"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+
+	defaultIssuer = "loop"
+)
+
+// Key 签名密钥，没有私钥的密钥只用于校验，用于轮换时继续接受旧密钥签发的 token
+type Key struct {
+	Kid       string
+	Alg       string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+type keySet struct {
+	issuer  string
+	signing *Key
+	keys    map[string]*Key
+}
+
+var (
+	mu      sync.RWMutex
+	current *keySet
+)
+
+// Init 按配置加载签名和校验密钥，加载失败时返回错误且不替换当前密钥
+func Init(c *settings.JwtConfig) error {
+	set, err := loadKeySet(c)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	current = set
+	mu.Unlock()
+	return nil
+}
+
+// InitEphemeral 使用进程内随机生成的临时密钥，仅用于本地调试；
+// 重启后已签发的 token 全部失效，多个实例之间也无法互相校验
+func InitEphemeral() error {
+	set, err := ephemeralKeySet()
+	if err != nil {
+		return err
+	}
+	slog.Warn("!!! pkg/jwt using EPHEMERAL signing key: all tokens are invalidated on restart and are not accepted by other instances, configure jwt.keys for production !!!")
+	mu.Lock()
+	current = set
+	mu.Unlock()
+	return nil
+}
+
+func loadKeySet(c *settings.JwtConfig) (*keySet, error) {
+	if c == nil || len(c.Keys) == 0 {
+		return nil, errors.New("jwt keys not configured")
+	}
+	set := &keySet{issuer: c.Issuer, keys: make(map[string]*Key, len(c.Keys))}
+	if set.issuer == "" {
+		set.issuer = defaultIssuer
+	}
+	for _, kc := range c.Keys {
+		key, err := loadKey(kc)
+		if err != nil {
+			return nil, fmt.Errorf("load jwt key %s: %w", kc.Kid, err)
+		}
+		if _, ok := set.keys[key.Kid]; ok {
+			return nil, fmt.Errorf("duplicate jwt kid %s", key.Kid)
+		}
+		set.keys[key.Kid] = key
+	}
+	signing, ok := set.keys[c.SigningKey]
+	if !ok || signing.signKey == nil {
+		return nil, fmt.Errorf("jwt signing key %s not found or has no private key", c.SigningKey)
+	}
+	set.signing = signing
+	return set, nil
+}
+
+func loadKey(c settings.JwtKey) (*Key, error) {
+	if c.Kid == "" {
+		return nil, errors.New("kid is required")
+	}
+	key := &Key{Kid: c.Kid, Alg: c.Alg}
+	switch c.Alg {
+	case AlgHS256:
+		if len(c.Secret) < 32 {
+			return nil, errors.New("HS256 secret must be at least 32 bytes")
+		}
+		key.method = jwt.SigningMethodHS256
+		key.signKey = []byte(c.Secret)
+		key.verifyKey = key.signKey
+	case AlgRS256:
+		key.method = jwt.SigningMethodRS256
+		if c.PrivateKeyFile != "" {
+			pem, err := os.ReadFile(c.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.signKey, key.verifyKey = private, &private.PublicKey
+		} else {
+			pem, err := os.ReadFile(c.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			if key.verifyKey, err = jwt.ParseRSAPublicKeyFromPEM(pem); err != nil {
+				return nil, err
+			}
+		}
+	case AlgEdDSA:
+		key.method = jwt.SigningMethodEdDSA
+		if c.PrivateKeyFile != "" {
+			pem, err := os.ReadFile(c.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			private, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.signKey, key.verifyKey = private, private.(ed25519.PrivateKey).Public()
+		} else {
+			pem, err := os.ReadFile(c.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			if key.verifyKey, err = jwt.ParseEdPublicKeyFromPEM(pem); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported alg %s", c.Alg)
+	}
+	return key, nil
+}
+
+func ephemeralKeySet() (*keySet, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	key := &Key{Kid: "ephemeral", Alg: AlgHS256, method: jwt.SigningMethodHS256, signKey: secret, verifyKey: secret}
+	return &keySet{issuer: defaultIssuer, signing: key, keys: map[string]*Key{key.Kid: key}}, nil
+}
+
+func keys() *keySet {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+func signingKey() *Key {
+	if set := keys(); set != nil {
+		return set.signing
+	}
+	return nil
+}
+
+func issuer() string {
+	if set := keys(); set != nil {
+		return set.issuer
+	}
+	return defaultIssuer
+}
+
+// verifyKeyFunc 按 token 头中的 kid 选择校验密钥，并要求算法与密钥一致
+func verifyKeyFunc(token *jwt.Token) (interface{}, error) {
+	set := keys()
+	if set == nil {
+		return nil, errors.New("jwt keys not initialized")
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, ok := set.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+	return key.verifyKey, nil
+}
+
+// JWK 公钥的 JSON Web Key 表示
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS 导出全部非对称校验公钥，对称密钥不对外暴露
+func JWKS() map[string][]JWK {
+	list := make([]JWK, 0)
+	set := keys()
+	if set == nil {
+		return map[string][]JWK{"keys": list}
+	}
+	enc := base64.RawURLEncoding
+	for _, key := range set.keys {
+		switch pub := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			list = append(list, JWK{
+				Kty: "RSA", Kid: key.Kid, Use: "sig", Alg: key.Alg,
+				N: enc.EncodeToString(pub.N.Bytes()),
+				E: enc.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			list = append(list, JWK{
+				Kty: "OKP", Kid: key.Kid, Use: "sig", Alg: key.Alg,
+				Crv: "Ed25519",
+				X:   enc.EncodeToString(pub),
+			})
+		}
+	}
+	return map[string][]JWK{"keys": list}
+}
@@ -6,13 +6,60 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-const CtxUserIDKey = "userID"
+const (
+	CtxUserIDKey    = "userID"
+	CtxSessionIDKey = "sessionID"
+
+	CtxOperatorIDKey   = "operatorID"
+	CtxOperatorRoleKey = "operatorRole"
+)
+
+const HeaderDeviceName = "X-Device-Name" // 客户端上报的设备名
 
 var ErrorUserNotLogin = errors.New("用户未登录")
 
 func GetCurrentUser(ctx context.Context) (userID uint) {
-	c, _ := ctx.(*gin.Context)
-	uid, _ := c.Get(CtxUserIDKey)
-	userID, _ = uid.(uint)
+	if c, ok := ctx.(*gin.Context); ok {
+		uid, _ := c.Get(CtxUserIDKey)
+		userID, _ = uid.(uint)
+		return
+	}
+	userID, _ = ctx.Value(CtxUserIDKey).(uint)
 	return
 }
+
+// WithCurrentUser 为脱离请求的上下文设置当前用户，用于异步任务
+func WithCurrentUser(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, CtxUserIDKey, userID)
+}
+
+// GetCurrentSession 获取当前请求的会话ID
+func GetCurrentSession(ctx context.Context) (sessionID string) {
+	if c, ok := ctx.(*gin.Context); ok {
+		sid, _ := c.Get(CtxSessionIDKey)
+		sessionID, _ = sid.(string)
+		return
+	}
+	sessionID, _ = ctx.Value(CtxSessionIDKey).(string)
+	return
+}
+
+// GetCurrentOperator 获取管理后台当前操作员
+func GetCurrentOperator(ctx context.Context) (operatorID uint) {
+	if c, ok := ctx.(*gin.Context); ok {
+		oid, _ := c.Get(CtxOperatorIDKey)
+		operatorID, _ = oid.(uint)
+		return
+	}
+	operatorID, _ = ctx.Value(CtxOperatorIDKey).(uint)
+	return
+}
+
+// GetClientInfo 获取请求的客户端 IP、User-Agent 和设备名，非 HTTP 请求返回空值
+func GetClientInfo(ctx context.Context) (ip, userAgent, device string) {
+	c, ok := ctx.(*gin.Context)
+	if !ok || c.Request == nil {
+		return
+	}
+	return c.ClientIP(), c.Request.UserAgent(), c.GetHeader(HeaderDeviceName)
+}
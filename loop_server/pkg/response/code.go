@@ -13,19 +13,105 @@ const (
 	CodePartUserNotExist
 	CodeGroupUserExist
 	CodeNoPermission
+	CodeGroupFull
+	CodeGroupJoinForbidden
+	CodeGroupConfirmMismatch
+	CodeFriendCategoryNotExist
+	CodeFriendCategoryLimit
+	CodeFriendRequestPending
+	CodeFriendRequestCooldown
+	CodeFriendRequestLimit
+	CodeFriendRequestNotExist
+	CodeFriendRequestForbidden
+	CodePasswordError
+	CodeAccountExportPending
+	CodeAccountExportNotExist
+	CodeVerifyCodeInvalid
+	CodeVerifyCodeTooFrequent
+	CodeVerifyCodeLimit
+	CodePhoneNotExist
+	CodeTwoFactorEnabled
+	CodeTwoFactorNotEnabled
+	CodeTwoFactorEnrollExpired
+	CodeTwoFactorCodeInvalid
+	CodeLoginChallengeInvalid
+	CodeSessionNotExist
+	CodeRefreshTokenReused
+	CodeAccountLocked
+	CodeLoginTooFrequent
+	CodeCaptchaRequired
+	CodeCaptchaInvalid
+	CodeRateLimited
+	CodeContentRejected
+	CodeModerationReviewNotExist
+	CodeReportTargetNotExist
+	CodeReportPending
+	CodeReportNotExist
+	CodeReportActionInvalid
+	CodeAccountBanned
+	CodeOperatorLoginFailed
+	CodeOperatorExist
+	CodeOperatorNotExist
+	CodeUserNotExist
+	CodeGroupNotExist
+	CodeMessageNotExist
+	CodeGroupJoinRequestCooldown
 )
 
 var codeMsgMap = map[ResCode]string{
-	CodeSuccess:              "success",
-	CodeServerBusy:           "服务繁忙",
-	CodePhoneExist:           "手机号已被注册",
-	CodeInvalidParam:         "无效的参数",
-	CodePhoneOrPasswordError: "手机号或密码错误",
-	CodeInvalidToken:         "无效的token",
-	CodeOldPasswordError:     "原始密码错误",
-	CodePartUserNotExist:     "部分用户不存在",
-	CodeGroupUserExist:       "群组成员已存在",
-	CodeNoPermission:         "没有权限",
+	CodeSuccess:                  "success",
+	CodeServerBusy:               "服务繁忙",
+	CodePhoneExist:               "手机号已被注册",
+	CodeInvalidParam:             "无效的参数",
+	CodePhoneOrPasswordError:     "手机号或密码错误",
+	CodeInvalidToken:             "无效的token",
+	CodeOldPasswordError:         "原始密码错误",
+	CodePartUserNotExist:         "部分用户不存在",
+	CodeGroupUserExist:           "群组成员已存在",
+	CodeNoPermission:             "没有权限",
+	CodeGroupFull:                "群成员已达上限",
+	CodeGroupJoinForbidden:       "该群不允许主动加入",
+	CodeGroupConfirmMismatch:     "群名称确认不一致",
+	CodeFriendCategoryNotExist:   "好友分组不存在",
+	CodeFriendCategoryLimit:      "好友分组数量已达上限",
+	CodeFriendRequestPending:     "好友请求已发送，请等待对方处理",
+	CodeFriendRequestCooldown:    "好友请求被拒绝，请稍后再试",
+	CodeFriendRequestLimit:       "今日好友请求次数已达上限",
+	CodeFriendRequestNotExist:    "好友请求不存在或已失效",
+	CodeFriendRequestForbidden:   "对方设置了不允许添加好友",
+	CodePasswordError:            "密码错误",
+	CodeAccountExportPending:     "数据正在导出，请稍后",
+	CodeAccountExportNotExist:    "导出文件不存在或已过期",
+	CodeVerifyCodeInvalid:        "验证码错误或已过期",
+	CodeVerifyCodeTooFrequent:    "验证码发送过于频繁",
+	CodeVerifyCodeLimit:          "今日验证码发送次数已达上限",
+	CodePhoneNotExist:            "手机号未注册",
+	CodeTwoFactorEnabled:         "已开启两步验证",
+	CodeTwoFactorNotEnabled:      "未开启两步验证",
+	CodeTwoFactorEnrollExpired:   "两步验证绑定已过期，请重新获取",
+	CodeTwoFactorCodeInvalid:     "动态码或恢复码错误",
+	CodeLoginChallengeInvalid:    "登录验证已过期，请重新登录",
+	CodeSessionNotExist:          "会话不存在或已失效",
+	CodeRefreshTokenReused:       "登录状态异常，请重新登录",
+	CodeAccountLocked:            "登录失败次数过多，账号已临时锁定",
+	CodeLoginTooFrequent:         "登录尝试过于频繁，请稍后再试",
+	CodeCaptchaRequired:          "请完成人机验证",
+	CodeCaptchaInvalid:           "人机验证未通过",
+	CodeRateLimited:              "请求过于频繁，请稍后再试",
+	CodeContentRejected:          "内容包含违规信息",
+	CodeModerationReviewNotExist: "审核记录不存在或已处理",
+	CodeReportTargetNotExist:     "举报对象不存在",
+	CodeReportPending:            "已举报，请等待处理",
+	CodeReportNotExist:           "举报不存在",
+	CodeReportActionInvalid:      "该举报不支持此操作",
+	CodeAccountBanned:            "账号已被封禁",
+	CodeOperatorLoginFailed:      "用户名或密码错误",
+	CodeOperatorExist:            "操作员已存在",
+	CodeOperatorNotExist:         "操作员不存在",
+	CodeUserNotExist:             "用户不存在",
+	CodeGroupNotExist:            "群不存在",
+	CodeMessageNotExist:          "消息不存在",
+	CodeGroupJoinRequestCooldown: "入群申请被拒绝，请稍后再试",
 }
 
 func (c ResCode) Msg() string {
@@ -7,12 +7,24 @@ import (
 	"github.com/spf13/viper"
 )
 
+const ModeDev = "dev" // 本地调试模式，允许部分配置缺失时降级运行
+
 type AppConfig struct {
-	Mode          string `mapstructure:"mode"`
-	Port          int    `mapstructure:"port"`
-	*MySQLConfig  `mapstructure:"mysql"`
-	*RedisConfig  `mapstructure:"redis"`
-	*OpenaiConfig `mapstructure:"openai"`
+	Mode              string `mapstructure:"mode"`
+	Port              int    `mapstructure:"port"`
+	*MySQLConfig      `mapstructure:"mysql"`
+	*RedisConfig      `mapstructure:"redis"`
+	*OpenaiConfig     `mapstructure:"openai"`
+	*GroupConfig      `mapstructure:"group"`
+	*FriendConfig     `mapstructure:"friend"`
+	*AccountConfig    `mapstructure:"account"`
+	*SmsConfig        `mapstructure:"sms"`
+	*JwtConfig        `mapstructure:"jwt"`
+	*LoginGuardConfig `mapstructure:"login_guard"`
+	*CaptchaConfig    `mapstructure:"captcha"`
+	*AdminConfig      `mapstructure:"admin"`
+	*RateLimitConfig  `mapstructure:"rate_limit"`
+	*ModerationConfig `mapstructure:"moderation"`
 }
 
 type MySQLConfig struct {
@@ -37,6 +49,73 @@ type OpenaiConfig struct {
 	URL   string `mapstructure:"url"`
 }
 
+type GroupConfig struct {
+	DissolveRetention string `mapstructure:"dissolve_retention"` // 解散群后的消息保留策略:archive-只读归档，purge-清除
+}
+
+type FriendConfig struct {
+	RequestExpireDays    int `mapstructure:"request_expire_days"`    // 好友请求过期天数
+	RequestCooldownHours int `mapstructure:"request_cooldown_hours"` // 被拒绝后再次申请的冷却小时数
+	RequestDailyLimit    int `mapstructure:"request_daily_limit"`    // 每人每天可发送的好友请求数
+}
+
+type AccountConfig struct {
+	DeletionGraceDays int    `mapstructure:"deletion_grace_days"` // 注销冷静期天数，期间重新登录可取消注销
+	ExportDir         string `mapstructure:"export_dir"`          // 数据导出文件目录
+}
+
+type SmsConfig struct {
+	Driver   string `mapstructure:"driver"`    // 发送方式:log-打印日志，file-写入文件，两者都只能在 dev 模式下使用
+	FilePath string `mapstructure:"file_path"` // file 方式的文件路径
+}
+
+type JwtConfig struct {
+	Issuer     string   `mapstructure:"issuer"`      // 签发者
+	SigningKey string   `mapstructure:"signing_key"` // 签发 token 使用的 kid
+	Keys       []JwtKey `mapstructure:"keys"`        // 全部有效密钥，轮换时保留旧密钥用于校验
+}
+
+type JwtKey struct {
+	Kid            string `mapstructure:"kid"`
+	Alg            string `mapstructure:"alg"`              // HS256、RS256、EdDSA
+	Secret         string `mapstructure:"secret"`           // HS256 密钥，至少 32 字节
+	PrivateKeyFile string `mapstructure:"private_key_file"` // RS256/EdDSA 私钥 PEM 文件
+	PublicKeyFile  string `mapstructure:"public_key_file"`  // 只用于校验的公钥 PEM 文件
+}
+
+type LoginGuardConfig struct {
+	CaptchaThreshold int `mapstructure:"captcha_threshold"` // 连续失败多少次后需要人机验证
+	LockThreshold    int `mapstructure:"lock_threshold"`    // 同一手机号连续失败多少次后锁定
+	LockMinutes      int `mapstructure:"lock_minutes"`      // 锁定分钟数
+	IpMaxFailures    int `mapstructure:"ip_max_failures"`   // 同一 IP 在统计窗口内的失败上限
+}
+
+type CaptchaConfig struct {
+	Driver     string `mapstructure:"driver"`      // 校验方式:none-不启用 stub-固定答案
+	StubAnswer string `mapstructure:"stub_answer"` // stub 方式的答案
+}
+
+type AdminConfig struct {
+	Username string `mapstructure:"username"` // 初始超级管理员登录名，启动时不存在则创建
+	Password string `mapstructure:"password"` // 初始超级管理员密码，为空时不创建
+}
+
+type RateLimitConfig struct {
+	Disabled bool                     `mapstructure:"disabled"` // 关闭全部限流
+	Rules    map[string]RateLimitRule `mapstructure:"rules"`    // 按限流范围覆盖默认规则
+}
+
+type RateLimitRule struct {
+	Limit         int `mapstructure:"limit"`          // 窗口内允许的请求数，0 表示不限流
+	WindowSeconds int `mapstructure:"window_seconds"` // 窗口秒数
+}
+
+type ModerationConfig struct {
+	WordFile      string `mapstructure:"word_file"`      // 敏感词库文件，修改后自动重新加载
+	DefaultAction string `mapstructure:"default_action"` // 词库中未指定处理方式时使用:mask、flag、reject
+	Classifier    string `mapstructure:"classifier"`     // 异步分类器:llm，为空时不启用
+}
+
 func Init() (app *AppConfig, err error) {
 	app = new(AppConfig)
 	viper.SetConfigFile("config.yaml")
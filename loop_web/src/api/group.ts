@@ -32,9 +32,12 @@ export const addGroupMember = (groupId: number, userIds: number[]) => {
   });
 };
 
-// 解散群聊
-export const disbandGroup = (groupId: number) => {
-  return http.post(`/api/v1/user/group/delete`, { group_id: groupId });
+// 解散群聊，confirm 需与群名称一致
+export const disbandGroup = (groupId: number, confirm: string) => {
+  return http.post(`/api/v1/user/group/delete`, {
+    group_id: groupId,
+    confirm,
+  });
 };
 
 // 退出群聊
@@ -21,8 +21,9 @@ export const LoginPost = (loginData: LoginParams) => {
 
 interface RegisterParams {
   nickname: string;
-  phone: number;
+  phone: string;
   password: string;
+  code: string; // 手机验证码
 }
 
 // 注册接口
@@ -30,6 +31,14 @@ export const RegisterPost = (registerData: RegisterParams) => {
   return http.post<User>("/api/v1/register", registerData);
 };
 
+// 验证码场景
+export type VerifyScene = "register" | "login" | "reset_password";
+
+// 发送手机验证码
+export const SendVerifyCode = (phone: string, scene: VerifyScene) => {
+  return http.post("/api/v1/verify_code", { phone, scene });
+};
+
 // 刷新token
 export const RefreshToken = (refreshToken: string) => {
   return http.post("/api/v1/refresh", { refresh_token: refreshToken });
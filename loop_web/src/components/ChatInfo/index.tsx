@@ -81,6 +81,7 @@ const ChatInfo = (props: ChatInfoProps) => {
   const [showAddAdminDrawer, setShowAddAdminDrawer] = useState(false); // 添加管理员抽屉
   const [isModalOpen, setIsModalOpen] = useState(false); // 添加成员弹窗状态
   const [isTransferModalOpen, setIsTransferModalOpen] = useState(false); // 转让群主确认弹窗
+  const [isDisbandModalOpen, setIsDisbandModalOpen] = useState(false); // 解散群聊确认弹窗
   const [showEditGroupDrawer, setShowEditGroupDrawer] = useState(false); // 编辑群信息抽屉
 
   // 数据状态
@@ -104,6 +105,7 @@ const ChatInfo = (props: ChatInfoProps) => {
   const [isDeleteMode, setIsDeleteMode] = useState(false); // 是否处于删除模式
   const [topSwitch, setTopSwitch] = useState(false); // 置顶开关状态
   const [searchInput, setSearchInput] = useState(""); // 搜索框内容
+  const [disbandConfirm, setDisbandConfirm] = useState(""); // 解散群聊时输入的群名称
 
   /**
    * 判断当前用户是否是群主或管理员
@@ -305,18 +307,14 @@ const ChatInfo = (props: ChatInfoProps) => {
   // ===================== 其他操作方法 =====================
 
   /**
-   * 处理群操作(解散/退出/删除)
+   * 处理群操作(退出/删除)
    */
   const handleGroupAction = async () => {
     try {
       await db.deleteConversation(userInfo.id, friendId, chatType);
 
       if (chatType === 2) {
-        if (groupInfo.ownerId == userInfo.id) {
-          await disbandGroup(friendId); // 群主解散群聊
-        } else {
-          await quitGroup(friendId); // 成员退出群聊
-        }
+        await quitGroup(friendId); // 成员退出群聊
       } else if (chatType === 1) {
         await deleteFriend(friendId); // 删除好友
       }
@@ -330,6 +328,29 @@ const ChatInfo = (props: ChatInfoProps) => {
     }
   };
 
+  /**
+   * 解散群聊，需输入群名称确认，服务端校验通过后再删除本地会话
+   */
+  const handleDisbandGroup = async () => {
+    try {
+      const res: any = await disbandGroup(friendId, disbandConfirm);
+      if (res.code !== 1000) {
+        message.error(res.msg || "解散群聊失败");
+        return;
+      }
+      await db.deleteConversation(userInfo.id, friendId, chatType);
+      setIsDisbandModalOpen(false);
+      setDisbandConfirm("");
+
+      refreshConversation();
+      setCurrentFriendData({ id: null, nickname: "", avatar: "" });
+      const list: any = await db.getUserConversations(userInfo.id);
+      setCurrentChatList(list);
+    } catch (error) {
+      console.error("解散群聊失败:", error);
+    }
+  };
+
   /**
    * 清空聊天记录
    */
@@ -689,28 +710,30 @@ const ChatInfo = (props: ChatInfoProps) => {
               </Button>
             </Popconfirm>
 
-            <Popconfirm
-              title=""
-              description={
-                chatType === 2
-                  ? groupInfo.ownerId == userInfo.id
-                    ? "是否确定解散群聊？"
-                    : "是否确定退出群聊？"
-                  : "是否确定删除好友？"
-              }
-              onConfirm={handleGroupAction}
-              okText="Yes"
-              cancelText="No"
-            >
-              <Button color="danger" variant="text">
-                {/* 群聊，只有群主能解散 */}
-                {chatType === 2
-                  ? groupInfo.ownerId == userInfo.id
-                    ? "解散群聊"
-                    : "退出群聊"
-                  : "删除好友"}
+            {/* 群聊，只有群主能解散，解散前需输入群名称确认 */}
+            {chatType === 2 && groupInfo.ownerId == userInfo.id ? (
+              <Button
+                color="danger"
+                variant="text"
+                onClick={() => setIsDisbandModalOpen(true)}
+              >
+                解散群聊
               </Button>
-            </Popconfirm>
+            ) : (
+              <Popconfirm
+                title=""
+                description={
+                  chatType === 2 ? "是否确定退出群聊？" : "是否确定删除好友？"
+                }
+                onConfirm={handleGroupAction}
+                okText="Yes"
+                cancelText="No"
+              >
+                <Button color="danger" variant="text">
+                  {chatType === 2 ? "退出群聊" : "删除好友"}
+                </Button>
+              </Popconfirm>
+            )}
           </div>
         </div>
         {/* 群聊相关弹窗 */}
@@ -1017,6 +1040,31 @@ const ChatInfo = (props: ChatInfoProps) => {
       >
         <p>您将自动放弃群主身份</p>
       </Modal>
+
+      {/* 解散群聊确认框 */}
+      <Modal
+        open={isDisbandModalOpen}
+        onOk={handleDisbandGroup}
+        onCancel={() => {
+          setIsDisbandModalOpen(false);
+          setDisbandConfirm("");
+        }}
+        okText="解散"
+        cancelText="取消"
+        okButtonProps={{
+          danger: true,
+          disabled: disbandConfirm !== groupInfo.name,
+        }}
+        title="解散群聊"
+        className="prompt-modal"
+      >
+        <p>解散后所有成员将被移出群聊，请输入群名称「{groupInfo.name}」确认</p>
+        <Input
+          value={disbandConfirm}
+          onChange={(e) => setDisbandConfirm(e.target.value)}
+          placeholder="请输入群名称"
+        />
+      </Modal>
     </div>
   );
 };
@@ -1,7 +1,7 @@
 import "./index.scss";
 import { Button, Form, Input, Checkbox } from "antd";
-import { useState } from "react";
-import { LoginPost, RegisterPost } from "@/api/login";
+import { useEffect, useState } from "react";
+import { LoginPost, RegisterPost, SendVerifyCode } from "@/api/login";
 import { message } from "@/utils/message";
 import { generateNickname } from "@/utils/nickname";
 import { useNavigate } from "react-router-dom";
@@ -13,8 +13,34 @@ const Login = () => {
   const { setUserInfo, setToken } = userStore;
   const [form] = Form.useForm();
   const [login, setLogin] = useState<boolean>(true);
+  const [countdown, setCountdown] = useState<number>(0); // 验证码重发倒计时，秒
   const navigate = useNavigate();
 
+  useEffect(() => {
+    if (countdown <= 0) return;
+    const timer = window.setTimeout(() => setCountdown(countdown - 1), 1000);
+    return () => clearTimeout(timer);
+  }, [countdown]);
+
+  // 发送注册验证码
+  const handleSendCode = async () => {
+    try {
+      await form.validateFields(["phone"]);
+    } catch {
+      return;
+    }
+    const result: any = await SendVerifyCode(
+      form.getFieldValue("phone"),
+      "register"
+    );
+    if (result?.code === 1000) {
+      setCountdown(60);
+      message.success("验证码已发送");
+    } else {
+      message.error(result?.msg);
+    }
+  };
+
   const onFinish = async (value: any) => {
     console.log(value);
     if (!value.deal) {
@@ -57,6 +83,7 @@ const Login = () => {
         phone: value.phone,
         password: value.password,
         nickname: generateNickname(),
+        code: value.code,
       };
       const result: any = await RegisterPost(valueParams);
 
@@ -109,6 +136,22 @@ const Login = () => {
             >
               <Input />
             </Form.Item>
+            {!login && (
+              <Form.Item label="验证码" required>
+                <div style={{ display: "flex", gap: 8 }}>
+                  <Form.Item
+                    name="code"
+                    noStyle
+                    rules={[{ required: true, message: "请输入验证码" }]}
+                  >
+                    <Input />
+                  </Form.Item>
+                  <Button disabled={countdown > 0} onClick={handleSendCode}>
+                    {countdown > 0 ? `${countdown}s 后重发` : "获取验证码"}
+                  </Button>
+                </div>
+              </Form.Item>
+            )}
             <Form.Item
               name="password"
               label="密码"
@@ -14,20 +14,6 @@ export const setHttpNavigate = (navigate: ReturnType<typeof useNavigate>) => {
   _navigate = navigate;
 };
 
-let isRefreshing = false;
-let failedQueue: Array<{
-  resolve: (value: any) => void;
-  reject: (error: any) => void;
-}> = [];
-
-const processQueue = (token?: string) => {
-  failedQueue.forEach((prom) => {
-    console.log(prom, "遍历请求队列");
-    prom.resolve(token);
-  });
-  failedQueue = [];
-};
-
 // 创建 axios 实例
 const service = axios.create({
   // 修改 baseURL
@@ -37,6 +23,62 @@ const service = axios.create({
   timeout: 15000,
 });
 
+// 正在进行的刷新，并发的 http 请求和 websocket 认证共用同一次刷新，
+// 避免同一个 refresh_token 被使用两次而被服务端判定为盗用
+let refreshing: Promise<string> | null = null;
+
+// 刷新失败，清除用户信息并跳转登录
+const expireLogin = () => {
+  userStore.clearToken();
+  userStore.clearUserInfo();
+  localStorage.removeItem("loopToken");
+
+  if (_navigate) {
+    _navigate("/login", {
+      replace: true,
+      state: { from: window.location.pathname },
+    });
+  }
+  message.error("登录已过期，请重新登录"); // 提示用户登录已过期
+};
+
+const doRefresh = async (): Promise<string> => {
+  const { refresh_token } = userStore;
+  if (!refresh_token) {
+    throw new Error("refresh token 不存在");
+  }
+  // 调用刷新token的API
+  const response: any = await RefreshToken(refresh_token);
+
+  // 服务端每次刷新都会轮换 refresh_token，旧的再次使用会被视为盗用
+  const {
+    access_token: newAccessToken,
+    refresh_token: newRefreshToken,
+  } = response.data;
+  userStore.setToken({
+    access_token: newAccessToken,
+    refresh_token: newRefreshToken,
+  });
+  return newAccessToken;
+};
+
+/**
+ * 刷新 access token，返回新的 access token，失败时退出登录
+ */
+export const refreshAccessToken = (): Promise<string> => {
+  if (!refreshing) {
+    refreshing = doRefresh()
+      .catch((refreshError) => {
+        expireLogin();
+        return Promise.reject(refreshError);
+      })
+      .finally(() => {
+        refreshing = null;
+      });
+  }
+  return refreshing;
+};
+
 // 请求拦截器
 service.interceptors.request.use(
   (config: InternalAxiosRequestConfig) => {
@@ -58,72 +100,11 @@ service.interceptors.response.use(
   async (response: AxiosResponse) => {
     if (response.data.code === 1005) {
       const originalRequest: any = response.config;
-
-      if (isRefreshing) {
-        return new Promise((resolve, reject) => {
-          failedQueue.push({ resolve, reject });
-        })
-          .then((token) => {
-            originalRequest.headers.Authorization = `Bearer ${token}`;
-            return service(originalRequest);
-          })
-          .catch((err) => {
-            return Promise.reject(err);
-          });
-      }
-
       originalRequest._retry = true;
-      isRefreshing = true;
-
-      try {
-        const { refresh_token } = userStore;
-        if (!refresh_token) {
-          // 刷新token失败，清除用户信息并跳转登录
-          userStore.clearToken();
-          userStore.clearUserInfo();
-          localStorage.removeItem("loopToken");
-
-          if (_navigate) {
-            _navigate("/login", {
-              replace: true,
-              state: { from: window.location.pathname },
-            });
-          }
-          message.error("登录已过期，请重新登录"); // 提示用户登录已过期
-        }
-
-        // 调用刷新token的API
-        const response: any = await RefreshToken(refresh_token);
-
-        const { access_token: newAccessToken } = response.data;
-        userStore.setToken({
-          access_token: newAccessToken,
-          refresh_token: refresh_token,
-        });
-
-        // 处理队列中的请求
-        processQueue(newAccessToken);
-
-        // 重试原始请求
-        originalRequest.headers.Authorization = `Bearer ${newAccessToken}`;
-        return service(originalRequest);
-      } catch (refreshError) {
-        // 刷新token失败，清除用户信息并跳转登录
-        userStore.clearToken();
-        userStore.clearUserInfo();
-        localStorage.removeItem("loopToken");
-
-        if (_navigate) {
-          _navigate("/login", {
-            replace: true,
-            state: { from: window.location.pathname },
-          });
-        }
-        message.error("登录已过期，请重新登录");
-        return Promise.reject(refreshError);
-      } finally {
-        isRefreshing = false;
-      }
+      const newAccessToken = await refreshAccessToken();
+      // 重试原始请求
+      originalRequest.headers.Authorization = `Bearer ${newAccessToken}`;
+      return service(originalRequest);
     }
     return response.data;
   },
@@ -1,10 +1,16 @@
 // 定义 WebSocket 消息类型
 import userStore from "@/store/user";
+import { refreshAccessToken } from "@/utils/request";
 
 interface WebSocketMessage<T = unknown> {
-  cmd: number; // 消息类型,  0-心跳，1-私聊，2-群聊，3-在线应答
+  cmd: number; // 消息类型,  0-心跳，1-私聊，2-群聊，3-在线应答，102-认证，103-要求重新认证
+  token?: string; // 认证帧携带的 access token
   data: T; // 消息内容（泛型）
 }
+
+const CMD_AUTH = 102;
+const CMD_REAUTH = 103;
+const CLOSE_REPLACED = 4002; // 同一账号在其他地方建立了新连接
 /**
  * data:{
         "seq_id":"",//唯一标识
@@ -132,14 +138,13 @@ class WebSocketClient<T = unknown> {
       this.socket.close();
       this.socket = null;
     }
-    const { access_token } = userStore; // 获取用户信息
-
     try {
-      const url = this.options.url + `?token=${access_token}`;
-      this.socket = new WebSocket(url);
+      this.socket = new WebSocket(this.options.url);
 
       this.socket.onopen = () => {
         console.log("WebSocket 连接成功");
+        // 首帧发送认证，token 不放在 URL 中；重连时 token 可能已过期，先刷新
+        this.sendAuth(this.reconnectCount > 0);
         this.status = "connected";
         this.reconnectCount = 0;
         this.options.onOpen?.();
@@ -164,6 +169,10 @@ class WebSocketClient<T = unknown> {
         this.options.onClose?.(event);
         this.clearTimers();
 
+        // 被新连接替换时不再重连，避免多个页面互相踢下线
+        if (event.code === CLOSE_REPLACED) {
+          return;
+        }
         // 尝试自动重连
         if (
           this.reconnectCount <
@@ -190,6 +199,11 @@ class WebSocketClient<T = unknown> {
 
           // 忽略心跳消息
           if (message.cmd === 0) return;
+          // 服务端要求重新认证
+          if (message.cmd === CMD_REAUTH) {
+            this.sendAuth(true);
+            return;
+          }
           // console.log(message, "心跳包外的数据");
 
           this.lastMessage = message;
@@ -223,6 +237,27 @@ class WebSocketClient<T = unknown> {
     }
   }
 
+  /**
+   * 发送认证帧
+   * @param refresh 是否先刷新 access token，重新认证必须携带新的 token
+   */
+  private async sendAuth(refresh = false): Promise<void> {
+    const socket = this.socket;
+    let { access_token } = userStore;
+    if (refresh) {
+      try {
+        access_token = await refreshAccessToken();
+      } catch (error) {
+        console.error("刷新 token 失败:", error);
+        return;
+      }
+    }
+    if (socket !== this.socket || socket?.readyState !== WebSocket.OPEN) {
+      return;
+    }
+    socket.send(JSON.stringify({ cmd: CMD_AUTH, token: access_token }));
+  }
+
   /**
    * 关闭连接
    */